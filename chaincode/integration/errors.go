@@ -0,0 +1,112 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayError is the structured detail behind a failed transaction, as
+// reported by the Fabric Gateway. Stage is where the transaction failed:
+// "evaluate", "endorse", "submit", "commit status" or "commit". Code is the
+// gRPC status code for all but "commit", which has ValidationCode instead.
+type GatewayError struct {
+	Org            string
+	Stage          string
+	TransactionID  string
+	Code           codes.Code
+	Message        string
+	ValidationCode peer.TxValidationCode
+	Endorsers      []EndorserError
+}
+
+// EndorserError is one peer's reason for failing a transaction.
+type EndorserError struct {
+	Address string
+	MSPID   string
+	Message string
+}
+
+// Error formats the failure with every endorser's message, one per line.
+func (e *GatewayError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s failed", e.Org, e.Stage)
+	if e.TransactionID != "" {
+		fmt.Fprintf(&b, " for transaction %s", e.TransactionID)
+	}
+	if e.Stage == "commit" {
+		fmt.Fprintf(&b, " with validation code %s", e.ValidationCode)
+	} else {
+		fmt.Fprintf(&b, " (%s): %s", e.Code, e.Message)
+	}
+	for _, endorser := range e.Endorsers {
+		fmt.Fprintf(&b, "\n  %s (%s): %s", endorser.Address, endorser.MSPID, endorser.Message)
+	}
+	return b.String()
+}
+
+// GatewayErrorDetails extracts the gateway's detail from an error returned by
+// SubmitTransaction or EvaluateTransaction. It returns false if err did not
+// come from the gateway.
+func (fc *FabricClient) GatewayErrorDetails(err error) (*GatewayError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var commitErr *client.CommitError
+	if errors.As(err, &commitErr) {
+		return &GatewayError{
+			Org:            fc.OrgName,
+			Stage:          "commit",
+			TransactionID:  commitErr.TransactionID,
+			ValidationCode: commitErr.Code,
+		}, true
+	}
+
+	detail := &GatewayError{Org: fc.OrgName}
+
+	var endorseErr *client.EndorseError
+	var submitErr *client.SubmitError
+	var commitStatusErr *client.CommitStatusError
+	switch {
+	case errors.As(err, &endorseErr):
+		detail.Stage = "endorse"
+		detail.TransactionID = endorseErr.TransactionID
+	case errors.As(err, &submitErr):
+		detail.Stage = "submit"
+		detail.TransactionID = submitErr.TransactionID
+	case errors.As(err, &commitStatusErr):
+		detail.Stage = "commit status"
+		detail.TransactionID = commitStatusErr.TransactionID
+	default:
+		// Evaluate errors carry no transaction ID, only the gRPC status.
+		detail.Stage = "evaluate"
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	detail.Code = st.Code()
+	detail.Message = st.Message()
+	for _, d := range st.Details() {
+		if ed, ok := d.(*gateway.ErrorDetail); ok {
+			detail.Endorsers = append(detail.Endorsers, EndorserError{
+				Address: ed.GetAddress(),
+				MSPID:   ed.GetMspId(),
+				Message: ed.GetMessage(),
+			})
+		}
+	}
+	return detail, true
+}
@@ -0,0 +1,62 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestGatewayErrorDetails parses simulated gateway errors without a network.
+func TestGatewayErrorDetails(t *testing.T) {
+	fc := &FabricClient{OrgName: "Org2"}
+
+	t.Run("evaluate failure with peer details", func(t *testing.T) {
+		st, err := status.New(codes.Aborted, "evaluate call to endorser returned error").WithDetails(
+			&gateway.ErrorDetail{Address: "peer0.org1.example.com:7051", MspId: "Org1MSP", Message: "chaincode response 500, validation failed: amount must be > 0"},
+			&gateway.ErrorDetail{Address: "peer0.org2.example.com:9051", MspId: "Org2MSP", Message: "chaincode response 500, validation failed: amount must be > 0"},
+		)
+		require.NoError(t, err)
+		gwErr := fmt.Errorf("CreateCharge: %w", st.Err())
+
+		detail, ok := fc.GatewayErrorDetails(gwErr)
+		require.True(t, ok)
+		assert.Equal(t, "Org2", detail.Org)
+		assert.Equal(t, "evaluate", detail.Stage)
+		assert.Equal(t, codes.Aborted, detail.Code)
+		require.Len(t, detail.Endorsers, 2)
+		assert.Equal(t, "Org1MSP", detail.Endorsers[0].MSPID)
+		assert.Equal(t, "peer0.org1.example.com:7051", detail.Endorsers[0].Address)
+		assert.Contains(t, detail.Endorsers[1].Message, "amount must be > 0")
+		assert.Contains(t, detail.Error(), "peer0.org2.example.com:9051 (Org2MSP)")
+	})
+
+	t.Run("commit failure", func(t *testing.T) {
+		gwErr := &client.CommitError{TransactionID: "tx123", Code: peer.TxValidationCode_MVCC_READ_CONFLICT}
+
+		detail, ok := fc.GatewayErrorDetails(gwErr)
+		require.True(t, ok)
+		assert.Equal(t, "commit", detail.Stage)
+		assert.Equal(t, "tx123", detail.TransactionID)
+		assert.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, detail.ValidationCode)
+		assert.Equal(t, "Org2: commit failed for transaction tx123 with validation code MVCC_READ_CONFLICT", detail.Error())
+	})
+
+	t.Run("not a gateway error", func(t *testing.T) {
+		_, ok := fc.GatewayErrorDetails(errors.New("boom"))
+		assert.False(t, ok)
+		_, ok = fc.GatewayErrorDetails(nil)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// eventReconnectDelay is how long ChaincodeEvents waits before re-opening a
+// dropped event stream.
+const eventReconnectDelay = 2 * time.Second
+
+// EventSubscription delivers chaincode events from FabricClient.ChaincodeEvents.
+// Close it when done to release the underlying stream.
+type EventSubscription struct {
+	events chan *client.ChaincodeEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Events returns the channel events are delivered on. It is closed once the
+// subscription ends, either through Close or cancellation of the parent
+// context.
+func (s *EventSubscription) Events() <-chan *client.ChaincodeEvent {
+	return s.events
+}
+
+// Close stops the subscription and waits for the event stream to shut down.
+// It is safe to call more than once.
+func (s *EventSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// ChaincodeEvents subscribes to events emitted by the client's chaincode,
+// starting at startBlock. A startBlock of 0 starts at the next block committed.
+// If the stream drops it is re-opened after the last event delivered, so no
+// event is delivered twice or skipped.
+func (fc *FabricClient) ChaincodeEvents(ctx context.Context, startBlock uint64) (*EventSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	checkpointer := new(client.InMemoryCheckpointer)
+
+	subscribe := func() (<-chan *client.ChaincodeEvent, error) {
+		var opts []client.ChaincodeEventsOption
+		if startBlock > 0 {
+			opts = append(opts, client.WithStartBlock(startBlock))
+		}
+		// The checkpoint is ignored until an event has been recorded, and
+		// overrides the start block once one has.
+		opts = append(opts, client.WithCheckpoint(checkpointer))
+		return fc.Network.ChaincodeEvents(ctx, fc.Chaincode, opts...)
+	}
+
+	stream, err := subscribe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to subscribe to %s events: %w", fc.Chaincode, err)
+	}
+
+	sub := &EventSubscription{
+		events: make(chan *client.ChaincodeEvent),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(sub.done)
+		defer close(sub.events)
+
+		for {
+			for event := range stream {
+				select {
+				case sub.events <- event:
+					checkpointer.CheckpointChaincodeEvent(event)
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// The stream closed: either we were cancelled or the
+			// connection failed. Reconnect until one or the other.
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(eventReconnectDelay):
+				}
+				if stream, err = subscribe(); err == nil {
+					break
+				}
+			}
+		}
+	}()
+
+	return sub, nil
+}
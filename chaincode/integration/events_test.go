@@ -0,0 +1,73 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChaincodeEvents subscribes to chaincode events and checks that creating
+// a charge delivers a ChargeCreated event.
+func TestChaincodeEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	sub, err := org2Client.ChaincodeEvents(ctx, 0)
+	require.NoError(t, err, "Failed to subscribe to chaincode events")
+	defer sub.Close()
+
+	chargeID := uniqueID("CHG-EVT")
+	charge := map[string]interface{}{
+		"chargeID":        chargeID,
+		"chargeType":      "toll_tag",
+		"recordType":      "TB01",
+		"protocol":        "niop",
+		"awayAgencyID":    "Org2",
+		"homeAgencyID":    "Org1",
+		"tagSerialNumber": "TEST.000000001",
+		"facilityID":      "SR73",
+		"plaza":           "CATALINA",
+		"exitDateTime":    "2026-01-15T08:30:00Z",
+		"vehicleClass":    2,
+		"amount":          4.75,
+		"fee":             0.05,
+		"netAmount":       4.70,
+		"status":          "pending",
+	}
+	chargeJSON, err := json.Marshal(charge)
+	require.NoError(t, err)
+
+	_, err = org2Client.SubmitTransaction("CreateCharge", string(chargeJSON))
+	require.NoError(t, err, "Failed to create charge")
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			require.True(t, ok, "Event stream closed before ChargeCreated was received")
+			if event.EventName != "ChargeCreated" {
+				continue
+			}
+
+			var payload map[string]interface{}
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			if payload["chargeID"] != chargeID {
+				continue
+			}
+
+			assert.Equal(t, org2Client.Chaincode, event.ChaincodeName)
+			assert.Equal(t, "Org2", payload["awayAgencyID"])
+			assert.Equal(t, "Org1", payload["homeAgencyID"])
+			return
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for ChargeCreated event")
+		}
+	}
+}
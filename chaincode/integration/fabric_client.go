@@ -133,36 +133,109 @@ func contractForFunction(fn string) string {
 		"GetAgency":          "AgencyContract",
 		"UpdateAgencyStatus": "AgencyContract",
 		"GetAllAgencies":     "AgencyContract",
+		"GetAgencyCount":     "AgencyContract",
+		"InitLedger":         "AgencyContract",
 		// TagContract
-		"CreateTag":       "TagContract",
-		"GetTag":          "TagContract",
-		"UpdateTagStatus": "TagContract",
-		"GetTagsByAgency": "TagContract",
+		"CreateTag":                 "TagContract",
+		"CreateTags":                "TagContract",
+		"GetTag":                    "TagContract",
+		"UpdateTagStatus":           "TagContract",
+		"UpdateTagsStatusByAccount": "TagContract",
+		"ReportTagLostOrStolen":     "TagContract",
+		"GetTagHistory":             "TagContract",
+		"GetTagsByAgency":           "TagContract",
+		"GetTagsByAgencySorted":     "TagContract",
+		"GetTagsByAccount":          "TagContract",
 		// ChargeContract
-		"CreateCharge":           "ChargeContract",
-		"GetCharge":              "ChargeContract",
-		"UpdateChargeStatus":     "ChargeContract",
-		"GetChargesByAgencyPair": "ChargeContract",
+		"CreateCharge":                  "ChargeContract",
+		"GetCharge":                     "ChargeContract",
+		"UpdateChargeStatus":            "ChargeContract",
+		"DeleteCharge":                  "ChargeContract",
+		"VoidAndReissue":                "ChargeContract",
+		"GetChargeChain":                "ChargeContract",
+		"GetChargeWithCorrections":      "ChargeContract",
+		"GetChargeWithReconciliation":   "ChargeContract",
+		"GetChargeEndorsementOrgs":      "ChargeContract",
+		"PurgeCharge":                   "ChargeContract",
+		"GetChargesByAgencyPair":        "ChargeContract",
+		"GetChargesForAgency":           "ChargeContract",
+		"GetChargeCount":                "ChargeContract",
+		"GetChargeSummary":              "ChargeContract",
+		"GetChargeRedacted":             "ChargeContract",
+		"GetChargesByStatus":            "ChargeContract",
+		"VerifyChargeHash":              "ChargeContract",
+		"GetChargesByStatusSorted":      "ChargeContract",
+		"GetChargesByProtocol":          "ChargeContract",
+		"GetChargesBySubmissionChannel": "ChargeContract",
+		"GetChargesModifiedSince":       "ChargeContract",
+		"GetAgingUnreconciledCharges":   "ChargeContract",
+		"GetChargesByAmountRange":       "ChargeContract",
+		"GetChargesByRole":              "ChargeContract",
+		"GetChargesByPlate":             "ChargeContract",
+		"GetChargesByEntryPlaza":        "ChargeContract",
+		"GetFacilityChargeCount":        "ChargeContract",
+		"GetChargesByIDs":               "ChargeContract",
 		// CorrectionContract
-		"CreateCorrection":       "CorrectionContract",
-		"GetCorrection":          "CorrectionContract",
-		"GetCorrectionsForCharge": "CorrectionContract",
+		"CreateCorrection":             "CorrectionContract",
+		"PreviewCorrectionImpact":      "CorrectionContract",
+		"GetCorrection":                "CorrectionContract",
+		"GetCorrectionsForCharge":      "CorrectionContract",
+		"ReverseCharge":                "CorrectionContract",
+		"GetLatestCorrectionForCharge": "CorrectionContract",
+		"GetCorrectionsByAgencyPair":   "CorrectionContract",
+		"ValidateSequenceContiguity":   "CorrectionContract",
 		// ReconciliationContract
-		"CreateReconciliation":            "ReconciliationContract",
-		"GetReconciliation":               "ReconciliationContract",
-		"GetReconciliationsByAgency":      "ReconciliationContract",
-		"GetReconciliationsByDisposition": "ReconciliationContract",
+		"CreateReconciliation":                  "ReconciliationContract",
+		"GetReconciliation":                     "ReconciliationContract",
+		"GetReconciliationsByChargeIDs":         "ReconciliationContract",
+		"GetCorrectionReconciliation":           "ReconciliationContract",
+		"CreateReconciliationBatch":             "ReconciliationContract",
+		"GetReconciliationBatch":                "ReconciliationContract",
+		"GetReconciliationsByAgency":            "ReconciliationContract",
+		"GetReconciliationsByAgencySorted":      "ReconciliationContract",
+		"GetReconciliationsByAgencyPaged":       "ReconciliationContract",
+		"GetReconciliationsByDisposition":       "ReconciliationContract",
+		"GetReconciliationsByDispositionSorted": "ReconciliationContract",
+		"GetReconciliationsByDispositionPaged":  "ReconciliationContract",
+		"GetFailedReconciliations":              "ReconciliationContract",
 		// AcknowledgementContract
-		"CreateAcknowledgement":              "AcknowledgementContract",
-		"GetAcknowledgement":                 "AcknowledgementContract",
-		"GetAcknowledgementsBySubmissionType": "AcknowledgementContract",
-		"GetAcknowledgementsByReturnCode":    "AcknowledgementContract",
+		"CreateAcknowledgement":                     "AcknowledgementContract",
+		"GetAcknowledgement":                        "AcknowledgementContract",
+		"GetAcknowledgementsBySubmissionType":       "AcknowledgementContract",
+		"GetAcknowledgementsBySubmissionTypeSorted": "AcknowledgementContract",
+		"GetAcknowledgementsByReturnCode":           "AcknowledgementContract",
+		"GetAcknowledgementsByReturnCodeSorted":     "AcknowledgementContract",
+		"AcknowledgeSubmission":                     "AcknowledgementContract",
+		"GetSubmissionSequence":                     "AcknowledgementContract",
 		// SettlementContract
-		"CreateSettlement":           "SettlementContract",
-		"GetSettlement":              "SettlementContract",
-		"UpdateSettlementStatus":     "SettlementContract",
-		"GetSettlementsByAgencyPair": "SettlementContract",
-		"GetSettlementsByStatus":     "SettlementContract",
+		"CreateSettlement":                  "SettlementContract",
+		"GetSettlement":                     "SettlementContract",
+		"UpdateSettlementStatus":            "SettlementContract",
+		"ApproveSettlement":                 "SettlementContract",
+		"RecordSettlementPayment":           "SettlementContract",
+		"SettleWithNoPaymentDue":            "SettlementContract",
+		"GenerateSettlement":                "SettlementContract",
+		"RecomputeSettlement":               "SettlementContract",
+		"GetSettlementsByAgencyPair":        "SettlementContract",
+		"GetSettlementsByStatus":            "SettlementContract",
+		"GetSettlementsByStatusSorted":      "SettlementContract",
+		"GetSettlementsInvolvingAgency":     "SettlementContract",
+		"GetSettlementForPeriod":            "SettlementContract",
+		"GetSettlementHistory":              "SettlementContract",
+		"GetSettlementReconciliationReport": "SettlementContract",
+		// DisputeContract
+		"CreateDispute":        "DisputeContract",
+		"GetDispute":           "DisputeContract",
+		"GetDisputesForEntity": "DisputeContract",
+		"ResolveDispute":       "DisputeContract",
+		// FeeScheduleContract
+		"CreateFeeSchedule": "FeeScheduleContract",
+		"GetFeeSchedule":    "FeeScheduleContract",
+		// MigrationContract
+		"MigrateCollection": "MigrationContract",
+		// PingContract
+		"Ping":             "PingContract",
+		"GetChaincodeInfo": "PingContract",
 	}
 
 	if contract, ok := functionToContract[fn]; ok {
@@ -7,6 +7,7 @@ package integration
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -141,4 +142,14 @@ func TestConnectionHealth(t *testing.T) {
 			t.Fatal("Org4 gateway is nil")
 		}
 	})
+
+	t.Run("Chaincode_Responds", func(t *testing.T) {
+		result, err := org1Client.EvaluateTransaction("Ping")
+		if err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+		if !strings.HasPrefix(string(result), "pong") {
+			t.Fatalf("unexpected Ping response: %q", result)
+		}
+	})
 }
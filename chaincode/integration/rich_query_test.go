@@ -209,19 +209,17 @@ func TestSettlementRichQueries(t *testing.T) {
 			"netAmount":       float64((i+1)*5000 - (i+1)*50),
 			"chargeCount":     (i + 1) * 500,
 			"correctionCount": i * 5,
-			"status":          status,
+			"status":          "draft",
 		}
 		settlementJSON, _ := json.Marshal(settlement)
 		_, err := org1Client.SubmitTransaction("CreateSettlement", string(settlementJSON))
 		require.NoError(t, err)
 
-		// If not draft, update status
+		// New settlements start in draft; move the rest through the workflow
 		if status == "submitted" {
 			settlementID := settlement["settlementID"].(string)
-			// First create as draft, then update to submitted
 			_, err := org1Client.SubmitTransaction("UpdateSettlementStatus", settlementID, "Org1", "Org2", "submitted")
-			// Ignore error since the settlement was created with the target status
-			_ = err
+			require.NoError(t, err)
 		}
 	}
 
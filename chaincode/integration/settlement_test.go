@@ -81,9 +81,9 @@ func TestSettlementLifecycle(t *testing.T) {
 	})
 
 	t.Run("Step5_AcceptedToPaid", func(t *testing.T) {
-		// Payor (Org1) marks as paid after transferring funds
-		_, err := org1Client.SubmitTransaction("UpdateSettlementStatus", settlementID, "Org1", "Org2", "paid")
-		require.NoError(t, err, "Failed to update settlement to paid")
+		// Payor (Org1) records the payment after transferring funds
+		_, err := org1Client.SubmitTransaction("RecordSettlementPayment", settlementID, "Org1", "Org2", "14850.00")
+		require.NoError(t, err, "Failed to record settlement payment")
 
 		// Verify
 		result, err := org1Client.EvaluateTransaction("GetSettlement", settlementID, "Org1", "Org2")
@@ -187,8 +187,8 @@ func TestSettlementValidation(t *testing.T) {
 		_, err := org1Client.SubmitTransaction("CreateSettlement", string(settlementJSON))
 		require.NoError(t, err)
 
-		// Try to go from draft directly to paid (invalid - must go through submitted and accepted)
-		_, err = org1Client.SubmitTransaction("UpdateSettlementStatus", settlementID, "Org1", "Org2", "paid")
+		// Try to go from draft directly to accepted (invalid - must be submitted first)
+		_, err = org1Client.SubmitTransaction("UpdateSettlementStatus", settlementID, "Org1", "Org2", "accepted")
 		require.Error(t, err, "Should reject invalid status transition")
 		assert.Contains(t, err.Error(), "cannot transition")
 	})
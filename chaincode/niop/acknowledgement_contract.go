@@ -18,15 +18,28 @@ type AcknowledgementContract struct {
 
 // CreateAcknowledgement creates a new acknowledgement on the ledger.
 // Returns an error if the acknowledgement already exists or validation fails.
+// With CheckAckReturnMessages enabled, the return message must also agree
+// with the return code.
 func (c *AcknowledgementContract) CreateAcknowledgement(ctx contractapi.TransactionContextInterface, ackJSON string) error {
 	var ack models.Acknowledgement
 	if err := json.Unmarshal([]byte(ackJSON), &ack); err != nil {
 		return fmt.Errorf("failed to parse acknowledgement JSON: %w", err)
 	}
 
-	if err := ack.Validate(); err != nil {
+	return c.createAcknowledgement(ctx, &ack)
+}
+
+// createAcknowledgement validates and stores a new acknowledgement. It holds
+// the checks shared by CreateAcknowledgement and AcknowledgeSubmission.
+func (c *AcknowledgementContract) createAcknowledgement(ctx contractapi.TransactionContextInterface, ack *models.Acknowledgement) error {
+	if err := validateModel(ack); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if CurrentConfig().CheckAckReturnMessages {
+		if err := ack.ValidateReturnMessage(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
 
 	existing, err := ctx.GetStub().GetState(ack.Key())
 	if err != nil {
@@ -46,6 +59,74 @@ func (c *AcknowledgementContract) CreateAcknowledgement(ctx contractapi.Transact
 	return ctx.GetStub().PutState(ack.Key(), bytes)
 }
 
+// AcknowledgeSubmission records fromAgencyID's acknowledgement of a
+// submission from toAgencyID that carried sequenceNumber, and returns it.
+// Each agency numbers the submissions it sends another 1, 2, 3 and so on.
+// The next number in order is acknowledged with return code "00" and becomes
+// the last one seen. A number that repeats or skips one is acknowledged with
+// return code "03" and a message naming the number expected, and the sequence
+// is left where it was so the submitter can resend in order.
+func (c *AcknowledgementContract) AcknowledgeSubmission(ctx contractapi.TransactionContextInterface, acknowledgementID string, submissionType string, fromAgencyID string, toAgencyID string, sequenceNumber int) (*models.Acknowledgement, error) {
+	if sequenceNumber < 1 {
+		return nil, fmt.Errorf("sequenceNumber must be >= 1, got %d", sequenceNumber)
+	}
+
+	seq, err := c.GetSubmissionSequence(ctx, toAgencyID, fromAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ack := &models.Acknowledgement{
+		AcknowledgementID: acknowledgementID,
+		SubmissionType:    submissionType,
+		FromAgencyID:      fromAgencyID,
+		ToAgencyID:        toAgencyID,
+		ReturnCode:        "00",
+		SequenceNumber:    sequenceNumber,
+	}
+	if err := seq.Check(sequenceNumber); err != nil {
+		ack.ReturnCode = "03"
+		ack.ReturnMessage = err.Error()
+	}
+
+	if err := c.createAcknowledgement(ctx, ack); err != nil {
+		return nil, err
+	}
+	if ack.ReturnCode != "00" {
+		return ack, nil
+	}
+
+	seq.LastSequenceNumber = sequenceNumber
+	seq.TouchUpdatedAt()
+	bytes, err := json.Marshal(seq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal submission sequence: %w", err)
+	}
+	if err := ctx.GetStub().PutState(seq.Key(), bytes); err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// GetSubmissionSequence returns the sequence of submissions from
+// submitterAgencyID to receiverAgencyID. Before any submission has been
+// acknowledged it has LastSequenceNumber 0.
+func (c *AcknowledgementContract) GetSubmissionSequence(ctx contractapi.TransactionContextInterface, submitterAgencyID string, receiverAgencyID string) (*models.SubmissionSequence, error) {
+	bytes, err := ctx.GetStub().GetState(models.SubmissionSequenceKey(submitterAgencyID, receiverAgencyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	seq := &models.SubmissionSequence{SubmitterAgencyID: submitterAgencyID, ReceiverAgencyID: receiverAgencyID}
+	if bytes == nil {
+		return seq, nil
+	}
+	if err := decodeDocument("submissionsequence", bytes, seq); err != nil {
+		return nil, fmt.Errorf("failed to parse submission sequence: %w", err)
+	}
+	return seq, nil
+}
+
 // GetAcknowledgement retrieves an acknowledgement by ID.
 func (c *AcknowledgementContract) GetAcknowledgement(ctx contractapi.TransactionContextInterface, acknowledgementID string) (*models.Acknowledgement, error) {
 	key := "ACK_" + acknowledgementID
@@ -58,7 +139,7 @@ func (c *AcknowledgementContract) GetAcknowledgement(ctx contractapi.Transaction
 	}
 
 	var ack models.Acknowledgement
-	if err := json.Unmarshal(bytes, &ack); err != nil {
+	if err := decodeDocument("acknowledgement", bytes, &ack); err != nil {
 		return nil, fmt.Errorf("failed to parse acknowledgement: %w", err)
 	}
 
@@ -68,7 +149,7 @@ func (c *AcknowledgementContract) GetAcknowledgement(ctx contractapi.Transaction
 // GetAcknowledgementsBySubmissionType returns all acknowledgements of a specific type.
 // Uses a CouchDB rich query with index on (docType, submissionType).
 func (c *AcknowledgementContract) GetAcknowledgementsBySubmissionType(ctx contractapi.TransactionContextInterface, submissionType string) ([]*models.Acknowledgement, error) {
-	if !contains(models.ValidSubmissionTypes, submissionType) {
+	if !models.Contains(models.ValidSubmissionTypes, submissionType) {
 		return nil, fmt.Errorf("invalid submissionType %q: must be one of %v", submissionType, models.ValidSubmissionTypes)
 	}
 
@@ -87,7 +168,7 @@ func (c *AcknowledgementContract) GetAcknowledgementsBySubmissionType(ctx contra
 		}
 
 		var ack models.Acknowledgement
-		if err := json.Unmarshal(queryResponse.Value, &ack); err != nil {
+		if err := decodeDocument("acknowledgement", queryResponse.Value, &ack); err != nil {
 			return nil, fmt.Errorf("failed to parse acknowledgement: %w", err)
 		}
 		acks = append(acks, &ack)
@@ -99,7 +180,7 @@ func (c *AcknowledgementContract) GetAcknowledgementsBySubmissionType(ctx contra
 // GetAcknowledgementsByReturnCode returns all acknowledgements with a specific return code.
 // Uses a CouchDB rich query with index on (docType, returnCode).
 func (c *AcknowledgementContract) GetAcknowledgementsByReturnCode(ctx contractapi.TransactionContextInterface, returnCode string) ([]*models.Acknowledgement, error) {
-	if !contains(models.ValidReturnCodes, returnCode) {
+	if !models.Contains(models.ValidReturnCodes, returnCode) {
 		return nil, fmt.Errorf("invalid returnCode %q: must be one of 00-13", returnCode)
 	}
 
@@ -118,7 +199,86 @@ func (c *AcknowledgementContract) GetAcknowledgementsByReturnCode(ctx contractap
 		}
 
 		var ack models.Acknowledgement
-		if err := json.Unmarshal(queryResponse.Value, &ack); err != nil {
+		if err := decodeDocument("acknowledgement", queryResponse.Value, &ack); err != nil {
+			return nil, fmt.Errorf("failed to parse acknowledgement: %w", err)
+		}
+		acks = append(acks, &ack)
+	}
+
+	return acks, nil
+}
+
+// AcknowledgementSortFields lists the acknowledgement fields that
+// GetAcknowledgementsBySubmissionTypeSorted and
+// GetAcknowledgementsByReturnCodeSorted can sort on. Each has a (docType,
+// submissionType, field) and a (docType, returnCode, field) index under
+// META-INF/statedb/couchdb/indexes.
+var AcknowledgementSortFields = []string{"createdAt", "fromAgencyID", "toAgencyID"}
+
+// GetAcknowledgementsBySubmissionTypeSorted returns the acknowledgements of a
+// specific type, sorted ascending by sortField and truncated to limit results.
+// An empty sortField leaves results unsorted; a limit of 0 returns all
+// matches.
+func (c *AcknowledgementContract) GetAcknowledgementsBySubmissionTypeSorted(ctx contractapi.TransactionContextInterface, submissionType string, sortField string, limit int) ([]*models.Acknowledgement, error) {
+	if !models.Contains(models.ValidSubmissionTypes, submissionType) {
+		return nil, fmt.Errorf("invalid submissionType %q: must be one of %v", submissionType, models.ValidSubmissionTypes)
+	}
+	if err := validateSortAndLimit(sortField, AcknowledgementSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("acknowledgement", map[string]interface{}{"submissionType": submissionType}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryAcknowledgements(ctx, query)
+}
+
+// GetAcknowledgementsByReturnCodeSorted returns the acknowledgements with a
+// specific return code, sorted and truncated as
+// GetAcknowledgementsBySubmissionTypeSorted does.
+func (c *AcknowledgementContract) GetAcknowledgementsByReturnCodeSorted(ctx contractapi.TransactionContextInterface, returnCode string, sortField string, limit int) ([]*models.Acknowledgement, error) {
+	if !models.Contains(models.ValidReturnCodes, returnCode) {
+		return nil, fmt.Errorf("invalid returnCode %q: must be one of 00-13", returnCode)
+	}
+	if err := validateSortAndLimit(sortField, AcknowledgementSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("acknowledgement", map[string]interface{}{"returnCode": returnCode}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryAcknowledgements(ctx, query)
+}
+
+// queryAcknowledgements runs a rich query against world state and decodes
+// each result as an acknowledgement. Returns an empty list when nothing
+// matches.
+func queryAcknowledgements(ctx contractapi.TransactionContextInterface, query string) ([]*models.Acknowledgement, error) {
+	resultsIterator, err := ctx.GetStub().GetQueryResult(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	acks := []*models.Acknowledgement{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var ack models.Acknowledgement
+		if err := decodeDocument("acknowledgement", queryResponse.Value, &ack); err != nil {
 			return nil, fmt.Errorf("failed to parse acknowledgement: %w", err)
 		}
 		acks = append(acks, &ack)
@@ -22,6 +22,18 @@ func validAcknowledgement() *models.Acknowledgement {
 	}
 }
 
+// newContextWithAcknowledgements returns a context holding the given
+// acknowledgements.
+func newContextWithAcknowledgements(t *testing.T, acks ...*models.Acknowledgement) *enhancedMockContext {
+	t.Helper()
+	ctx := newMockContext()
+	for _, ack := range acks {
+		ackJSON, _ := json.Marshal(ack)
+		require.NoError(t, (&AcknowledgementContract{}).CreateAcknowledgement(ctx, string(ackJSON)))
+	}
+	return ctx
+}
+
 func TestCreateAcknowledgement(t *testing.T) {
 	contract := &AcknowledgementContract{}
 
@@ -81,6 +93,124 @@ func TestCreateAcknowledgement(t *testing.T) {
 	})
 }
 
+func TestCreateAcknowledgement_CheckReturnMessages(t *testing.T) {
+	contract := &AcknowledgementContract{}
+
+	contradictory := validAcknowledgement()
+	contradictory.ReturnMessage = "Rejected"
+	contradictoryJSON, _ := json.Marshal(contradictory)
+
+	t.Run("lenient by default", func(t *testing.T) {
+		ctx := newMockContext()
+		assert.NoError(t, contract.CreateAcknowledgement(ctx, string(contradictoryJSON)))
+	})
+
+	t.Run("rejects contradictory success when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.CheckAckReturnMessages = true })
+		ctx := newMockContext()
+
+		err := contract.CreateAcknowledgement(ctx, string(contradictoryJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "contradicts success returnCode")
+	})
+
+	t.Run("rejects error code without message when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.CheckAckReturnMessages = true })
+		ctx := newMockContext()
+		ack := validAcknowledgement()
+		ack.ReturnCode = "04"
+		ack.ReturnMessage = ""
+		ackJSON, _ := json.Marshal(ack)
+
+		err := contract.CreateAcknowledgement(ctx, string(ackJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "returnMessage is required")
+	})
+
+	t.Run("accepts well-formed error acknowledgement when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.CheckAckReturnMessages = true })
+		ctx := newMockContext()
+		ack := validAcknowledgement()
+		ack.ReturnCode = "04"
+		ack.ReturnMessage = "Record count mismatch: header 10, body 9"
+		ackJSON, _ := json.Marshal(ack)
+
+		assert.NoError(t, contract.CreateAcknowledgement(ctx, string(ackJSON)))
+	})
+}
+
+func TestAcknowledgeSubmission(t *testing.T) {
+	contract := &AcknowledgementContract{}
+
+	// ORG2 acknowledges transaction files from ORG1.
+	acknowledge := func(t *testing.T, ctx *enhancedMockContext, id string, seq int) *models.Acknowledgement {
+		t.Helper()
+		ack, err := contract.AcknowledgeSubmission(ctx, id, "STRAN", "ORG2", "ORG1", seq)
+		require.NoError(t, err)
+		return ack
+	}
+
+	t.Run("accepts sequence numbers in order", func(t *testing.T) {
+		ctx := newMockContext()
+		assert.Equal(t, "00", acknowledge(t, ctx, "ACK-1", 1).ReturnCode)
+		ack := acknowledge(t, ctx, "ACK-2", 2)
+		assert.Equal(t, "00", ack.ReturnCode)
+		assert.Equal(t, 2, ack.SequenceNumber)
+
+		seq, err := contract.GetSubmissionSequence(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, seq.LastSequenceNumber)
+
+		stored, err := contract.GetAcknowledgement(ctx, "ACK-2")
+		require.NoError(t, err)
+		assert.Equal(t, "00", stored.ReturnCode)
+	})
+
+	t.Run("answers a repeated number with code 03", func(t *testing.T) {
+		ctx := newMockContext()
+		acknowledge(t, ctx, "ACK-1", 1)
+
+		ack := acknowledge(t, ctx, "ACK-1-AGAIN", 1)
+		assert.Equal(t, "03", ack.ReturnCode)
+		assert.Contains(t, ack.ReturnMessage, "repeats")
+
+		seq, err := contract.GetSubmissionSequence(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 1, seq.LastSequenceNumber)
+	})
+
+	t.Run("answers a skipped number with code 03", func(t *testing.T) {
+		ctx := newMockContext()
+		acknowledge(t, ctx, "ACK-1", 1)
+
+		ack := acknowledge(t, ctx, "ACK-3", 3)
+		assert.Equal(t, "03", ack.ReturnCode)
+		assert.Contains(t, ack.ReturnMessage, "expected 2")
+
+		stored, err := contract.GetAcknowledgement(ctx, "ACK-3")
+		require.NoError(t, err)
+		assert.Equal(t, "03", stored.ReturnCode)
+
+		assert.Equal(t, "00", acknowledge(t, ctx, "ACK-2", 2).ReturnCode, "the submitter can resend in order")
+	})
+
+	t.Run("numbers each direction separately", func(t *testing.T) {
+		ctx := newMockContext()
+		acknowledge(t, ctx, "ACK-1", 1)
+
+		ack, err := contract.AcknowledgeSubmission(ctx, "ACK-REV-1", "STRAN", "ORG1", "ORG2", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "00", ack.ReturnCode)
+	})
+
+	t.Run("rejects a sequence number below 1", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.AcknowledgeSubmission(ctx, "ACK-0", "STRAN", "ORG2", "ORG1", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sequenceNumber must be >= 1")
+	})
+}
+
 func TestGetAcknowledgement(t *testing.T) {
 	contract := &AcknowledgementContract{}
 
@@ -170,3 +300,70 @@ func TestGetAcknowledgementsByReturnCode(t *testing.T) {
 		assert.Equal(t, "00", result[0].ReturnCode)
 	})
 }
+
+func TestGetAcknowledgementsSorted(t *testing.T) {
+	contract := &AcknowledgementContract{}
+
+	ack := func(id, submissionType, returnCode, from string) *models.Acknowledgement {
+		a := validAcknowledgement()
+		a.AcknowledgementID = id
+		a.SubmissionType = submissionType
+		a.ReturnCode = returnCode
+		a.FromAgencyID = from
+		return a
+	}
+	acks := []*models.Acknowledgement{
+		ack("ACK-1", "STVL", "00", "ORG3"),
+		ack("ACK-2", "STVL", "06", "ORG1"),
+		ack("ACK-3", "STVL", "00", "ORG5"),
+		ack("ACK-4", "STRAN", "00", "ORG1"),
+	}
+
+	t.Run("sorts by submission type", func(t *testing.T) {
+		ctx := newContextWithAcknowledgements(t, acks...)
+
+		result, err := contract.GetAcknowledgementsBySubmissionTypeSorted(ctx, "STVL", "fromAgencyID", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ACK-2", "ACK-1", "ACK-3"}, acknowledgementIDs(result))
+
+		result, err = contract.GetAcknowledgementsBySubmissionTypeSorted(ctx, "STVL", "fromAgencyID", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ACK-2", "ACK-1"}, acknowledgementIDs(result))
+	})
+
+	t.Run("sorts by return code", func(t *testing.T) {
+		ctx := newContextWithAcknowledgements(t, acks...)
+
+		result, err := contract.GetAcknowledgementsByReturnCodeSorted(ctx, "00", "fromAgencyID", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ACK-4", "ACK-1", "ACK-3"}, acknowledgementIDs(result))
+
+		result, err = contract.GetAcknowledgementsByReturnCodeSorted(ctx, "00", "fromAgencyID", 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ACK-4"}, acknowledgementIDs(result))
+	})
+
+	t.Run("unsorted and unlimited by default", func(t *testing.T) {
+		ctx := newContextWithAcknowledgements(t, acks...)
+
+		result, err := contract.GetAcknowledgementsByReturnCodeSorted(ctx, "06", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ACK-2"}, acknowledgementIDs(result))
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		ctx := newMockContext()
+
+		_, err := contract.GetAcknowledgementsBySubmissionTypeSorted(ctx, "STVL", "returnMessage", 0)
+		assert.ErrorContains(t, err, "invalid sortField")
+
+		_, err = contract.GetAcknowledgementsByReturnCodeSorted(ctx, "00", "createdAt", -1)
+		assert.ErrorContains(t, err, "limit must be >= 0")
+
+		_, err = contract.GetAcknowledgementsBySubmissionTypeSorted(ctx, "INVALID", "", 0)
+		assert.ErrorContains(t, err, "invalid submissionType")
+
+		_, err = contract.GetAcknowledgementsByReturnCodeSorted(ctx, "99", "", 0)
+		assert.ErrorContains(t, err, "invalid returnCode")
+	})
+}
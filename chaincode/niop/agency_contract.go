@@ -5,6 +5,7 @@ package niop
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -17,14 +18,16 @@ type AgencyContract struct {
 }
 
 // CreateAgency creates a new agency on the ledger.
-// Returns an error if the agency already exists or validation fails.
+// Returns an error if the agency already exists or validation fails. When
+// Config.StrictMode is set, an agency with a hubID must name an existing hub
+// that shares one of its consortiums.
 func (c *AgencyContract) CreateAgency(ctx contractapi.TransactionContextInterface, agencyJSON string) error {
 	var agency models.Agency
 	if err := json.Unmarshal([]byte(agencyJSON), &agency); err != nil {
 		return fmt.Errorf("failed to parse agency JSON: %w", err)
 	}
 
-	if err := agency.Validate(); err != nil {
+	if err := validateModel(&agency); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -36,6 +39,16 @@ func (c *AgencyContract) CreateAgency(ctx contractapi.TransactionContextInterfac
 		return fmt.Errorf("agency %s already exists", agency.AgencyID)
 	}
 
+	if CurrentConfig().StrictMode && agency.HubID != "" {
+		hub, err := c.GetAgency(ctx, agency.HubID)
+		if err != nil {
+			return fmt.Errorf("failed to load hub: %w", err)
+		}
+		if err := agency.ValidateHub(hub); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
 	agency.SetTimestamps()
 
 	bytes, err := json.Marshal(agency)
@@ -59,7 +72,7 @@ func (c *AgencyContract) GetAgency(ctx contractapi.TransactionContextInterface,
 	}
 
 	var agency models.Agency
-	if err := json.Unmarshal(bytes, &agency); err != nil {
+	if err := decodeDocument("agency", bytes, &agency); err != nil {
 		return nil, fmt.Errorf("failed to parse agency: %w", err)
 	}
 
@@ -74,7 +87,7 @@ func (c *AgencyContract) UpdateAgencyStatus(ctx contractapi.TransactionContextIn
 		return err
 	}
 
-	if !contains(models.ValidAgencyStatuses, newStatus) {
+	if !models.Contains(models.ValidAgencyStatuses, newStatus) {
 		return fmt.Errorf("invalid status %q: must be one of %v", newStatus, models.ValidAgencyStatuses)
 	}
 
@@ -106,7 +119,7 @@ func (c *AgencyContract) GetAllAgencies(ctx contractapi.TransactionContextInterf
 		}
 
 		var agency models.Agency
-		if err := json.Unmarshal(queryResponse.Value, &agency); err != nil {
+		if err := decodeDocument("agency", queryResponse.Value, &agency); err != nil {
 			return nil, fmt.Errorf("failed to parse agency: %w", err)
 		}
 		agencies = append(agencies, &agency)
@@ -115,12 +128,127 @@ func (c *AgencyContract) GetAllAgencies(ctx contractapi.TransactionContextInterf
 	return agencies, nil
 }
 
-// contains checks if a string is in a slice.
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
+// GetAgencyCount returns the number of agencies on the ledger. It walks the
+// AGENCY_ key range without decoding the values, so the count is exact as of
+// the transaction's snapshot but costs O(n) reads.
+func (c *AgencyContract) GetAgencyCount(ctx contractapi.TransactionContextInterface) (int, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByRange("AGENCY_", "AGENCY_~")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state by range: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		if _, err := resultsIterator.Next(); err != nil {
+			return 0, fmt.Errorf("failed to iterate: %w", err)
 		}
+		count++
+	}
+
+	return count, nil
+}
+
+// DefaultSeedAgencies returns the reference agencies InitLedger creates when
+// no seed data is supplied.
+func DefaultSeedAgencies() []*models.Agency {
+	californiaAgency := func(id string, name string) *models.Agency {
+		return &models.Agency{
+			AgencyID:         id,
+			Name:             name,
+			Consortium:       []string{"WRTO"},
+			State:            "CA",
+			Role:             "toll_operator",
+			ConnectivityMode: "direct",
+			Status:           "active",
+			Capabilities:     []string{"toll"},
+			ProtocolSupport:  []string{"ctoc_rev_a", "niop_2.0"},
+		}
+	}
+	return []*models.Agency{
+		californiaAgency("TCA", "Transportation Corridor Agencies"),
+		californiaAgency("BATA", "Bay Area Toll Authority"),
+		californiaAgency("SANDAG", "San Diego Association of Governments"),
+	}
+}
+
+// InitLedger seeds the ledger with reference agencies so a new network has
+// trading partners to work with. agenciesJSON is a JSON array of agencies; an
+// empty string seeds DefaultSeedAgencies. Agencies that already exist are left
+// untouched, so running it again is a no-op. Every agency is validated before
+// any is written. Returns the number of agencies created.
+//
+// InitLedger then checks that the collection configuration defines a
+// bilateral collection for every pair of agencies, seeded or already on the
+// ledger, and logs a warning naming any that are missing; with
+// Config.RequireCollections set it fails instead.
+func (c *AgencyContract) InitLedger(ctx contractapi.TransactionContextInterface, agenciesJSON string) (int, error) {
+	agencies := DefaultSeedAgencies()
+	if agenciesJSON != "" {
+		agencies = nil
+		if err := json.Unmarshal([]byte(agenciesJSON), &agencies); err != nil {
+			return 0, fmt.Errorf("failed to parse agencies JSON: %w", err)
+		}
+	}
+
+	for i, agency := range agencies {
+		if err := validateModel(agency); err != nil {
+			return 0, fmt.Errorf("agencies[%d]: validation failed: %w", i, err)
+		}
+	}
+
+	created := 0
+	for _, agency := range agencies {
+		existing, err := ctx.GetStub().GetState(agency.Key())
+		if err != nil {
+			return 0, fmt.Errorf("failed to read state: %w", err)
+		}
+		if existing != nil {
+			continue
+		}
+
+		agency.SetTimestamps()
+
+		bytes, err := json.Marshal(agency)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal agency: %w", err)
+		}
+		if err := ctx.GetStub().PutState(agency.Key(), bytes); err != nil {
+			return 0, err
+		}
+		created++
+	}
+
+	if err := c.checkCollections(ctx, agencies); err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+// checkCollections reports the bilateral collections missing for the seeded
+// agencies and those already on the ledger. Agencies written in this
+// transaction are not yet visible to GetAllAgencies, so seeded is passed in.
+func (c *AgencyContract) checkCollections(ctx contractapi.TransactionContextInterface, seeded []*models.Agency) error {
+	existing, err := c.GetAllAgencies(ctx)
+	if err != nil {
+		return err
+	}
+	var agencyIDs []string
+	for _, agency := range append(existing, seeded...) {
+		agencyIDs = append(agencyIDs, agency.AgencyID)
+	}
+
+	missing, err := MissingCollections(ctx, agencyIDs)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if CurrentConfig().RequireCollections {
+		return fmt.Errorf("collection configuration is missing %d bilateral collections: %v", len(missing), missing)
 	}
-	return false
+	log.Printf("Warning: collection configuration is missing %d bilateral collections; charges between these agencies will fail: %v", len(missing), missing)
+	return nil
 }
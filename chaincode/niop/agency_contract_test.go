@@ -3,7 +3,10 @@
 package niop
 
 import (
+	"bytes"
 	"encoding/json"
+	"log"
+	"os"
 	"testing"
 
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -31,6 +34,17 @@ func validAgency() *models.Agency {
 	}
 }
 
+// createAgencies registers an agency for each ID.
+func createAgencies(t *testing.T, ctx *enhancedMockContext, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		agency := validAgency()
+		agency.AgencyID = id
+		agencyJSON, _ := json.Marshal(agency)
+		require.NoError(t, (&AgencyContract{}).CreateAgency(ctx, string(agencyJSON)))
+	}
+}
+
 func TestCreateAgency(t *testing.T) {
 	contract := &AgencyContract{}
 
@@ -104,6 +118,72 @@ func TestCreateAgency(t *testing.T) {
 	})
 }
 
+func TestCreateAgency_HubRouting(t *testing.T) {
+	contract := &AgencyContract{}
+
+	createHub := func(t *testing.T, ctx *enhancedMockContext, role string, consortium []string) {
+		hub := validAgency()
+		hub.AgencyID = "HUB1"
+		hub.Name = "Western Region Hub"
+		hub.Role = role
+		hub.Consortium = consortium
+		hubJSON, _ := json.Marshal(hub)
+		require.NoError(t, contract.CreateAgency(ctx, string(hubJSON)))
+	}
+
+	routedAgency := func() string {
+		agency := validAgency()
+		agency.ConnectivityMode = "hub_routed"
+		agency.HubID = "HUB1"
+		agencyJSON, _ := json.Marshal(agency)
+		return string(agencyJSON)
+	}
+
+	t.Run("accepts hub sharing a consortium", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createHub(t, ctx, "hub", []string{"WRTO"})
+
+		require.NoError(t, contract.CreateAgency(ctx, routedAgency()))
+	})
+
+	t.Run("rejects hub in another consortium", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createHub(t, ctx, "hub", []string{"EZIOP"})
+
+		err := contract.CreateAgency(ctx, routedAgency())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "shares no consortium with hub HUB1")
+	})
+
+	t.Run("rejects hubID that is not a hub", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createHub(t, ctx, "toll_operator", []string{"WRTO"})
+
+		err := contract.CreateAgency(ctx, routedAgency())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not hub")
+	})
+
+	t.Run("rejects unknown hub", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+
+		err := contract.CreateAgency(ctx, routedAgency())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load hub")
+	})
+
+	t.Run("skips hub lookup outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newMockContext()
+
+		require.NoError(t, contract.CreateAgency(ctx, routedAgency()))
+	})
+}
+
 func TestGetAgency(t *testing.T) {
 	contract := &AgencyContract{}
 
@@ -197,3 +277,160 @@ func TestGetAllAgencies(t *testing.T) {
 		assert.Len(t, result, 2)
 	})
 }
+
+func TestGetAgencyCount(t *testing.T) {
+	contract := &AgencyContract{}
+	ctx := newMockContext()
+
+	count, err := contract.GetAgencyCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	for _, id := range []string{"ORG1", "ORG2", "ORG3"} {
+		agency := validAgency()
+		agency.AgencyID = id
+		agencyJSON, _ := json.Marshal(agency)
+		require.NoError(t, contract.CreateAgency(ctx, string(agencyJSON)))
+	}
+
+	count, err = contract.GetAgencyCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestInitLedger(t *testing.T) {
+	contract := &AgencyContract{}
+
+	t.Run("seeds reference agencies once", func(t *testing.T) {
+		ctx := newMockContext()
+
+		created, err := contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 3, created)
+
+		for _, id := range []string{"TCA", "BATA", "SANDAG"} {
+			agency, err := contract.GetAgency(ctx, id)
+			require.NoError(t, err, id)
+			assert.Equal(t, "active", agency.Status)
+			assert.NotEmpty(t, agency.CreatedAt)
+		}
+
+		created, err = contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, created, "second run should be a no-op")
+
+		agencies, err := contract.GetAllAgencies(ctx)
+		require.NoError(t, err)
+		assert.Len(t, agencies, 3)
+	})
+
+	t.Run("leaves existing agencies untouched", func(t *testing.T) {
+		ctx := newMockContext()
+		existing := validAgency()
+		existing.AgencyID = "TCA"
+		existing.Status = "suspended"
+		existingJSON, _ := json.Marshal(existing)
+		require.NoError(t, contract.CreateAgency(ctx, string(existingJSON)))
+
+		created, err := contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, created)
+
+		agency, err := contract.GetAgency(ctx, "TCA")
+		require.NoError(t, err)
+		assert.Equal(t, "suspended", agency.Status)
+	})
+
+	t.Run("seeds supplied agencies instead of defaults", func(t *testing.T) {
+		ctx := newMockContext()
+		agenciesJSON, _ := json.Marshal([]*models.Agency{validAgency()})
+
+		created, err := contract.InitLedger(ctx, string(agenciesJSON))
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		_, err = contract.GetAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		_, err = contract.GetAgency(ctx, "TCA")
+		require.Error(t, err)
+	})
+
+	t.Run("writes nothing when any agency is invalid", func(t *testing.T) {
+		ctx := newMockContext()
+		invalid := validAgency()
+		invalid.AgencyID = "ORG2"
+		invalid.Role = "bogus"
+		agenciesJSON, _ := json.Marshal([]*models.Agency{validAgency(), invalid})
+
+		_, err := contract.InitLedger(ctx, string(agenciesJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "agencies[1]: validation failed")
+
+		agencies, err := contract.GetAllAgencies(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, agencies)
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.InitLedger(ctx, "not json")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse agencies JSON")
+	})
+}
+
+func TestInitLedger_CollectionCheck(t *testing.T) {
+	contract := &AgencyContract{}
+
+	setup := func(t *testing.T) (*enhancedMockContext, *bytes.Buffer) {
+		ctx := newMockContext()
+		ctx.stub.collectionErrors = map[string]error{
+			"charges_BATA_SANDAG": undefinedCollectionError("charges_BATA_SANDAG"),
+		}
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		t.Cleanup(func() { log.SetOutput(os.Stderr) })
+		return ctx, &logs
+	}
+
+	t.Run("warns about missing collections by default", func(t *testing.T) {
+		ctx, logs := setup(t)
+
+		created, err := contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 3, created)
+		assert.Contains(t, logs.String(), "missing 1 bilateral collections")
+		assert.Contains(t, logs.String(), "charges_BATA_SANDAG")
+	})
+
+	t.Run("includes agencies already on the ledger", func(t *testing.T) {
+		ctx, logs := setup(t)
+		ctx.stub.collectionErrors["charges_ORG1_TCA"] = undefinedCollectionError("charges_ORG1_TCA")
+		agencyJSON, _ := json.Marshal(validAgency())
+		require.NoError(t, contract.CreateAgency(ctx, string(agencyJSON)))
+
+		_, err := contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Contains(t, logs.String(), "[charges_BATA_SANDAG charges_ORG1_TCA]")
+	})
+
+	t.Run("fails when collections are required", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RequireCollections = true })
+		ctx, _ := setup(t)
+
+		_, err := contract.InitLedger(ctx, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "collection configuration is missing 1 bilateral collections: [charges_BATA_SANDAG]")
+	})
+
+	t.Run("is silent when every collection is defined", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RequireCollections = true })
+		ctx, logs := setup(t)
+		ctx.stub.collectionErrors = nil
+
+		_, err := contract.InitLedger(ctx, "")
+		require.NoError(t, err)
+		assert.Empty(t, logs.String())
+	})
+}
@@ -3,8 +3,15 @@
 package niop
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -17,20 +24,87 @@ type ChargeContract struct {
 }
 
 // CreateCharge creates a new charge on the ledger.
-// The charge is stored in a private data collection named charges_{A}_{B}
-// where A and B are alphabetically sorted agency IDs.
+// The charge is stored in a private data collection named charges_{A}_{B} where
+// A and B are alphabetically sorted agency IDs. When Config.ComputeChargeFees
+// is set, the fee and net amount come from the agencies' fee schedule for the
+// charge type. When Config.RequirePayByPlatePlates is set, pay-by-plate charges
+// must carry a plate. When Config.StrictMode is set, a charge with a
+// tagSerialNumber must name a tag on the ledger whose class agrees with its
+// vehicleClass and whose protocol its own protocol accepts. New charges start
+// in "pending"; an empty status defaults to it and any other status is
+// rejected. Plate fields are stored upper case. Emits a "ChargeCreated" event.
 func (c *ChargeContract) CreateCharge(ctx contractapi.TransactionContextInterface, chargeJSON string) error {
 	var charge models.Charge
 	if err := json.Unmarshal([]byte(chargeJSON), &charge); err != nil {
 		return fmt.Errorf("failed to parse charge JSON: %w", err)
 	}
 
-	if err := charge.Validate(); err != nil {
+	if err := prepareCharge(ctx, &charge); err != nil {
+		return err
+	}
+
+	if err := adjustChargeCount(ctx, charge.CollectionName(), 1); err != nil {
+		return err
+	}
+	if err := putCharge(ctx, &charge, ""); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(ChargeCreatedEvent{
+		ChargeID:     charge.ChargeID,
+		AwayAgencyID: charge.AwayAgencyID,
+		HomeAgencyID: charge.HomeAgencyID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return ctx.GetStub().SetEvent("ChargeCreated", payload)
+}
+
+// ChargeCreatedEvent is the payload of the "ChargeCreated" chaincode event.
+// Charges are private data, so it carries only the IDs needed to read the
+// charge from its collection.
+type ChargeCreatedEvent struct {
+	ChargeID     string `json:"chargeID"`
+	AwayAgencyID string `json:"awayAgencyID"`
+	HomeAgencyID string `json:"homeAgencyID"`
+}
+
+// prepareCharge applies the checks and defaults CreateCharge gives a new
+// charge, without writing anything. It holds the steps shared by
+// CreateCharge and VoidAndReissue.
+func prepareCharge(ctx contractapi.TransactionContextInterface, charge *models.Charge) error {
+	if charge.Status == "" {
+		charge.Status = "pending"
+	}
+	if charge.Status != "pending" {
+		return fmt.Errorf("new charges must start in pending, got status %q", charge.Status)
+	}
+	charge.NormalizePlate()
+
+	if err := validateModel(charge); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if CurrentConfig().RequirePayByPlatePlates {
+		if err := charge.ValidatePayByPlate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	if CurrentConfig().StrictMode && charge.TagSerialNumber != "" {
+		tag, err := (&TagContract{}).GetTag(ctx, charge.TagSerialNumber)
+		if err != nil {
+			return fmt.Errorf("failed to load tag: %w", err)
+		}
+		if err := charge.ValidateTagClass(tag); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := charge.ValidateTagProtocol(tag); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
 
-	collection := charge.CollectionName()
-	existing, err := ctx.GetStub().GetPrivateData(collection, charge.Key())
+	existing, err := ctx.GetStub().GetPrivateData(charge.CollectionName(), charge.Key())
 	if err != nil {
 		return fmt.Errorf("failed to read private data: %w", err)
 	}
@@ -38,46 +112,342 @@ func (c *ChargeContract) CreateCharge(ctx contractapi.TransactionContextInterfac
 		return fmt.Errorf("charge %s already exists", charge.ChargeID)
 	}
 
-	charge.SetCreatedAt()
+	if CurrentConfig().ComputeChargeFees {
+		if err := applyFeeSchedule(ctx, charge); err != nil {
+			return err
+		}
+	}
 
-	bytes, err := json.Marshal(charge)
+	now, err := txTime(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal charge: %w", err)
+		return err
+	}
+	charge.SetCreatedAt(now)
+	return nil
+}
+
+// ChargeReissuedEvent is the payload of the "ChargeReissued" chaincode event.
+type ChargeReissuedEvent struct {
+	VoidedChargeID string `json:"voidedChargeID"`
+	NewChargeID    string `json:"newChargeID"`
+	AwayAgencyID   string `json:"awayAgencyID"`
+	HomeAgencyID   string `json:"homeAgencyID"`
+}
+
+// VoidAndReissue replaces a charge submitted with wrong data in one
+// transaction: the old charge is soft-deleted as DeleteCharge would, and the
+// new charge is created as CreateCharge would. The two are linked through the
+// new charge's ReplacesChargeID and the old charge's SupersededByChargeID.
+// Only pending and rejected charges can be voided, and the new charge must
+// have a new chargeID and the same agencies. Emits a "ChargeReissued" event
+// in place of "ChargeDeleted".
+func (c *ChargeContract) VoidAndReissue(ctx contractapi.TransactionContextInterface, oldChargeID string, awayAgencyID string, homeAgencyID string, newChargeJSON string) (*models.Charge, error) {
+	old, err := c.GetCharge(ctx, oldChargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var charge models.Charge
+	if err := json.Unmarshal([]byte(newChargeJSON), &charge); err != nil {
+		return nil, fmt.Errorf("failed to parse charge JSON: %w", err)
+	}
+	if charge.ChargeID == old.ChargeID {
+		return nil, fmt.Errorf("reissued charge needs a new chargeID: %s is being voided", old.ChargeID)
+	}
+	if charge.AwayAgencyID != old.AwayAgencyID || charge.HomeAgencyID != old.HomeAgencyID {
+		return nil, fmt.Errorf("reissued charge must keep awayAgencyID %s and homeAgencyID %s", old.AwayAgencyID, old.HomeAgencyID)
 	}
+	charge.ReplacesChargeID = old.ChargeID
 
-	return ctx.GetStub().PutPrivateData(collection, charge.Key(), bytes)
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := old.MarkDeleted("voided and reissued as "+charge.ChargeID, now); err != nil {
+		return nil, fmt.Errorf("cannot void charge %s: %w", old.ChargeID, err)
+	}
+	old.SupersededByChargeID = charge.ChargeID
+	if err := prepareCharge(ctx, &charge); err != nil {
+		return nil, err
+	}
+
+	// One charge leaves the count and one joins it in the same collection,
+	// so the charge counter is left as it is.
+	if err := putCharge(ctx, old, old.Status); err != nil {
+		return nil, err
+	}
+	if err := putCharge(ctx, &charge, ""); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(ChargeReissuedEvent{
+		VoidedChargeID: old.ChargeID,
+		NewChargeID:    charge.ChargeID,
+		AwayAgencyID:   charge.AwayAgencyID,
+		HomeAgencyID:   charge.HomeAgencyID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := ctx.GetStub().SetEvent("ChargeReissued", payload); err != nil {
+		return nil, err
+	}
+
+	return &charge, nil
 }
 
 // GetCharge retrieves a charge by ID.
 // Requires knowing both agency IDs to determine the collection name.
 func (c *ChargeContract) GetCharge(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) (*models.Charge, error) {
-	// Determine collection name using alphabetical sort
-	a, b := awayAgencyID, homeAgencyID
-	if a > b {
-		a, b = b, a
+	charge, err := findCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if charge == nil {
+		return nil, fmt.Errorf("charge %s not found in collection %s", chargeID, models.BilateralCollectionName(awayAgencyID, homeAgencyID))
+	}
+
+	return charge, nil
+}
+
+// ChargeWithCorrections is the result of GetChargeWithCorrections.
+// EffectiveAmount is the charge amount with its corrections applied, rounded
+// to the cent.
+type ChargeWithCorrections struct {
+	Charge          *models.Charge       `json:"charge"`
+	Corrections     []*models.Correction `json:"corrections"`
+	EffectiveAmount float64              `json:"effectiveAmount"`
+}
+
+// GetChargeWithCorrections returns a charge together with its corrections,
+// in sequence order, and the amount they bring it to.
+func (c *ChargeContract) GetChargeWithCorrections(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) (*ChargeWithCorrections, error) {
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	corrections, err := (&CorrectionContract{}).GetCorrectionsForCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if corrections == nil {
+		corrections = []*models.Correction{}
+	}
+
+	return &ChargeWithCorrections{
+		Charge:          charge,
+		Corrections:     corrections,
+		EffectiveAmount: math.Round(models.AdjustedAmount(charge.Amount, corrections)*100) / 100,
+	}, nil
+}
+
+// ChargeWithReconciliation is the result of GetChargeWithReconciliation.
+// Reconciliation is omitted until the home agency has reconciled the charge.
+type ChargeWithReconciliation struct {
+	Charge         *models.Charge         `json:"charge"`
+	Reconciliation *models.Reconciliation `json:"reconciliation,omitempty"`
+	IsPosted       bool                   `json:"isPosted"`
+}
+
+// GetChargeWithReconciliation returns a charge, read from its bilateral
+// collection, together with its charge-level reconciliation from world state.
+// IsPosted is true if the reconciliation reports the charge as posted.
+func (c *ChargeContract) GetChargeWithReconciliation(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) (*ChargeWithReconciliation, error) {
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	recon, err := readChargeReconciliation(ctx, chargeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChargeWithReconciliation{
+		Charge:         charge,
+		Reconciliation: recon,
+		IsPosted:       recon != nil && recon.IsPosted(),
+	}, nil
+}
+
+// GetChargeChain returns the chain of reissues chargeID belongs to, oldest
+// first: the charges it replaces, reached through ReplacesChargeID, then the
+// charge itself and the charges superseding it, reached through
+// SupersededByChargeID. A charge that was never reissued is a chain of one.
+// Returns an error if a link names a charge that is missing or if the links
+// loop.
+func (c *ChargeContract) GetChargeChain(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) ([]*models.Charge, error) {
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{charge.ChargeID: true}
+	follow := func(id string) (*models.Charge, error) {
+		if seen[id] {
+			return nil, fmt.Errorf("charge chain of %s loops at %s", chargeID, id)
+		}
+		seen[id] = true
+		return c.GetCharge(ctx, id, awayAgencyID, homeAgencyID)
+	}
+
+	var earlier []*models.Charge
+	for current := charge; current.ReplacesChargeID != ""; {
+		current, err = follow(current.ReplacesChargeID)
+		if err != nil {
+			return nil, err
+		}
+		earlier = append(earlier, current)
+	}
+
+	chain := make([]*models.Charge, 0, len(earlier)+1)
+	for i := len(earlier) - 1; i >= 0; i-- {
+		chain = append(chain, earlier[i])
+	}
+	chain = append(chain, charge)
+	for current := charge; current.SupersededByChargeID != ""; {
+		current, err = follow(current.SupersededByChargeID)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, current)
+	}
+
+	return chain, nil
+}
+
+// GetChargeRedacted retrieves a charge with its PII fields masked, for
+// consumers such as audit dashboards that need charge details without the
+// customer's plate or full tag number. GetCharge returns the full record.
+func (c *ChargeContract) GetChargeRedacted(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) (*models.Charge, error) {
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	return charge.Redacted(), nil
+}
+
+// VerifyChargeHash checks that the charge stored in this peer's private
+// collection matches the hash committed to the public ledger. It returns
+// false if the local copy has been altered or is out of sync.
+func (c *ChargeContract) VerifyChargeHash(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) (bool, error) {
+	collection := models.BilateralCollectionName(awayAgencyID, homeAgencyID)
+	key := models.ChargeKey(chargeID)
+
+	onChain, err := ctx.GetStub().GetPrivateDataHash(collection, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to read private data hash: %w", err)
+	}
+	if onChain == nil {
+		return false, fmt.Errorf("charge %s not found in collection %s", chargeID, collection)
 	}
-	collection := "charges_" + a + "_" + b
-	key := "CHARGE_" + chargeID
+
+	local, err := ctx.GetStub().GetPrivateData(collection, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to read private data: %w", err)
+	}
+	if local == nil {
+		return false, nil
+	}
+
+	localHash := sha256.Sum256(local)
+	return bytes.Equal(onChain, localHash[:]), nil
+}
+
+// findCharge reads a charge from the bilateral collection of the two agencies,
+// in either order. It returns nil without an error if the charge does not exist.
+func findCharge(ctx contractapi.TransactionContextInterface, chargeID string, agencyA string, agencyB string) (*models.Charge, error) {
+	collection := models.BilateralCollectionName(agencyA, agencyB)
+	key := models.ChargeKey(chargeID)
 
 	bytes, err := ctx.GetStub().GetPrivateData(collection, key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read private data: %w", err)
 	}
 	if bytes == nil {
-		return nil, fmt.Errorf("charge %s not found in collection %s", chargeID, collection)
+		return nil, nil
 	}
 
 	var charge models.Charge
-	if err := json.Unmarshal(bytes, &charge); err != nil {
+	if err := decodeDocument("charge", bytes, &charge); err != nil {
 		return nil, fmt.Errorf("failed to parse charge: %w", err)
 	}
 
 	return &charge, nil
 }
 
+// chargeByStatusIndex is the composite key object type indexing charges by
+// status within a bilateral collection.
+const chargeByStatusIndex = "chargeByStatus"
+
+// putCharge writes a charge to its collection and keeps its chargeByStatus
+// index entry in step. previousStatus is the status the charge is currently
+// stored with, or "" for a new charge; its index entry is removed when the
+// status has changed. All charge writes go through here, and each one sets the
+// charge's UpdatedAt to the transaction timestamp. A new charge for a pair
+// flagged by SetDualEndorsementPairs also gets a key-level endorsement policy
+// requiring both agencies, as do its index entries and the collection's charge
+// counter.
+func putCharge(ctx contractapi.TransactionContextInterface, charge *models.Charge, previousStatus string) error {
+	collection := charge.CollectionName()
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	charge.TouchUpdatedAt(now)
+	bytes, err := json.Marshal(charge)
+	if err != nil {
+		return fmt.Errorf("failed to marshal charge: %w", err)
+	}
+	if err := ctx.GetStub().PutPrivateData(collection, charge.Key(), bytes); err != nil {
+		return err
+	}
+	if previousStatus == "" && requiresDualEndorsement(charge) {
+		for _, key := range []string{charge.Key(), chargeCountKey} {
+			if err := setChargeEndorsementPolicy(ctx, charge, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if previousStatus == charge.Status {
+		return nil
+	}
+	if previousStatus != "" {
+		oldKey, err := ctx.GetStub().CreateCompositeKey(chargeByStatusIndex, []string{previousStatus, charge.ChargeID})
+		if err != nil {
+			return fmt.Errorf("failed to create index key: %w", err)
+		}
+		if err := ctx.GetStub().DelPrivateData(collection, oldKey); err != nil {
+			return fmt.Errorf("failed to remove index entry: %w", err)
+		}
+	}
+	return putChargeStatusIndex(ctx, collection, charge)
+}
+
+// putChargeStatusIndex writes the chargeByStatus entry for a charge's current
+// status, with the charge's key-level endorsement policy when its pair needs
+// both agencies.
+func putChargeStatusIndex(ctx contractapi.TransactionContextInterface, collection string, charge *models.Charge) error {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(chargeByStatusIndex, []string{charge.Status, charge.ChargeID})
+	if err != nil {
+		return fmt.Errorf("failed to create index key: %w", err)
+	}
+	if err := ctx.GetStub().PutPrivateData(collection, indexKey, []byte{0x00}); err != nil {
+		return err
+	}
+	if requiresDualEndorsement(charge) {
+		return setChargeEndorsementPolicy(ctx, charge, indexKey)
+	}
+	return nil
+}
+
 // UpdateChargeStatus updates the status of an existing charge.
 // Valid transitions: pending->posted/rejected, posted->disputed/settled,
-// disputed->posted/settled, rejected->pending.
+// disputed->posted/settled, rejected->pending. Charges included in a paid
+// settlement cannot change status.
 func (c *ChargeContract) UpdateChargeStatus(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string, newStatus string) error {
 	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
 	if err != nil {
@@ -88,32 +458,464 @@ func (c *ChargeContract) UpdateChargeStatus(ctx contractapi.TransactionContextIn
 		return fmt.Errorf("invalid status transition: %w", err)
 	}
 
+	// Money for a charge in a paid settlement has already moved, so the
+	// charge is frozen regardless of what its own status would allow.
+	paid, err := (&SettlementContract{}).GetSettlementsByStatus(ctx, awayAgencyID, homeAgencyID, "paid")
+	if err != nil {
+		return fmt.Errorf("failed to check settlements: %w", err)
+	}
+	for _, settlement := range paid {
+		if settlement.IncludesCharge(chargeID) {
+			return fmt.Errorf("charge %s is included in paid settlement %s", chargeID, settlement.SettlementID)
+		}
+	}
+
+	previousStatus := charge.Status
 	charge.Status = newStatus
 
-	bytes, err := json.Marshal(charge)
+	return putCharge(ctx, charge, previousStatus)
+}
+
+// ChargeDeletedEvent is the payload of the "ChargeDeleted" chaincode event.
+type ChargeDeletedEvent struct {
+	ChargeID     string `json:"chargeID"`
+	AwayAgencyID string `json:"awayAgencyID"`
+	HomeAgencyID string `json:"homeAgencyID"`
+	Reason       string `json:"reason"`
+}
+
+// DeleteCharge soft-deletes a charge entered in error. The charge stays in
+// its collection for audit, marked deleted with the reason given, and is
+// left out of the list queries; GetCharge still returns it. Only pending and
+// rejected charges can be deleted. Emits a "ChargeDeleted" event.
+func (c *ChargeContract) DeleteCharge(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string, reason string) error {
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal charge: %w", err)
+		return err
+	}
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if err := charge.MarkDeleted(reason, now); err != nil {
+		return fmt.Errorf("cannot delete charge %s: %w", chargeID, err)
+	}
+
+	if err := adjustChargeCount(ctx, charge.CollectionName(), -1); err != nil {
+		return err
+	}
+	if err := putCharge(ctx, charge, charge.Status); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(ChargeDeletedEvent{
+		ChargeID:     charge.ChargeID,
+		AwayAgencyID: charge.AwayAgencyID,
+		HomeAgencyID: charge.HomeAgencyID,
+		Reason:       reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	return ctx.GetStub().PutPrivateData(charge.CollectionName(), charge.Key(), bytes)
+	return ctx.GetStub().SetEvent("ChargeDeleted", payload)
+}
+
+// ChargePurgedEvent is the payload of the "ChargePurged" chaincode event.
+type ChargePurgedEvent struct {
+	ChargeID     string `json:"chargeID"`
+	AwayAgencyID string `json:"awayAgencyID"`
+	HomeAgencyID string `json:"homeAgencyID"`
+	ExitDateTime string `json:"exitDateTime"`
+}
+
+// PurgeCharge permanently removes a settled charge whose exit date is at
+// least Config.ChargeRetentionDays before the transaction time, for
+// retention limits that require the data to be destroyed. Unlike
+// DeleteCharge, the charge and its history are purged from every peer
+// holding the collection. Emits a "ChargePurged" event as the audit record.
+func (c *ChargeContract) PurgeCharge(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) error {
+	retentionDays := CurrentConfig().ChargeRetentionDays
+	if retentionDays == 0 {
+		return fmt.Errorf("charge purging is disabled: no retention period is configured")
+	}
+
+	charge, err := c.GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return err
+	}
+	if charge.Status != "settled" {
+		return fmt.Errorf("cannot purge charge %s in status %q: only settled charges can be purged", chargeID, charge.Status)
+	}
+
+	exitTime, err := time.Parse(time.RFC3339, charge.ExitDateTime)
+	if err != nil {
+		return fmt.Errorf("cannot purge charge %s: invalid exitDateTime %q", chargeID, charge.ExitDateTime)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	purgeableFrom := exitTime.AddDate(0, 0, retentionDays)
+	if now.Before(purgeableFrom) {
+		return fmt.Errorf("cannot purge charge %s before %s: retention period is %d days", chargeID, purgeableFrom.UTC().Format(time.RFC3339), retentionDays)
+	}
+
+	collection := charge.CollectionName()
+	if err := adjustChargeCount(ctx, collection, -1); err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PurgePrivateData(collection, charge.Key()); err != nil {
+		return fmt.Errorf("failed to purge charge: %w", err)
+	}
+	indexKey, err := ctx.GetStub().CreateCompositeKey(chargeByStatusIndex, []string{charge.Status, charge.ChargeID})
+	if err != nil {
+		return fmt.Errorf("failed to create index key: %w", err)
+	}
+	if err := ctx.GetStub().PurgePrivateData(collection, indexKey); err != nil {
+		return fmt.Errorf("failed to purge index entry: %w", err)
+	}
+
+	payload, err := json.Marshal(ChargePurgedEvent{
+		ChargeID:     charge.ChargeID,
+		AwayAgencyID: charge.AwayAgencyID,
+		HomeAgencyID: charge.HomeAgencyID,
+		ExitDateTime: charge.ExitDateTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return ctx.GetStub().SetEvent("ChargePurged", payload)
 }
 
 // GetChargesByAgencyPair returns all charges between two agencies.
-// This performs a range scan on the bilateral collection.
+// This performs a range scan on the bilateral collection. Deleted charges
+// are skipped.
 func (c *ChargeContract) GetChargesByAgencyPair(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string) ([]*models.Charge, error) {
-	// Determine collection name using alphabetical sort
-	a, b := agencyA, agencyB
-	if a > b {
-		a, b = b, a
+	collection := models.BilateralCollectionName(agencyA, agencyB)
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collection, "CHARGE_", "CHARGE_~")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private data by range: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	var charges []*models.Charge
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var charge models.Charge
+		if err := decodeDocument("charge", queryResponse.Value, &charge); err != nil {
+			return nil, fmt.Errorf("failed to parse charge: %w", err)
+		}
+		if charge.Deleted {
+			continue
+		}
+		charges = append(charges, &charge)
+	}
+
+	return charges, nil
+}
+
+// GetChargesForAgency returns agencyID's charges with each of its partners,
+// merged in partner order. partnerIDsJSON is a JSON array of partner agency
+// IDs; an empty string uses every other agency on the ledger. A non-empty
+// status limits the result to charges in that status, as GetChargesByStatus
+// does. Deleted charges are skipped.
+//
+// Each partner's charges live in a separate bilateral collection, and a peer
+// can only read the collections its organization is a member of. The call
+// fails if any partner's collection is not readable on the evaluating peer, so
+// callers should pass the partners they actually exchange charges with.
+func (c *ChargeContract) GetChargesForAgency(ctx contractapi.TransactionContextInterface, agencyID string, partnerIDsJSON string, status string) ([]*models.Charge, error) {
+	if err := models.ValidateAgencyID("agencyID", agencyID); err != nil {
+		return nil, err
+	}
+
+	var partnerIDs []string
+	if partnerIDsJSON != "" {
+		if err := json.Unmarshal([]byte(partnerIDsJSON), &partnerIDs); err != nil {
+			return nil, fmt.Errorf("failed to parse partner IDs JSON: %w", err)
+		}
+		if err := checkBatchSize(len(partnerIDs)); err != nil {
+			return nil, err
+		}
+	} else {
+		agencies, err := (&AgencyContract{}).GetAllAgencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, agency := range agencies {
+			if agency.AgencyID != agencyID {
+				partnerIDs = append(partnerIDs, agency.AgencyID)
+			}
+		}
+	}
+
+	charges := []*models.Charge{}
+	seen := make(map[string]bool, len(partnerIDs))
+	for _, partnerID := range partnerIDs {
+		if partnerID == agencyID {
+			return nil, fmt.Errorf("agency %s cannot be its own partner", agencyID)
+		}
+		if seen[partnerID] {
+			continue
+		}
+		seen[partnerID] = true
+
+		var partnerCharges []*models.Charge
+		var err error
+		if status == "" {
+			partnerCharges, err = c.GetChargesByAgencyPair(ctx, agencyID, partnerID)
+		} else {
+			partnerCharges, err = c.GetChargesByStatus(ctx, agencyID, partnerID, status)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read charges with %s: %w", partnerID, err)
+		}
+		charges = append(charges, partnerCharges...)
 	}
-	collection := "charges_" + a + "_" + b
 
+	return charges, nil
+}
+
+// GetChargeCount returns the number of charges between two agencies,
+// excluding deleted charges. It reads the collection's maintained charge
+// counter, so it costs one read however many charges there are.
+func (c *ChargeContract) GetChargeCount(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string) (int, error) {
+	return readChargeCount(ctx, models.BilateralCollectionName(agencyA, agencyB))
+}
+
+// chargeCountKey is the key of the counter each bilateral collection keeps
+// of its live (not deleted) charges. It sits outside the CHARGE_ key range.
+const chargeCountKey = "COUNT_CHARGE"
+
+// readChargeCount returns the charge counter of a collection. A collection
+// written before counters existed has none, so its charges are counted with
+// a range scan instead.
+func readChargeCount(ctx contractapi.TransactionContextInterface, collection string) (int, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collection, chargeCountKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read charge count: %w", err)
+	}
+	if bytes == nil {
+		return countCharges(ctx, collection)
+	}
+	count, err := strconv.Atoi(string(bytes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse charge count: %w", err)
+	}
+	return count, nil
+}
+
+// adjustChargeCount adds delta to the charge counter of a collection. Every
+// charge create, delete and purge writes the same key, so concurrent
+// transactions on one agency pair fail Fabric's MVCC check and must be
+// resubmitted. Fabric does not return a transaction's own writes, so callers
+// must adjust the counter at most once per transaction, and before writing
+// the charge so a seeding scan sees the collection as it was.
+func adjustChargeCount(ctx contractapi.TransactionContextInterface, collection string, delta int) error {
+	count, err := readChargeCount(ctx, collection)
+	if err != nil {
+		return err
+	}
+	return ctx.GetStub().PutPrivateData(collection, chargeCountKey, []byte(strconv.Itoa(count+delta)))
+}
+
+// countCharges counts the live charges in a collection by walking its
+// CHARGE_ key range, at a cost of O(n) reads.
+func countCharges(ctx contractapi.TransactionContextInterface, collection string) (int, error) {
 	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collection, "CHARGE_", "CHARGE_~")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get private data by range: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return 0, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var charge models.Charge
+		if err := decodeDocument("charge", queryResponse.Value, &charge); err != nil {
+			return 0, fmt.Errorf("failed to parse charge: %w", err)
+		}
+		if !charge.Deleted {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ChargeSummary is the result of GetChargeSummary. Amounts are rounded to
+// the cent. CorrectedNet is NetAmount with the corrections applied: what the
+// away agency is owed once adjustments are counted. Classes breaks the
+// charges down by vehicle class, in class order.
+type ChargeSummary struct {
+	AwayAgencyID          string                `json:"awayAgencyID"`
+	HomeAgencyID          string                `json:"homeAgencyID"`
+	ChargeCount           int                   `json:"chargeCount"`
+	GrossAmount           float64               `json:"grossAmount"`
+	TotalFees             float64               `json:"totalFees"`
+	NetAmount             float64               `json:"netAmount"`
+	CorrectionCount       int                   `json:"correctionCount"`
+	TotalCorrectionAmount float64               `json:"totalCorrectionAmount"`
+	CorrectedNet          float64               `json:"correctedNet"`
+	Classes               []*ChargeClassSummary `json:"classes"`
+}
+
+// ChargeClassSummary totals the charges of one vehicle class in a
+// ChargeSummary. Description comes from the vehicle class table and is empty
+// for a class the table does not list.
+type ChargeClassSummary struct {
+	VehicleClass int     `json:"vehicleClass"`
+	Description  string  `json:"description,omitempty"`
+	ChargeCount  int     `json:"chargeCount"`
+	GrossAmount  float64 `json:"grossAmount"`
+	NetAmount    float64 `json:"netAmount"`
+}
+
+// GetChargeSummary totals the charges awayAgencyID has submitted to
+// homeAgencyID, and the corrections to them, in one scan of the agencies'
+// collection. CHARGE_ keys sort before CORRECTION_ keys, so every charge has
+// been seen by the time its corrections are reached. Deleted charges, and
+// corrections to them, are left out.
+func (c *ChargeContract) GetChargeSummary(ctx contractapi.TransactionContextInterface, awayAgencyID string, homeAgencyID string) (*ChargeSummary, error) {
+	collection := models.BilateralCollectionName(awayAgencyID, homeAgencyID)
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collection, "CHARGE_", "CORRECTION_~")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get private data by range: %w", err)
 	}
 	defer resultsIterator.Close()
 
+	summary := &ChargeSummary{AwayAgencyID: awayAgencyID, HomeAgencyID: homeAgencyID, Classes: []*ChargeClassSummary{}}
+	counted := make(map[string]bool)
+	classes := make(map[int]*ChargeClassSummary)
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(queryResponse.Key, "CHARGE_"):
+			var charge models.Charge
+			if err := decodeDocument("charge", queryResponse.Value, &charge); err != nil {
+				return nil, fmt.Errorf("failed to parse charge: %w", err)
+			}
+			if charge.Deleted || charge.AwayAgencyID != awayAgencyID || charge.HomeAgencyID != homeAgencyID {
+				continue
+			}
+			counted[charge.ChargeID] = true
+			summary.ChargeCount++
+			summary.GrossAmount += charge.Amount
+			summary.TotalFees += charge.Fee
+			summary.NetAmount += charge.NetAmount
+
+			class, ok := classes[charge.VehicleClass]
+			if !ok {
+				vc, _ := models.LookupVehicleClass(charge.VehicleClass)
+				class = &ChargeClassSummary{VehicleClass: charge.VehicleClass, Description: vc.Description}
+				classes[charge.VehicleClass] = class
+				summary.Classes = append(summary.Classes, class)
+			}
+			class.ChargeCount++
+			class.GrossAmount += charge.Amount
+			class.NetAmount += charge.NetAmount
+		case strings.HasPrefix(queryResponse.Key, "CORRECTION_"):
+			var correction models.Correction
+			if err := decodeDocument("correction", queryResponse.Value, &correction); err != nil {
+				return nil, fmt.Errorf("failed to parse correction: %w", err)
+			}
+			if !counted[correction.OriginalChargeID] {
+				continue
+			}
+			summary.CorrectionCount++
+			summary.TotalCorrectionAmount += correction.Amount
+		}
+	}
+
+	summary.GrossAmount = math.Round(summary.GrossAmount*100) / 100
+	summary.TotalFees = math.Round(summary.TotalFees*100) / 100
+	summary.NetAmount = math.Round(summary.NetAmount*100) / 100
+	summary.TotalCorrectionAmount = math.Round(summary.TotalCorrectionAmount*100) / 100
+	summary.CorrectedNet = math.Round((summary.NetAmount+summary.TotalCorrectionAmount)*100) / 100
+	sort.Slice(summary.Classes, func(i, j int) bool {
+		return summary.Classes[i].VehicleClass < summary.Classes[j].VehicleClass
+	})
+	for _, class := range summary.Classes {
+		class.GrossAmount = math.Round(class.GrossAmount*100) / 100
+		class.NetAmount = math.Round(class.NetAmount*100) / 100
+	}
+
+	return summary, nil
+}
+
+// ChargesByIDsResult is the result of GetChargesByIDs.
+type ChargesByIDsResult struct {
+	Charges    []*models.Charge `json:"charges"`
+	MissingIDs []string         `json:"missingIDs"`
+}
+
+// GetChargesByIDs returns the charges with the given IDs for an agency pair,
+// in the order requested, and lists the IDs that were not found. idsJSON is
+// a JSON array of chargeIDs holding at most MaxBatchSize entries;
+// repeated IDs are read once.
+func (c *ChargeContract) GetChargesByIDs(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, idsJSON string) (*ChargesByIDsResult, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(idsJSON), &ids); err != nil {
+		return nil, fmt.Errorf("failed to parse charge IDs JSON: %w", err)
+	}
+	if err := checkBatchSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	result := &ChargesByIDsResult{Charges: []*models.Charge{}, MissingIDs: []string{}}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		charge, err := findCharge(ctx, id, agencyA, agencyB)
+		if err != nil {
+			return nil, err
+		}
+		if charge == nil {
+			result.MissingIDs = append(result.MissingIDs, id)
+			continue
+		}
+		result.Charges = append(result.Charges, charge)
+	}
+
+	return result, nil
+}
+
+// GetChargesByStatus returns all charges with a specific status for an agency
+// pair. Reads the chargeByStatus composite key index instead of scanning the
+// whole collection. Deleted charges are skipped.
+func (c *ChargeContract) GetChargesByStatus(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, status string) ([]*models.Charge, error) {
+	if !models.Contains(models.ValidChargeStatuses, status) {
+		return nil, fmt.Errorf("invalid status %q: must be one of %v", status, models.ValidChargeStatuses)
+	}
+
+	collection := models.BilateralCollectionName(agencyA, agencyB)
+	resultsIterator, err := ctx.GetStub().GetPrivateDataByPartialCompositeKey(collection, chargeByStatusIndex, []string{status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query charge index: %w", err)
+	}
+	defer resultsIterator.Close()
+
 	var charges []*models.Charge
 	for resultsIterator.HasNext() {
 		queryResponse, err := resultsIterator.Next()
@@ -121,8 +923,338 @@ func (c *ChargeContract) GetChargesByAgencyPair(ctx contractapi.TransactionConte
 			return nil, fmt.Errorf("failed to iterate: %w", err)
 		}
 
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split index key: %w", err)
+		}
+		if len(attributes) != 2 {
+			continue
+		}
+
+		charge, err := c.GetCharge(ctx, attributes[1], agencyA, agencyB)
+		if err != nil {
+			return nil, err
+		}
+		if charge.Deleted {
+			continue
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, nil
+}
+
+// ChargeSortFields lists the charge fields that GetChargesByStatusSorted can
+// sort on. Each has a (docType, status, field) index in every bilateral
+// collection under META-INF/statedb/couchdb/collections.
+var ChargeSortFields = []string{"exitDateTime", "amount", "createdAt"}
+
+// GetChargesByStatusSorted returns charges with a specific status for an
+// agency pair, sorted ascending by sortField and truncated to limit results.
+// An empty sortField leaves results unsorted; a limit of 0 returns all matches.
+// Deleted charges are excluded.
+func (c *ChargeContract) GetChargesByStatusSorted(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, status string, sortField string, limit int) ([]*models.Charge, error) {
+	if !models.Contains(models.ValidChargeStatuses, status) {
+		return nil, fmt.Errorf("invalid status %q: must be one of %v", status, models.ValidChargeStatuses)
+	}
+	if err := validateSortAndLimit(sortField, ChargeSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"status":  status,
+		"deleted": map[string]interface{}{"$exists": false},
+	}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesByProtocol returns the charges for an agency pair that arrived
+// through the given protocol, one of models.ValidChargeProtocols. Deleted
+// charges are excluded. Returns an empty list when none match.
+func (c *ChargeContract) GetChargesByProtocol(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, protocol string) ([]*models.Charge, error) {
+	if !models.Contains(models.ValidChargeProtocols, protocol) {
+		return nil, fmt.Errorf("invalid protocol %q: must be one of %v", protocol, models.ValidChargeProtocols)
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"protocol": protocol,
+		"deleted":  map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesBySubmissionChannel returns the charges for an agency pair
+// submitted through channel, one of models.ValidSubmissionChannels, for
+// analysing how charges are routed. Charges that do not record a channel and
+// deleted charges are excluded. Returns an empty list when none match.
+func (c *ChargeContract) GetChargesBySubmissionChannel(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, channel string) ([]*models.Charge, error) {
+	if !models.Contains(models.ValidSubmissionChannels, channel) {
+		return nil, fmt.Errorf("invalid submission channel %q: must be one of %v", channel, models.ValidSubmissionChannels)
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"submittedVia": channel,
+		"deleted":      map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesModifiedSince returns the charges for an agency pair written at
+// or after since, an RFC 3339 timestamp, so an off-chain replica can sync
+// incrementally from the time of its last sync. Deleted charges are included
+// so the replica sees deletions. Charges not written since UpdatedAt was
+// added have no UpdatedAt and are never returned. Returns an empty list when
+// none match.
+func (c *ChargeContract) GetChargesModifiedSince(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, since string) ([]*models.Charge, error) {
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since %q: must be an RFC 3339 timestamp: %w", since, err)
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"updatedAt": map[string]interface{}{"$gte": sinceTime.UTC().Format(time.RFC3339)},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesByAmountRange returns the charges for an agency pair whose amount
+// lies between minAmount and maxAmount inclusive, for example to review every
+// charge over a fraud threshold. Deleted charges are excluded. Returns an
+// empty list when none match.
+func (c *ChargeContract) GetChargesByAmountRange(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, minAmount float64, maxAmount float64) ([]*models.Charge, error) {
+	if minAmount < 0 {
+		return nil, fmt.Errorf("minAmount must be >= 0, got %f", minAmount)
+	}
+	if maxAmount < minAmount {
+		return nil, fmt.Errorf("maxAmount %f must not be less than minAmount %f", maxAmount, minAmount)
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"amount":  map[string]interface{}{"$gte": minAmount, "$lte": maxAmount},
+		"deleted": map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// ChargeRoles lists the roles GetChargesByRole accepts: "home" selects the
+// charges an agency owes, "away" the charges it submitted.
+var ChargeRoles = []string{"home", "away"}
+
+// GetChargesByRole returns the charges for an agency pair in which agencyID,
+// one of the pair, is the home or away agency as role selects. Deleted
+// charges are excluded. Returns an empty list when none match.
+func (c *ChargeContract) GetChargesByRole(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, agencyID string, role string) ([]*models.Charge, error) {
+	if !models.Contains(ChargeRoles, role) {
+		return nil, fmt.Errorf("invalid role %q: must be one of %v", role, ChargeRoles)
+	}
+	if agencyID != agencyA && agencyID != agencyB {
+		return nil, fmt.Errorf("agency %s is not part of the pair %s/%s", agencyID, agencyA, agencyB)
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		role + "AgencyID": agencyID,
+		"deleted":         map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesByPlate returns the video charges for an agency pair on the plate
+// identified by country, state and number, in any status. All three are
+// required. Plate values are matched case-insensitively. Tag charges and deleted charges are excluded.
+// Returns an empty list when none match.
+func (c *ChargeContract) GetChargesByPlate(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, plateCountry string, plateState string, plateNumber string) ([]*models.Charge, error) {
+	if plateNumber == "" {
+		return nil, fmt.Errorf("plateNumber is required")
+	}
+	if plateState == "" {
+		return nil, fmt.Errorf("plateState is required")
+	}
+	if plateCountry == "" {
+		return nil, fmt.Errorf("plateCountry is required")
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"plateCountry": models.NormalizePlate(plateCountry),
+		"plateState":   models.NormalizePlate(plateState),
+		"plateNumber":  models.NormalizePlate(plateNumber),
+		"recordType":   map[string]interface{}{"$in": models.VideoRecordTypes()},
+		"deleted":      map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetChargesByEntryPlaza returns the charges for an agency pair that entered
+// at entryPlaza, in any status, for segment (distance-based) tolling. Point
+// charges, which have no entryPlaza, and deleted charges are excluded.
+// Returns an empty list when none match.
+func (c *ChargeContract) GetChargesByEntryPlaza(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, entryPlaza string) ([]*models.Charge, error) {
+	if entryPlaza == "" {
+		return nil, fmt.Errorf("entryPlaza is required")
+	}
+
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"entryPlaza": entryPlaza,
+		"deleted":    map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// maxUTCOffset is the largest offset from UTC in use, UTC+14:00.
+const maxUTCOffset = 14 * time.Hour
+
+// GetAgingUnreconciledCharges returns the charges for an agency pair that the
+// home agency has not reconciled and whose exit date is more than maxAgeDays
+// days before asOfDate, a YYYY-MM-DD date taken as midnight UTC. Exit times
+// are compared as instants, whatever their UTC offset; charges whose
+// exitDateTime is not RFC3339 are skipped. Only charge-level reconciliations
+// count. Deleted charges are excluded. Returns an empty list when none match.
+func (c *ChargeContract) GetAgingUnreconciledCharges(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, asOfDate string, maxAgeDays int) ([]*models.Charge, error) {
+	asOf, err := time.Parse(time.DateOnly, asOfDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asOfDate %q: must be a YYYY-MM-DD date", asOfDate)
+	}
+	if maxAgeDays < 0 {
+		return nil, fmt.Errorf("maxAgeDays must be >= 0, got %d", maxAgeDays)
+	}
+
+	// exitDateTime may carry any UTC offset, so the selector compares as
+	// strings against a bound widened by the largest offset, and each charge
+	// is then checked against the cutoff by its parsed time.
+	cutoff := asOf.AddDate(0, 0, -maxAgeDays)
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"exitDateTime": map[string]interface{}{"$lt": cutoff.Add(maxUTCOffset).Format(time.RFC3339)},
+		"deleted":      map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	charges, err := queryCharges(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+	if err != nil {
+		return nil, err
+	}
+
+	unreconciled := []*models.Charge{}
+	for _, charge := range charges {
+		exitTime, err := time.Parse(time.RFC3339, charge.ExitDateTime)
+		if err != nil || !exitTime.Before(cutoff) {
+			continue
+		}
+		recon, err := ctx.GetStub().GetState(models.ReconciliationKey(charge.ChargeID, 0))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state: %w", err)
+		}
+		if recon == nil {
+			unreconciled = append(unreconciled, charge)
+		}
+	}
+
+	return unreconciled, nil
+}
+
+// GetFacilityChargeCount returns how many charges for an agency pair exited
+// facilityID at or after start and before end, both RFC3339 timestamps.
+// Only the count is returned, for congestion pricing; deleted charges are
+// not counted.
+func (c *ChargeContract) GetFacilityChargeCount(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, facilityID string, start string, end string) (int, error) {
+	if facilityID == "" {
+		return 0, fmt.Errorf("facilityID is required")
+	}
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start %q: must be an RFC3339 timestamp", start)
+	}
+	endTime, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return 0, fmt.Errorf("invalid end %q: must be an RFC3339 timestamp", end)
+	}
+	if !endTime.After(startTime) {
+		return 0, fmt.Errorf("end %q must be after start %q", end, start)
+	}
+
+	// exitDateTime is stored in UTC, so compare against UTC bounds.
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"facilityID": facilityID,
+		"exitDateTime": map[string]interface{}{
+			"$gte": startTime.UTC().Format(time.RFC3339),
+			"$lt":  endTime.UTC().Format(time.RFC3339),
+		},
+		"deleted": map[string]interface{}{"$exists": false},
+	}).onlyFields("chargeID").String()
+	if err != nil {
+		return 0, err
+	}
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataQueryResult(models.BilateralCollectionName(agencyA, agencyB), query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		if _, err := resultsIterator.Next(); err != nil {
+			return 0, fmt.Errorf("failed to iterate: %w", err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// queryCharges runs a rich query against a bilateral collection and decodes
+// the matching charges.
+func queryCharges(ctx contractapi.TransactionContextInterface, collection string, query string) ([]*models.Charge, error) {
+	resultsIterator, err := ctx.GetStub().GetPrivateDataQueryResult(collection, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	charges := []*models.Charge{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
 		var charge models.Charge
-		if err := json.Unmarshal(queryResponse.Value, &charge); err != nil {
+		if err := decodeDocument("charge", queryResponse.Value, &charge); err != nil {
 			return nil, fmt.Errorf("failed to parse charge: %w", err)
 		}
 		charges = append(charges, &charge)
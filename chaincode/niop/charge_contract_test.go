@@ -4,7 +4,10 @@ package niop
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +34,65 @@ func validCharge() *models.Charge {
 	}
 }
 
+// createChargeWithStatus creates charge in pending and moves it through the
+// lifecycle to status.
+func createChargeWithStatus(t *testing.T, ctx *enhancedMockContext, charge *models.Charge, status string) {
+	t.Helper()
+	contract := &ChargeContract{}
+	charge.Status = "pending"
+	chargeJSON, _ := json.Marshal(charge)
+	require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+	path := map[string][]string{
+		"pending":  nil,
+		"posted":   {"posted"},
+		"rejected": {"rejected"},
+		"disputed": {"posted", "disputed"},
+		"settled":  {"posted", "settled"},
+	}
+	steps, ok := path[status]
+	require.True(t, ok, "no lifecycle path to %q", status)
+	for _, step := range steps {
+		require.NoError(t, contract.UpdateChargeStatus(ctx, charge.ChargeID, charge.AwayAgencyID, charge.HomeAgencyID, step))
+	}
+	// Setup runs as separate transactions; drop their events.
+	ctx.stub.events = nil
+}
+
+// chargeFixture is a charge for newContextWithCharges: validCharge with the
+// given ID, changed by modify when set, brought to status ("pending" when
+// empty).
+type chargeFixture struct {
+	id     string
+	status string
+	modify func(*models.Charge)
+}
+
+// exitingAt returns a chargeFixture modify func setting the exit time.
+func exitingAt(exit string) func(*models.Charge) {
+	return func(c *models.Charge) { c.ExitDateTime = exit }
+}
+
+// newContextWithCharges returns a mock context holding a charge for each
+// fixture, created in order.
+func newContextWithCharges(t *testing.T, fixtures ...chargeFixture) *enhancedMockContext {
+	t.Helper()
+	ctx := newMockContext()
+	for _, f := range fixtures {
+		charge := validCharge()
+		charge.ChargeID = f.id
+		if f.modify != nil {
+			f.modify(charge)
+		}
+		status := f.status
+		if status == "" {
+			status = "pending"
+		}
+		createChargeWithStatus(t, ctx, charge, status)
+	}
+	return ctx
+}
+
 func TestCreateCharge(t *testing.T) {
 	contract := &ChargeContract{}
 
@@ -53,6 +115,12 @@ func TestCreateCharge(t *testing.T) {
 		assert.Equal(t, "CHG-TEST-001", stored.ChargeID)
 		assert.Equal(t, "pending", stored.Status)
 		assert.NotEmpty(t, stored.CreatedAt)
+
+		require.Len(t, ctx.stub.events, 1)
+		assert.Equal(t, "ChargeCreated", ctx.stub.events[0].name)
+		var event ChargeCreatedEvent
+		require.NoError(t, json.Unmarshal(ctx.stub.events[0].payload, &event))
+		assert.Equal(t, ChargeCreatedEvent{ChargeID: "CHG-TEST-001", AwayAgencyID: "ORG2", HomeAgencyID: "ORG1"}, event)
 	})
 
 	t.Run("rejects duplicate charge", func(t *testing.T) {
@@ -84,6 +152,49 @@ func TestCreateCharge(t *testing.T) {
 		err := contract.CreateCharge(ctx, string(chargeJSON))
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "must be different")
+
+		var ve *models.ValidationError
+		require.True(t, errors.As(err, &ve))
+		assert.Equal(t, "homeAgencyID", ve.Field)
+	})
+
+	t.Run("reports every validation failure when configured", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ReportAllValidationErrors = true })
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.FacilityID = ""
+		charge.Amount = -1
+		chargeJSON, _ := json.Marshal(charge)
+
+		err := contract.CreateCharge(ctx, string(chargeJSON))
+		require.Error(t, err)
+		var errs models.ValidationErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 2)
+		assert.Equal(t, "facilityID", errs[0].Field)
+		assert.Equal(t, "amount", errs[1].Field)
+		assert.Contains(t, err.Error(), "validation failed: facilityID is required; amount must be >= 0")
+	})
+
+	t.Run("rejects pay-by-plate charge without plate when configured", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RequirePayByPlatePlates = true })
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.ChargeType = "toll_paybyplate"
+		chargeJSON, _ := json.Marshal(charge)
+
+		err := contract.CreateCharge(ctx, string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateNumber is required for toll_paybyplate charges")
+	})
+
+	t.Run("accepts pay-by-plate charge without plate by default", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.ChargeType = "toll_paybyplate"
+		chargeJSON, _ := json.Marshal(charge)
+
+		assert.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
 	})
 
 	t.Run("rejects tag charge without tag serial number", func(t *testing.T) {
@@ -109,6 +220,141 @@ func TestCreateCharge(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "plateNumber is required")
 	})
+
+	t.Run("rejects posted initial status", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.Status = "posted"
+		chargeJSON, _ := json.Marshal(charge)
+
+		err := contract.CreateCharge(ctx, string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "new charges must start in pending")
+	})
+
+	t.Run("defaults empty status to pending", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.Status = ""
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "pending", stored.Status)
+
+		pending, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "pending")
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+	})
+}
+
+func TestCreateCharge_TagClass(t *testing.T) {
+	contract := &ChargeContract{}
+
+	original := models.VehicleClassRemaps
+	t.Cleanup(func() { models.VehicleClassRemaps = original })
+	models.VehicleClassRemaps = map[int][]int{2: {3}}
+
+	createTag := func(t *testing.T, ctx *enhancedMockContext) {
+		tagJSON, _ := json.Marshal(validTag())
+		require.NoError(t, (&TagContract{}).CreateTag(ctx, string(tagJSON)))
+	}
+	chargeWithClass := func(class int) string {
+		charge := validCharge()
+		charge.VehicleClass = class
+		chargeJSON, _ := json.Marshal(charge)
+		return string(chargeJSON)
+	}
+
+	t.Run("accepts matching class", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		require.NoError(t, contract.CreateCharge(ctx, chargeWithClass(2)))
+	})
+
+	t.Run("accepts allowed remap", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		require.NoError(t, contract.CreateCharge(ctx, chargeWithClass(3)))
+	})
+
+	t.Run("rejects divergent class", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		err := contract.CreateCharge(ctx, chargeWithClass(5))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vehicleClass 5 does not match class 2 of tag TEST.000000001")
+	})
+
+	t.Run("rejects unknown tag", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+
+		err := contract.CreateCharge(ctx, chargeWithClass(2))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load tag")
+	})
+
+	t.Run("skips tag lookup outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newMockContext()
+
+		require.NoError(t, contract.CreateCharge(ctx, chargeWithClass(5)))
+	})
+}
+
+func TestCreateCharge_TagProtocol(t *testing.T) {
+	contract := &ChargeContract{}
+
+	original := models.ProtocolTagProtocols
+	t.Cleanup(func() { models.ProtocolTagProtocols = original })
+	models.ProtocolTagProtocols = map[string][]string{"niop": {"sego", "6c", "tdm"}, "iag": {"tdm"}}
+
+	createTag := func(t *testing.T, ctx *enhancedMockContext) {
+		tagJSON, _ := json.Marshal(validTag())
+		require.NoError(t, (&TagContract{}).CreateTag(ctx, string(tagJSON)))
+	}
+	iagCharge := func() string {
+		charge := validCharge()
+		charge.Protocol = "iag"
+		charge.RecordType = "ICTX"
+		chargeJSON, _ := json.Marshal(charge)
+		return string(chargeJSON)
+	}
+
+	t.Run("accepts compatible tag", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		chargeJSON, _ := json.Marshal(validCharge())
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+	})
+
+	t.Run("rejects incompatible tag", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		err := contract.CreateCharge(ctx, iagCharge())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tagProtocol 6c of tag TEST.000000001 is not compatible with protocol iag")
+	})
+
+	t.Run("skips check outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newMockContext()
+		createTag(t, ctx)
+
+		require.NoError(t, contract.CreateCharge(ctx, iagCharge()))
+	})
 }
 
 func TestGetCharge(t *testing.T) {
@@ -151,6 +397,78 @@ func TestGetCharge(t *testing.T) {
 	})
 }
 
+func TestGetChargeRedacted(t *testing.T) {
+	contract := &ChargeContract{}
+
+	t.Run("masks plate and tag serial", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.PlateNumber = "7ABC123"
+		charge.PlateState = "CA"
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		result, err := contract.GetChargeRedacted(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "7AB****", result.PlateNumber)
+		assert.Equal(t, "**********0001", result.TagSerialNumber)
+		assert.Equal(t, 4.75, result.Amount)
+
+		full, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "7ABC123", full.PlateNumber)
+	})
+
+	t.Run("returns error for nonexistent charge", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargeRedacted(ctx, "NONEXISTENT", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestVerifyChargeHash(t *testing.T) {
+	contract := &ChargeContract{}
+
+	t.Run("matches untouched private data", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		match, err := contract.VerifyChargeHash(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.True(t, match)
+	})
+
+	t.Run("detects tampered private data", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		tampered := validCharge()
+		tampered.Amount = 0.01
+		tamperedJSON, _ := json.Marshal(tampered)
+		ctx.stub.privateData["charges_ORG1_ORG2"]["CHARGE_CHG-TEST-001"] = tamperedJSON
+
+		match, err := contract.VerifyChargeHash(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("detects missing local copy", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		delete(ctx.stub.privateData["charges_ORG1_ORG2"], "CHARGE_CHG-TEST-001")
+
+		match, err := contract.VerifyChargeHash(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("returns error for unknown charge", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.VerifyChargeHash(ctx, "NONEXISTENT", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
 func TestUpdateChargeStatus(t *testing.T) {
 	contract := &ChargeContract{}
 
@@ -199,6 +517,157 @@ func TestUpdateChargeStatus(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
+
+	t.Run("stamps updatedAt with the transaction timestamp", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001"})
+		// 2026-03-01T09:30:00Z, well away from the clock.
+		ctx.stub.TxTimestamp.Seconds = 1772357400
+		ctx.stub.TxTimestamp.Nanos = 0
+
+		require.NoError(t, contract.UpdateChargeStatus(ctx, "CHG-TEST-001", "ORG2", "ORG1", "posted"))
+
+		result, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "2026-03-01T09:30:00Z", result.UpdatedAt)
+	})
+}
+
+func TestUpdateChargeStatus_SettledCharges(t *testing.T) {
+	contract := &ChargeContract{}
+
+	tests := []struct {
+		name             string
+		settlementStatus string
+		wantErr          string
+	}{
+		{"allows transition for charge in draft settlement", "draft", ""},
+		{"blocks transition for charge in paid settlement", "paid", "paid settlement SETTLE-TEST-001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newMockContext()
+			createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+			// Write the settlement directly so the charge stays posted;
+			// paying through the workflow would settle it first.
+			settlement := validSettlement()
+			settlement.Status = tt.settlementStatus
+			settlement.ChargeIDs = []string{"CHG-TEST-001"}
+			settlement.SetCreatedAt()
+			settlementJSON, _ := json.Marshal(settlement)
+			require.NoError(t, ctx.stub.PutPrivateData(settlement.CollectionName(), settlement.Key(), settlementJSON))
+
+			err := contract.UpdateChargeStatus(ctx, "CHG-TEST-001", "ORG2", "ORG1", "disputed")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("ignores paid settlements that do not include the charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		settlement := validSettlement()
+		settlement.ChargeIDs = []string{"CHG-OTHER"}
+		settlement.ChargeCount = 1
+		createSettlementWithStatus(t, ctx, settlement, "paid")
+
+		assert.NoError(t, contract.UpdateChargeStatus(ctx, "CHG-TEST-001", "ORG2", "ORG1", "disputed"))
+	})
+}
+
+func TestDeleteCharge(t *testing.T) {
+	contract := &ChargeContract{}
+
+	for _, status := range []string{"pending", "rejected"} {
+		t.Run("deletes "+status+" charge", func(t *testing.T) {
+			ctx := newMockContext()
+			createChargeWithStatus(t, ctx, validCharge(), status)
+
+			require.NoError(t, contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "duplicate read"))
+
+			charge, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+			require.NoError(t, err, "deleted charges stay on the ledger")
+			assert.True(t, charge.Deleted)
+			assert.Equal(t, "duplicate read", charge.DeletedReason)
+			assert.NotEmpty(t, charge.DeletedAt)
+			assert.Equal(t, status, charge.Status)
+
+			require.Len(t, ctx.stub.events, 1)
+			assert.Equal(t, "ChargeDeleted", ctx.stub.events[0].name)
+			var event ChargeDeletedEvent
+			require.NoError(t, json.Unmarshal(ctx.stub.events[0].payload, &event))
+			assert.Equal(t, "CHG-TEST-001", event.ChargeID)
+			assert.Equal(t, "duplicate read", event.Reason)
+		})
+	}
+
+	for _, status := range []string{"posted", "disputed", "settled"} {
+		t.Run("rejects "+status+" charge", func(t *testing.T) {
+			ctx := newMockContext()
+			createChargeWithStatus(t, ctx, validCharge(), status)
+
+			err := contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "duplicate read")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cannot delete charge")
+			assert.Empty(t, ctx.stub.events)
+		})
+	}
+
+	t.Run("requires reason", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+
+		err := contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reason is required")
+	})
+
+	t.Run("returns error for nonexistent charge", func(t *testing.T) {
+		ctx := newMockContext()
+		err := contract.DeleteCharge(ctx, "NONEXISTENT", "ORG2", "ORG1", "duplicate read")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("deleted charge cannot change status", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "duplicate read"))
+
+		err := contract.UpdateChargeStatus(ctx, "CHG-TEST-001", "ORG2", "ORG1", "posted")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "charge is deleted")
+	})
+
+	t.Run("queries skip deleted charges", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		kept := validCharge()
+		kept.ChargeID = "CHG-TEST-002"
+		createChargeWithStatus(t, ctx, kept, "pending")
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "duplicate read"))
+
+		byPair, err := contract.GetChargesByAgencyPair(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		require.Len(t, byPair, 1)
+		assert.Equal(t, "CHG-TEST-002", byPair[0].ChargeID)
+
+		byStatus, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "pending")
+		require.NoError(t, err)
+		require.Len(t, byStatus, 1)
+		assert.Equal(t, "CHG-TEST-002", byStatus[0].ChargeID)
+
+		sorted, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "pending", "amount", 0)
+		require.NoError(t, err)
+		require.Len(t, sorted, 1)
+		assert.Equal(t, "CHG-TEST-002", sorted[0].ChargeID)
+	})
 }
 
 func TestGetChargesByAgencyPair(t *testing.T) {
@@ -245,20 +714,1223 @@ func TestGetChargesByAgencyPair(t *testing.T) {
 	})
 }
 
-func TestChargeCollectionNameSymmetry(t *testing.T) {
-	// This tests a critical business rule: collection names must be symmetric
-	// so both agencies can find the same data regardless of who queries
+func TestVoidAndReissue(t *testing.T) {
+	contract := &ChargeContract{}
 
-	charge1 := &models.Charge{
-		AwayAgencyID: "ORG2",
-		HomeAgencyID: "ORG1",
+	reissue := func() string {
+		charge := validCharge()
+		charge.ChargeID = "CHG-TEST-002"
+		charge.FacilityID = "SR241"
+		chargeJSON, _ := json.Marshal(charge)
+		return string(chargeJSON)
 	}
 
-	charge2 := &models.Charge{
-		AwayAgencyID: "ORG1",
-		HomeAgencyID: "ORG2",
-	}
+	t.Run("voids the old charge and creates the new one", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "rejected")
+		ctx.stub.events = nil
 
-	assert.Equal(t, charge1.CollectionName(), charge2.CollectionName())
-	assert.Equal(t, "charges_ORG1_ORG2", charge1.CollectionName())
+		created, err := contract.VoidAndReissue(ctx, "CHG-TEST-001", "ORG2", "ORG1", reissue())
+		require.NoError(t, err)
+		assert.Equal(t, "CHG-TEST-001", created.ReplacesChargeID)
+
+		old, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.True(t, old.Deleted)
+		assert.Equal(t, "voided and reissued as CHG-TEST-002", old.DeletedReason)
+		assert.Equal(t, "CHG-TEST-002", old.SupersededByChargeID)
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-002", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Equal(t, "SR241", stored.FacilityID)
+		assert.Equal(t, "CHG-TEST-001", stored.ReplacesChargeID)
+
+		count, err := contract.GetChargeCount(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.Len(t, ctx.stub.events, 1)
+		assert.Equal(t, "ChargeReissued", ctx.stub.events[0].name)
+		var event ChargeReissuedEvent
+		require.NoError(t, json.Unmarshal(ctx.stub.events[0].payload, &event))
+		assert.Equal(t, "CHG-TEST-001", event.VoidedChargeID)
+		assert.Equal(t, "CHG-TEST-002", event.NewChargeID)
+	})
+
+	t.Run("rejects voiding a posted charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		_, err := contract.VoidAndReissue(ctx, "CHG-TEST-001", "ORG2", "ORG1", reissue())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot void charge CHG-TEST-001")
+
+		_, err = contract.GetCharge(ctx, "CHG-TEST-002", "ORG2", "ORG1")
+		require.Error(t, err, "new charge must not be created")
+	})
+
+	t.Run("validates the new charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		charge := validCharge()
+		charge.ChargeID = "CHG-TEST-002"
+		charge.FacilityID = ""
+		chargeJSON, _ := json.Marshal(charge)
+
+		_, err := contract.VoidAndReissue(ctx, "CHG-TEST-001", "ORG2", "ORG1", string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "facilityID is required")
+
+		old, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.False(t, old.Deleted)
+	})
+
+	t.Run("requires a new chargeID", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		chargeJSON, _ := json.Marshal(validCharge())
+
+		_, err := contract.VoidAndReissue(ctx, "CHG-TEST-001", "ORG2", "ORG1", string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs a new chargeID")
+	})
+
+	t.Run("requires the same agencies", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		charge := validCharge()
+		charge.ChargeID = "CHG-TEST-002"
+		charge.AwayAgencyID = "ORG3"
+		chargeJSON, _ := json.Marshal(charge)
+
+		_, err := contract.VoidAndReissue(ctx, "CHG-TEST-001", "ORG2", "ORG1", string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must keep awayAgencyID ORG2")
+	})
+}
+
+func TestGetChargeChain(t *testing.T) {
+	contract := &ChargeContract{}
+
+	reissue := func(t *testing.T, ctx *enhancedMockContext, oldID string, newID string) {
+		charge := validCharge()
+		charge.ChargeID = newID
+		chargeJSON, _ := json.Marshal(charge)
+		_, err := contract.VoidAndReissue(ctx, oldID, "ORG2", "ORG1", string(chargeJSON))
+		require.NoError(t, err)
+	}
+
+	t.Run("walks a chain of two reissues from any member", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		reissue(t, ctx, "CHG-TEST-001", "CHG-TEST-002")
+		reissue(t, ctx, "CHG-TEST-002", "CHG-TEST-003")
+
+		for _, id := range []string{"CHG-TEST-001", "CHG-TEST-002", "CHG-TEST-003"} {
+			chain, err := contract.GetChargeChain(ctx, id, "ORG2", "ORG1")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"CHG-TEST-001", "CHG-TEST-002", "CHG-TEST-003"}, chargeIDs(chain), "chain from %s", id)
+		}
+	})
+
+	t.Run("returns a charge that was never reissued alone", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+
+		chain, err := contract.GetChargeChain(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-TEST-001"}, chargeIDs(chain))
+	})
+
+	t.Run("rejects a missing link", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.ReplacesChargeID = "CHG-TEST-000"
+		createChargeWithStatus(t, ctx, charge, "pending")
+
+		_, err := contract.GetChargeChain(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "charge CHG-TEST-000 not found")
+	})
+}
+
+func TestGetChargeWithCorrections(t *testing.T) {
+	contract := &ChargeContract{}
+	corrections := &CorrectionContract{}
+
+	t.Run("charge without corrections", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+
+		result, err := contract.GetChargeWithCorrections(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "CHG-TEST-001", result.Charge.ChargeID)
+		assert.Empty(t, result.Corrections)
+		assert.NotNil(t, result.Corrections, "serializes as [] rather than null")
+		assert.Equal(t, 4.75, result.EffectiveAmount)
+	})
+
+	t.Run("applies every correction", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		for i, amount := range []float64{-1.25, 0.10, 0.20} {
+			correction := validCorrection()
+			correction.CorrectionID = fmt.Sprintf("CORR-TEST-%03d", i+1)
+			correction.CorrectionSeqNo = i + 1
+			correction.Amount = amount
+			correctionJSON, _ := json.Marshal(correction)
+			require.NoError(t, corrections.CreateCorrection(ctx, string(correctionJSON)))
+		}
+
+		result, err := contract.GetChargeWithCorrections(ctx, "CHG-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 4.75, result.Charge.Amount)
+		require.Len(t, result.Corrections, 3)
+		assert.Equal(t, 1, result.Corrections[0].CorrectionSeqNo)
+		assert.Equal(t, 3, result.Corrections[2].CorrectionSeqNo)
+		assert.Equal(t, 3.80, result.EffectiveAmount, "rounded to the cent")
+	})
+
+	t.Run("charge not found", func(t *testing.T) {
+		ctx := newMockContext()
+
+		_, err := contract.GetChargeWithCorrections(ctx, "CHG-MISSING", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestGetChargeWithReconciliation(t *testing.T) {
+	contract := &ChargeContract{}
+	reconciliations := &ReconciliationContract{}
+
+	t.Run("unreconciled charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+
+		result, err := contract.GetChargeWithReconciliation(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "CHG-TEST-001", result.Charge.ChargeID)
+		assert.Nil(t, result.Reconciliation)
+		assert.False(t, result.IsPosted)
+
+		bytes, err := json.Marshal(result)
+		require.NoError(t, err)
+		assert.NotContains(t, string(bytes), `"reconciliation"`)
+	})
+
+	t.Run("posted charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		reconJSON, _ := json.Marshal(validReconciliation())
+		require.NoError(t, reconciliations.CreateReconciliation(ctx, string(reconJSON)))
+
+		result, err := contract.GetChargeWithReconciliation(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		require.NotNil(t, result.Reconciliation)
+		assert.Equal(t, "RECON-TEST-001", result.Reconciliation.ReconciliationID)
+		assert.True(t, result.IsPosted)
+	})
+
+	t.Run("rejected charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		recon := validReconciliation()
+		recon.PostingDisposition = "D"
+		recon.PostedAmount = 0
+		recon.PostedDateTime = ""
+		reconJSON, _ := json.Marshal(recon)
+		require.NoError(t, reconciliations.CreateReconciliation(ctx, string(reconJSON)))
+
+		result, err := contract.GetChargeWithReconciliation(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		require.NotNil(t, result.Reconciliation)
+		assert.Equal(t, "D", result.Reconciliation.PostingDisposition)
+		assert.False(t, result.IsPosted)
+	})
+
+	t.Run("charge not found", func(t *testing.T) {
+		ctx := newMockContext()
+
+		_, err := contract.GetChargeWithReconciliation(ctx, "CHG-MISSING", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestGetChargesForAgency(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-ORG2-1"},
+		{id: "CHG-ORG2-2", status: "posted"},
+		{id: "CHG-ORG3-1", modify: func(c *models.Charge) { c.AwayAgencyID = "ORG3" }},
+	}
+
+	t.Run("merges charges from each partner collection", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		charges, err := contract.GetChargesForAgency(ctx, "ORG1", `["ORG2","ORG3"]`, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-ORG2-1", "CHG-ORG2-2", "CHG-ORG3-1"}, chargeIDs(charges))
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		charges, err := contract.GetChargesForAgency(ctx, "ORG1", `["ORG3","ORG2","ORG3"]`, "pending")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-ORG3-1", "CHG-ORG2-1"}, chargeIDs(charges))
+	})
+
+	t.Run("uses every other agency when no partners are given", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+
+		charges, err := contract.GetChargesForAgency(ctx, "ORG1", "", "pending")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-ORG2-1", "CHG-ORG3-1"}, chargeIDs(charges))
+	})
+
+	t.Run("returns empty list without partners", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		charges, err := contract.GetChargesForAgency(ctx, "ORG1", `[]`, "")
+		require.NoError(t, err)
+		assert.Empty(t, charges)
+	})
+
+	t.Run("rejects the agency as its own partner", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		_, err := contract.GetChargesForAgency(ctx, "ORG1", `["ORG2","ORG1"]`, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be its own partner")
+	})
+
+	t.Run("rejects invalid status", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		_, err := contract.GetChargesForAgency(ctx, "ORG1", `["ORG2"]`, "bogus")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+	})
+}
+
+func TestGetChargeCount(t *testing.T) {
+	contract := &ChargeContract{}
+	ctx := newEnhancedMockContext()
+
+	count, err := contract.GetChargeCount(ctx, "ORG1", "ORG2")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	for _, id := range []string{"CHG-TEST-001", "CHG-TEST-002", "CHG-TEST-003"} {
+		charge := validCharge()
+		charge.ChargeID = id
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+	}
+	require.NoError(t, contract.DeleteCharge(ctx, "CHG-TEST-002", "ORG2", "ORG1", "entered in error"))
+
+	count, err = contract.GetChargeCount(ctx, "ORG2", "ORG1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "deleted charges are not counted")
+
+	count, err = contract.GetChargeCount(ctx, "ORG1", "ORG3")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestChargeCounter(t *testing.T) {
+	contract := &ChargeContract{}
+	collection := models.BilateralCollectionName("ORG1", "ORG2")
+
+	storedCount := func(t *testing.T, ctx *enhancedMockContext) string {
+		t.Helper()
+		bytes, err := ctx.GetStub().GetPrivateData(collection, chargeCountKey)
+		require.NoError(t, err)
+		return string(bytes)
+	}
+
+	t.Run("increments on create and decrements on delete", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		second := validCharge()
+		second.ChargeID = "CHG-TEST-002"
+		createChargeWithStatus(t, ctx, second, "pending")
+		assert.Equal(t, "2", storedCount(t, ctx))
+
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "entered in error"))
+		assert.Equal(t, "1", storedCount(t, ctx))
+	})
+
+	t.Run("decrements on purge", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ChargeRetentionDays = 365 })
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.ExitDateTime = time.Now().UTC().AddDate(0, 0, -400).Format(time.RFC3339)
+		createChargeWithStatus(t, ctx, charge, "settled")
+
+		require.NoError(t, contract.PurgeCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1"))
+		assert.Equal(t, "0", storedCount(t, ctx))
+	})
+
+	t.Run("seeds a missing counter from the charges on the ledger", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+		require.NoError(t, ctx.GetStub().DelPrivateData(collection, chargeCountKey))
+
+		count, err := contract.GetChargeCount(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		second := validCharge()
+		second.ChargeID = "CHG-TEST-002"
+		createChargeWithStatus(t, ctx, second, "pending")
+		assert.Equal(t, "2", storedCount(t, ctx))
+	})
+}
+
+func TestGetChargeSummary(t *testing.T) {
+	contract := &ChargeContract{}
+	corrections := &CorrectionContract{}
+
+	createCharge := func(t *testing.T, ctx *enhancedMockContext, chargeID string) {
+		charge := validCharge()
+		charge.ChargeID = chargeID
+		createChargeWithStatus(t, ctx, charge, "pending")
+	}
+	createCorrection := func(t *testing.T, ctx *enhancedMockContext, chargeID string, seqNo int, amount float64) {
+		correction := validCorrection()
+		correction.CorrectionID = fmt.Sprintf("CORR-%s-%d", chargeID, seqNo)
+		correction.OriginalChargeID = chargeID
+		correction.CorrectionSeqNo = seqNo
+		correction.Amount = amount
+		correctionJSON, _ := json.Marshal(correction)
+		require.NoError(t, corrections.CreateCorrection(ctx, string(correctionJSON)))
+	}
+
+	t.Run("without corrections the corrected net is the net", func(t *testing.T) {
+		ctx := newMockContext()
+		createCharge(t, ctx, "CHG-001")
+		createCharge(t, ctx, "CHG-002")
+
+		summary, err := contract.GetChargeSummary(ctx, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, summary.ChargeCount)
+		assert.Equal(t, 9.50, summary.GrossAmount)
+		assert.Equal(t, 0.10, summary.TotalFees)
+		assert.Equal(t, 9.40, summary.NetAmount)
+		assert.Equal(t, 0, summary.CorrectionCount)
+		assert.Equal(t, 0.0, summary.TotalCorrectionAmount)
+		assert.Equal(t, 9.40, summary.CorrectedNet)
+	})
+
+	t.Run("returns empty classes without charges", func(t *testing.T) {
+		summary, err := contract.GetChargeSummary(newMockContext(), "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.NotNil(t, summary.Classes)
+		assert.Empty(t, summary.Classes)
+	})
+
+	t.Run("corrections adjust the corrected net", func(t *testing.T) {
+		ctx := newMockContext()
+		createCharge(t, ctx, "CHG-001")
+		createCharge(t, ctx, "CHG-002")
+		createCorrection(t, ctx, "CHG-001", 1, -1.25)
+		createCorrection(t, ctx, "CHG-001", 2, 0.10)
+		createCorrection(t, ctx, "CHG-002", 1, -4.75)
+
+		summary, err := contract.GetChargeSummary(ctx, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, 9.40, summary.NetAmount, "the raw net ignores corrections")
+		assert.Equal(t, 3, summary.CorrectionCount)
+		assert.Equal(t, -5.90, summary.TotalCorrectionAmount)
+		assert.Equal(t, 3.50, summary.CorrectedNet)
+	})
+
+	t.Run("skips deleted charges and their corrections", func(t *testing.T) {
+		ctx := newMockContext()
+		createCharge(t, ctx, "CHG-001")
+		createCharge(t, ctx, "CHG-002")
+		createCorrection(t, ctx, "CHG-002", 1, -1.00)
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-002", "ORG2", "ORG1", "duplicate read"))
+
+		summary, err := contract.GetChargeSummary(ctx, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.ChargeCount)
+		assert.Equal(t, 0, summary.CorrectionCount)
+		assert.Equal(t, 4.70, summary.CorrectedNet)
+	})
+
+	t.Run("breaks charges down by vehicle class", func(t *testing.T) {
+		ctx := newMockContext()
+		createCharge(t, ctx, "CHG-001")
+		createCharge(t, ctx, "CHG-002")
+		truck := validCharge()
+		truck.ChargeID = "CHG-003"
+		truck.VehicleClass = 5
+		truck.Amount = 18.00
+		truck.NetAmount = 17.95
+		createChargeWithStatus(t, ctx, truck, "pending")
+
+		summary, err := contract.GetChargeSummary(ctx, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, []*ChargeClassSummary{
+			{VehicleClass: 2, Description: "Two-axle passenger vehicle", ChargeCount: 2, GrossAmount: 9.50, NetAmount: 9.40},
+			{VehicleClass: 5, Description: "Five-axle vehicle", ChargeCount: 1, GrossAmount: 18.00, NetAmount: 17.95},
+		}, summary.Classes)
+	})
+
+	t.Run("counts only the requested direction", func(t *testing.T) {
+		ctx := newMockContext()
+		createCharge(t, ctx, "CHG-001")
+		reverse := validCharge()
+		reverse.ChargeID = "CHG-002"
+		reverse.AwayAgencyID, reverse.HomeAgencyID = "ORG1", "ORG2"
+		createChargeWithStatus(t, ctx, reverse, "pending")
+
+		summary, err := contract.GetChargeSummary(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "ORG1", summary.AwayAgencyID)
+		assert.Equal(t, 1, summary.ChargeCount)
+		assert.Equal(t, 4.70, summary.CorrectedNet)
+	})
+}
+
+func TestChargeCollectionNameSymmetry(t *testing.T) {
+	// This tests a critical business rule: collection names must be symmetric
+	// so both agencies can find the same data regardless of who queries
+
+	charge1 := &models.Charge{
+		AwayAgencyID: "ORG2",
+		HomeAgencyID: "ORG1",
+	}
+
+	charge2 := &models.Charge{
+		AwayAgencyID: "ORG1",
+		HomeAgencyID: "ORG2",
+	}
+
+	assert.Equal(t, charge1.CollectionName(), charge2.CollectionName())
+	assert.Equal(t, "charges_ORG1_ORG2", charge1.CollectionName())
+}
+
+func TestGetChargesByIDs(t *testing.T) {
+	contract := &ChargeContract{}
+
+	t.Run("returns present charges and missing IDs", func(t *testing.T) {
+		ctx := newMockContext()
+		for _, id := range []string{"CHG-001", "CHG-002", "CHG-003"} {
+			charge := validCharge()
+			charge.ChargeID = id
+			chargeJSON, _ := json.Marshal(charge)
+			require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+		}
+
+		result, err := contract.GetChargesByIDs(ctx, "ORG1", "ORG2", `["CHG-003","CHG-404","CHG-001","CHG-003"]`)
+		require.NoError(t, err)
+		require.Len(t, result.Charges, 2)
+		assert.Equal(t, "CHG-003", result.Charges[0].ChargeID)
+		assert.Equal(t, "CHG-001", result.Charges[1].ChargeID)
+		assert.Equal(t, []string{"CHG-404"}, result.MissingIDs)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		ctx := newMockContext()
+		result, err := contract.GetChargesByIDs(ctx, "ORG1", "ORG2", `[]`)
+		require.NoError(t, err)
+		assert.Empty(t, result.Charges)
+		assert.Empty(t, result.MissingIDs)
+	})
+
+	t.Run("accepts batch of max size", func(t *testing.T) {
+		ctx := newMockContext()
+		ids := make([]string, MaxBatchSize)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("CHG-%04d", i)
+		}
+		idsJSON, _ := json.Marshal(ids)
+
+		_, err := contract.GetChargesByIDs(ctx, "ORG1", "ORG2", string(idsJSON))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects oversized batch", func(t *testing.T) {
+		ctx := newMockContext()
+		ids := make([]string, MaxBatchSize+1)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("CHG-%04d", i)
+		}
+		idsJSON, _ := json.Marshal(ids)
+
+		_, err := contract.GetChargesByIDs(ctx, "ORG1", "ORG2", string(idsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("batch exceeds max size %d", MaxBatchSize))
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargesByIDs(ctx, "ORG1", "ORG2", `CHG-001`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse")
+	})
+}
+
+func TestGetChargesByStatus(t *testing.T) {
+	contract := &ChargeContract{}
+
+	t.Run("indexes charges on create", func(t *testing.T) {
+		ctx := newContextWithCharges(t,
+			chargeFixture{id: "CHG-1"},
+			chargeFixture{id: "CHG-2", status: "posted"},
+			chargeFixture{id: "CHG-3"},
+		)
+
+		pending, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "pending")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-1", "CHG-3"}, chargeIDs(pending))
+
+		posted, err := contract.GetChargesByStatus(ctx, "ORG2", "ORG1", "posted")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-2"}, chargeIDs(posted))
+	})
+
+	t.Run("moves index entry on transition", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-1"})
+
+		require.NoError(t, contract.UpdateChargeStatus(ctx, "CHG-1", "ORG2", "ORG1", "posted"))
+
+		pending, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "pending")
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		posted, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "posted")
+		require.NoError(t, err)
+		require.Len(t, posted, 1)
+		assert.Equal(t, "posted", posted[0].Status)
+
+		oldKey, _ := ctx.stub.CreateCompositeKey(chargeByStatusIndex, []string{"pending", "CHG-1"})
+		bytes, _ := ctx.stub.GetPrivateData("charges_ORG1_ORG2", oldKey)
+		assert.Nil(t, bytes)
+	})
+
+	t.Run("index entries are not returned as charges", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-1"})
+
+		charges, err := contract.GetChargesByAgencyPair(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Len(t, charges, 1)
+	})
+
+	t.Run("rejects invalid status", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "unknown")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+	})
+}
+
+func TestGetChargesByStatusSorted(t *testing.T) {
+	contract := &ChargeContract{}
+
+	// amounted sets a charge's amount, net of its fee, and exit time.
+	amounted := func(amount float64, exit string) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.Amount = amount
+			c.NetAmount = amount - c.Fee
+			c.ExitDateTime = exit
+		}
+	}
+	fixtures := []chargeFixture{
+		{id: "CHG-1", status: "posted", modify: amounted(7.25, "2026-01-15T09:00:00Z")},
+		{id: "CHG-2", status: "posted", modify: amounted(2.50, "2026-01-15T07:00:00Z")},
+		{id: "CHG-3", status: "posted", modify: amounted(4.75, "2026-01-15T08:00:00Z")},
+		{id: "CHG-4", modify: amounted(1.00, "2026-01-15T06:00:00Z")},
+	}
+
+	t.Run("sorts by amount", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		result, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "posted", "amount", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-2", "CHG-3", "CHG-1"}, chargeIDs(result))
+	})
+
+	t.Run("sorts by exit time and truncates", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		result, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "posted", "exitDateTime", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-2", "CHG-3"}, chargeIDs(result))
+	})
+
+	t.Run("unsorted and unlimited by default", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		result, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "posted", "", 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-1", "CHG-2", "CHG-3"}, chargeIDs(result))
+	})
+
+	t.Run("rejects unindexed sort field", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "posted", "plaza", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sortField")
+	})
+
+	t.Run("rejects negative limit", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "posted", "amount", -1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limit must be >= 0")
+	})
+
+	t.Run("rejects invalid status", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByStatusSorted(ctx, "ORG1", "ORG2", "unknown", "amount", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+	})
+}
+
+func TestGetChargesByProtocol(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-N1"},
+		{id: "CHG-N2"},
+		{id: "CHG-X1", modify: func(c *models.Charge) { c.Protocol = "native" }},
+	}
+
+	t.Run("filters by protocol", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		niop, err := contract.GetChargesByProtocol(ctx, "ORG1", "ORG2", "niop")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-N1", "CHG-N2"}, chargeIDs(niop))
+
+		native, err := contract.GetChargesByProtocol(ctx, "ORG2", "ORG1", "native")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-X1"}, chargeIDs(native))
+	})
+
+	t.Run("returns empty slice when none match", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		result, err := contract.GetChargesByProtocol(ctx, "ORG1", "ORG2", "iag")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("skips deleted charges", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-N1", "ORG2", "ORG1", "duplicate read"))
+
+		result, err := contract.GetChargesByProtocol(ctx, "ORG1", "ORG2", "niop")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-N2"}, chargeIDs(result))
+	})
+
+	t.Run("rejects invalid protocol", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargesByProtocol(ctx, "ORG1", "ORG2", "sego")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid protocol")
+	})
+}
+
+func TestGetChargesBySubmissionChannel(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-D1", modify: func(c *models.Charge) { c.SubmittedVia = "direct" }},
+		{id: "CHG-H1", modify: func(c *models.Charge) { c.SubmittedVia = "hub" }},
+		{id: "CHG-H2", modify: func(c *models.Charge) { c.SubmittedVia = "hub" }},
+		{id: "CHG-U1"},
+	}
+
+	t.Run("filters by channel", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+
+		hub, err := contract.GetChargesBySubmissionChannel(ctx, "ORG1", "ORG2", "hub")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-H1", "CHG-H2"}, chargeIDs(hub))
+
+		direct, err := contract.GetChargesBySubmissionChannel(ctx, "ORG2", "ORG1", "direct")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-D1"}, chargeIDs(direct))
+	})
+
+	t.Run("returns empty slice when none match", func(t *testing.T) {
+		ctx := newMockContext()
+		result, err := contract.GetChargesBySubmissionChannel(ctx, "ORG1", "ORG2", "hub")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("skips deleted charges", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-H1", "ORG2", "ORG1", "duplicate read"))
+
+		result, err := contract.GetChargesBySubmissionChannel(ctx, "ORG1", "ORG2", "hub")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-H2"}, chargeIDs(result))
+	})
+
+	t.Run("rejects invalid channel", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargesBySubmissionChannel(ctx, "ORG1", "ORG2", "email")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid submission channel")
+	})
+}
+
+func TestGetChargesModifiedSince(t *testing.T) {
+	contract := &ChargeContract{}
+
+	// Each charge's stored updatedAt is backdated, as though the charges
+	// were last written on different days.
+	updatedAt := map[string]string{
+		"CHG-OLD":    "2026-01-01T00:00:00Z",
+		"CHG-MID":    "2026-01-15T12:00:00Z",
+		"CHG-RECENT": "2026-02-01T00:00:00Z",
+	}
+
+	tests := []struct {
+		name  string
+		since string
+		write func(t *testing.T, ctx *enhancedMockContext)
+		want  []string
+	}{
+		{
+			name:  "returns charges written at since",
+			since: "2026-01-15T12:00:00Z",
+			want:  []string{"CHG-MID", "CHG-RECENT"},
+		},
+		{
+			name:  "returns charges written after since",
+			since: "2026-01-20T00:00:00Z",
+			want:  []string{"CHG-RECENT"},
+		},
+		{
+			name:  "normalizes since to UTC",
+			since: "2026-01-15T07:00:00-05:00",
+			want:  []string{"CHG-MID", "CHG-RECENT"},
+		},
+		{
+			name:  "includes charges updated since",
+			since: "2026-01-20T00:00:00Z",
+			write: func(t *testing.T, ctx *enhancedMockContext) {
+				require.NoError(t, contract.UpdateChargeStatus(ctx, "CHG-OLD", "ORG2", "ORG1", "posted"))
+			},
+			want: []string{"CHG-OLD", "CHG-RECENT"},
+		},
+		{
+			name:  "includes deleted charges",
+			since: "2026-01-20T00:00:00Z",
+			write: func(t *testing.T, ctx *enhancedMockContext) {
+				require.NoError(t, contract.DeleteCharge(ctx, "CHG-OLD", "ORG2", "ORG1", "duplicate read"))
+			},
+			want: []string{"CHG-OLD", "CHG-RECENT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContextWithCharges(t, chargeFixture{id: "CHG-OLD"}, chargeFixture{id: "CHG-MID"}, chargeFixture{id: "CHG-RECENT"})
+			for id, at := range updatedAt {
+				var stored map[string]interface{}
+				require.NoError(t, json.Unmarshal(ctx.stub.privateData["charges_ORG1_ORG2"]["CHARGE_"+id], &stored))
+				stored["updatedAt"] = at
+				bytes, err := json.Marshal(stored)
+				require.NoError(t, err)
+				ctx.stub.privateData["charges_ORG1_ORG2"]["CHARGE_"+id] = bytes
+			}
+			if tt.write != nil {
+				tt.write(t, ctx)
+			}
+
+			result, err := contract.GetChargesModifiedSince(ctx, "ORG2", "ORG1", tt.since)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, chargeIDs(result))
+		})
+	}
+
+	t.Run("returns empty slice when none match", func(t *testing.T) {
+		ctx := newMockContext()
+		result, err := contract.GetChargesModifiedSince(ctx, "ORG1", "ORG2", "2026-01-01T00:00:00Z")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("rejects invalid timestamp", func(t *testing.T) {
+		ctx := newMockContext()
+		for _, since := range []string{"", "2026-01-01", "yesterday"} {
+			_, err := contract.GetChargesModifiedSince(ctx, "ORG1", "ORG2", since)
+			require.Error(t, err, since)
+			assert.Contains(t, err.Error(), "must be an RFC 3339 timestamp")
+		}
+	})
+}
+
+func TestCreateCharge_RejectsInvalidSubmittedVia(t *testing.T) {
+	ctx := newMockContext()
+	charge := validCharge()
+	charge.SubmittedVia = "fax"
+	chargeJSON, _ := json.Marshal(charge)
+
+	err := (&ChargeContract{}).CreateCharge(ctx, string(chargeJSON))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid submittedVia "fax"`)
+}
+
+func TestGetAgingUnreconciledCharges(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-OLD-1", modify: exitingAt("2026-01-05T08:00:00Z")},
+		{id: "CHG-OLD-2", modify: exitingAt("2026-01-20T08:00:00Z")},
+		{id: "CHG-OLD-RECON", modify: exitingAt("2026-01-10T08:00:00Z")},
+		{id: "CHG-EDGE", modify: exitingAt("2026-01-31T00:00:00Z")},
+		{id: "CHG-NEW", modify: exitingAt("2026-02-25T08:00:00Z")},
+	}
+
+	t.Run("returns unreconciled charges older than the limit", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		recon := validReconciliation()
+		recon.ChargeID = "CHG-OLD-RECON"
+		reconJSON, _ := json.Marshal(recon)
+		require.NoError(t, (&ReconciliationContract{}).CreateReconciliation(ctx, string(reconJSON)))
+
+		charges, err := contract.GetAgingUnreconciledCharges(ctx, "ORG1", "ORG2", "2026-03-02", 30)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-OLD-1", "CHG-OLD-2"}, chargeIDs(charges))
+	})
+
+	t.Run("compares offset exit times as instants", func(t *testing.T) {
+		// The cutoff is 2026-01-31T00:00:00Z.
+		ctx := newContextWithCharges(t,
+			chargeFixture{id: "CHG-EAST", modify: exitingAt("2026-01-31T02:00:00+05:00")},
+			chargeFixture{id: "CHG-WEST", modify: exitingAt("2026-01-30T20:00:00-08:00")},
+		)
+
+		charges, err := contract.GetAgingUnreconciledCharges(ctx, "ORG1", "ORG2", "2026-03-02", 30)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-EAST"}, chargeIDs(charges))
+	})
+
+	t.Run("returns empty slice when nothing is old enough", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetAgingUnreconciledCharges(ctx, "ORG1", "ORG2", "2026-01-06", 30)
+		require.NoError(t, err)
+		assert.NotNil(t, charges)
+		assert.Empty(t, charges)
+	})
+
+	t.Run("rejects invalid inputs", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetAgingUnreconciledCharges(ctx, "ORG1", "ORG2", "2026-03-02T00:00:00Z", 30)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid asOfDate")
+
+		_, err = contract.GetAgingUnreconciledCharges(ctx, "ORG1", "ORG2", "2026-03-02", -1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maxAgeDays must be >= 0")
+	})
+}
+
+func TestGetFacilityChargeCount(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-1", modify: exitingAt("2026-01-15T07:59:59Z")},
+		{id: "CHG-2", modify: exitingAt("2026-01-15T08:00:00Z")},
+		{id: "CHG-3", modify: exitingAt("2026-01-15T08:30:00Z")},
+		{id: "CHG-4", modify: exitingAt("2026-01-15T08:59:59Z")},
+		{id: "CHG-5", modify: exitingAt("2026-01-15T09:00:00Z")},
+		{id: "CHG-6", modify: func(c *models.Charge) {
+			c.FacilityID = "SR241"
+			c.ExitDateTime = "2026-01-15T08:15:00Z"
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       int
+		wantErr    string
+	}{
+		{"counts charges at the facility within the window", "2026-01-15T08:00:00Z", "2026-01-15T09:00:00Z", 3, ""},
+		{"accepts offset timestamps", "2026-01-15T00:00:00-08:00", "2026-01-15T01:00:00-08:00", 3, ""},
+		{"returns zero for an empty window", "2026-01-16T08:00:00Z", "2026-01-16T09:00:00Z", 0, ""},
+		{"rejects invalid start", "2026-01-15", "2026-01-15T09:00:00Z", 0, "invalid start"},
+		{"rejects end before start", "2026-01-15T09:00:00Z", "2026-01-15T08:00:00Z", 0, "must be after start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContextWithCharges(t, fixtures...)
+			count, err := contract.GetFacilityChargeCount(ctx, "ORG1", "ORG2", "SR73", tt.start, tt.end)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+}
+
+func TestPurgeCharge(t *testing.T) {
+	contract := &ChargeContract{}
+
+	agedCharge := func(days int) *models.Charge {
+		charge := validCharge()
+		charge.ExitDateTime = time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339)
+		return charge
+	}
+
+	t.Run("purges a settled charge past retention", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ChargeRetentionDays = 365 })
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, agedCharge(400), "settled")
+		ctx.stub.events = nil
+
+		require.NoError(t, contract.PurgeCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1"))
+
+		_, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+
+		settled, err := contract.GetChargesByStatus(ctx, "ORG1", "ORG2", "settled")
+		require.NoError(t, err)
+		assert.Empty(t, settled, "status index entry should be purged")
+
+		require.Len(t, ctx.stub.events, 1)
+		assert.Equal(t, "ChargePurged", ctx.stub.events[0].name)
+		var event ChargePurgedEvent
+		require.NoError(t, json.Unmarshal(ctx.stub.events[0].payload, &event))
+		assert.Equal(t, "CHG-TEST-001", event.ChargeID)
+		assert.Equal(t, "ORG1", event.HomeAgencyID)
+	})
+
+	t.Run("rejects a charge inside the retention period", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ChargeRetentionDays = 365 })
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, agedCharge(30), "settled")
+
+		err := contract.PurgeCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "retention period is 365 days")
+	})
+
+	t.Run("rejects a charge that is not settled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ChargeRetentionDays = 365 })
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, agedCharge(400), "posted")
+
+		err := contract.PurgeCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only settled charges can be purged")
+	})
+
+	t.Run("is disabled without a retention period", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ChargeRetentionDays = 0 })
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, agedCharge(400), "settled")
+
+		err := contract.PurgeCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "purging is disabled")
+	})
+}
+
+func TestGetChargesByAmountRange(t *testing.T) {
+	contract := &ChargeContract{}
+
+	// Fees are zeroed so the amounts can be set freely.
+	priced := func(amount float64) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.Amount = amount
+			c.Fee = 0
+			c.NetAmount = amount
+		}
+	}
+	fixtures := []chargeFixture{
+		{id: "CHG-1", modify: priced(4.75)},
+		{id: "CHG-2", modify: priced(100.00)},
+		{id: "CHG-3", modify: priced(150.25)},
+		{id: "CHG-4", modify: priced(250.00)},
+		{id: "CHG-5", modify: priced(250.01)},
+	}
+
+	t.Run("includes both bounds", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByAmountRange(ctx, "ORG1", "ORG2", 100, 250)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-2", "CHG-3", "CHG-4"}, chargeIDs(charges))
+	})
+
+	t.Run("returns empty slice for an empty range", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByAmountRange(ctx, "ORG1", "ORG2", 5, 99.99)
+		require.NoError(t, err)
+		assert.NotNil(t, charges)
+		assert.Empty(t, charges)
+	})
+
+	t.Run("rejects invalid bounds", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetChargesByAmountRange(ctx, "ORG1", "ORG2", -1, 10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "minAmount must be >= 0")
+
+		_, err = contract.GetChargesByAmountRange(ctx, "ORG1", "ORG2", 10, 5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not be less than minAmount")
+	})
+}
+
+func TestGetChargesByRole(t *testing.T) {
+	contract := &ChargeContract{}
+
+	fixtures := []chargeFixture{
+		{id: "CHG-1"},
+		{id: "CHG-2"},
+		{id: "CHG-3", modify: func(c *models.Charge) { c.AwayAgencyID, c.HomeAgencyID = "ORG1", "ORG2" }},
+	}
+
+	t.Run("home returns charges the agency owes", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByRole(ctx, "ORG1", "ORG2", "ORG1", "home")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-1", "CHG-2"}, chargeIDs(charges))
+	})
+
+	t.Run("away returns charges the agency submitted", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByRole(ctx, "ORG1", "ORG2", "ORG1", "away")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-3"}, chargeIDs(charges))
+	})
+
+	t.Run("rejects invalid role", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByRole(ctx, "ORG1", "ORG2", "ORG1", "payor")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid role")
+	})
+
+	t.Run("rejects agency outside the pair", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByRole(ctx, "ORG1", "ORG2", "ORG3", "home")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not part of the pair")
+	})
+}
+
+func TestGetChargesByPlate(t *testing.T) {
+	contract := &ChargeContract{}
+
+	plated := func(state, number string) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.PlateCountry = "US"
+			c.PlateState = state
+			c.PlateNumber = number
+		}
+	}
+	video := func(state, number string) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.ChargeType = "toll_video"
+			c.RecordType = "VB01"
+			c.TagSerialNumber = ""
+			plated(state, number)(c)
+		}
+	}
+	fixtures := []chargeFixture{
+		{id: "CHG-V1", modify: video("CA", "7ABC123")},
+		{id: "CHG-V2", status: "posted", modify: video("ca", "7abc123")},
+		{id: "CHG-V3", modify: video("CA", "8XYZ999")},
+		{id: "CHG-V4", modify: video("NV", "7ABC123")},
+		// A tag charge that happens to carry the same plate.
+		{id: "CHG-T1", modify: plated("CA", "7ABC123")},
+	}
+
+	t.Run("matches the plate in any status and case", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByPlate(ctx, "ORG1", "ORG2", "us", "Ca", "7abc123")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-V1", "CHG-V2"}, chargeIDs(charges))
+	})
+
+	t.Run("returns empty slice for an unknown plate", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		charges, err := contract.GetChargesByPlate(ctx, "ORG1", "ORG2", "US", "CA", "NOPE")
+		require.NoError(t, err)
+		assert.NotNil(t, charges)
+		assert.Empty(t, charges)
+	})
+
+	t.Run("requires plate number", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByPlate(ctx, "ORG1", "ORG2", "US", "CA", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateNumber is required")
+	})
+
+	t.Run("requires plate country", func(t *testing.T) {
+		ctx := newContextWithCharges(t, fixtures...)
+		_, err := contract.GetChargesByPlate(ctx, "ORG1", "ORG2", "", "CA", "7ABC123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateCountry is required")
+	})
+}
+
+func TestGetChargesByEntryPlaza(t *testing.T) {
+	contract := &ChargeContract{}
+
+	enteringAt := func(plaza string) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.EntryPlaza = plaza
+			c.EntryDateTime = "2026-01-15T08:10:00Z"
+		}
+	}
+	setup := func(t *testing.T) *enhancedMockContext {
+		ctx := newContextWithCharges(t,
+			chargeFixture{id: "CHG-SEG-1", modify: enteringAt("LAGUNA")},
+			chargeFixture{id: "CHG-SEG-2", status: "posted", modify: enteringAt("LAGUNA")},
+			chargeFixture{id: "CHG-SEG-3", modify: enteringAt("IRVINE")},
+			chargeFixture{id: "CHG-POINT"},
+			chargeFixture{id: "CHG-SEG-DEL", modify: enteringAt("LAGUNA")},
+		)
+		require.NoError(t, contract.DeleteCharge(ctx, "CHG-SEG-DEL", "ORG2", "ORG1", "duplicate read"))
+		return ctx
+	}
+
+	t.Run("returns segment charges from the plaza", func(t *testing.T) {
+		ctx := setup(t)
+		charges, err := contract.GetChargesByEntryPlaza(ctx, "ORG1", "ORG2", "LAGUNA")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-SEG-1", "CHG-SEG-2"}, chargeIDs(charges))
+	})
+
+	t.Run("returns empty slice for an unknown plaza", func(t *testing.T) {
+		ctx := setup(t)
+		charges, err := contract.GetChargesByEntryPlaza(ctx, "ORG1", "ORG2", "CATALINA")
+		require.NoError(t, err)
+		assert.NotNil(t, charges)
+		assert.Empty(t, charges)
+	})
+
+	t.Run("requires entry plaza", func(t *testing.T) {
+		ctx := setup(t)
+		_, err := contract.GetChargesByEntryPlaza(ctx, "ORG1", "ORG2", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "entryPlaza is required")
+	})
 }
@@ -19,6 +19,12 @@
 //   - CHAINCODE_TLS_CERT: Path to TLS certificate file
 //   - CHAINCODE_TLS_CLIENT_CA_CERT: Path to client CA certificate for mutual TLS
 //
+// Business rules are tuned with NIOP_* environment variables in either mode;
+// see niop.ConfigFromEnv for the full list. NIOP_VEHICLE_CLASSES replaces the
+// vehicle class table (see niop.VehicleClassesFromEnv) and
+// NIOP_VEHICLE_CLASS_REMAPS the classes a tag's charges may be assessed at
+// (see niop.VehicleClassRemapsFromEnv).
+//
 // Build with: go build -o niop ./cmd
 package main
 
@@ -33,17 +39,39 @@ import (
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop"
 )
 
+// version identifies the build. Release builds set it with
+// -ldflags "-X main.version=1.0"; see ccaas/Dockerfile.
+var version = "dev"
+
 func main() {
+	// Apply business-rule overrides before any transaction is served
+	niop.SetConfig(niop.ConfigFromEnv())
+	if err := niop.VehicleClassesFromEnv(); err != nil {
+		log.Panicf("Error loading vehicle classes: %v", err)
+	}
+	if err := niop.VehicleClassRemapsFromEnv(); err != nil {
+		log.Panicf("Error loading vehicle class remaps: %v", err)
+	}
+	if err := niop.ProtocolTagProtocolsFromEnv(); err != nil {
+		log.Panicf("Error loading tag protocols: %v", err)
+	}
+	if err := niop.MaxBatchSizeFromEnv(); err != nil {
+		log.Panicf("Error loading max batch size: %v", err)
+	}
+	if err := niop.AllowUnknownPlateCountriesFromEnv(); err != nil {
+		log.Panicf("Error loading plate country setting: %v", err)
+	}
+	if err := niop.DualEndorsementPairsFromEnv(); err != nil {
+		log.Panicf("Error loading dual-endorsement pairs: %v", err)
+	}
+	if err := niop.SettlementApproversFromEnv(); err != nil {
+		log.Panicf("Error loading settlement approvers: %v", err)
+	}
+
+	niop.Version = version
+
 	// Create chaincode with all contracts
-	chaincode, err := contractapi.NewChaincode(
-		&niop.AgencyContract{},
-		&niop.TagContract{},
-		&niop.ChargeContract{},
-		&niop.CorrectionContract{},
-		&niop.ReconciliationContract{},
-		&niop.AcknowledgementContract{},
-		&niop.SettlementContract{},
-	)
+	chaincode, err := contractapi.NewChaincode(niop.Contracts()...)
 	if err != nil {
 		log.Panicf("Error creating NIOP chaincode: %v", err)
 	}
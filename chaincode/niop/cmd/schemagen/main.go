@@ -0,0 +1,38 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+// Command schemagen writes a JSON Schema for each NIOP ledger document, for
+// integrators building off-chain clients. Enum constraints come from the
+// same lists the chaincode validates against, so regenerate the schemas
+// whenever a model or one of its Valid* lists changes.
+//
+// Run from chaincode/niop with: go run ./cmd/schemagen [-out schemas]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+func main() {
+	out := flag.String("out", "schemas", "directory to write the schema files to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatalf("Error creating %s: %v", *out, err)
+	}
+	for name, schema := range models.JSONSchemas() {
+		bytes, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			log.Fatalf("Error encoding %s schema: %v", name, err)
+		}
+		path := filepath.Join(*out, name+".schema.json")
+		if err := os.WriteFile(path, append(bytes, '\n'), 0o644); err != nil {
+			log.Fatalf("Error writing %s: %v", path, err)
+		}
+	}
+}
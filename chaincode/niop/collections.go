@@ -0,0 +1,153 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// CollectionTemplate holds the settings applied to every generated bilateral
+// collection. PolicyFormat is a fmt format string that receives the two agency
+// IDs of a pair in sorted order.
+type CollectionTemplate struct {
+	PolicyFormat      string
+	RequiredPeerCount int
+	MaxPeerCount      int
+	BlockToLive       uint64
+}
+
+// DefaultCollectionTemplate returns the settings used for local development,
+// matching scripts/generate-collections.sh.
+func DefaultCollectionTemplate() CollectionTemplate {
+	return CollectionTemplate{
+		PolicyFormat:      "OR('%sMSP.member', '%sMSP.member')",
+		RequiredPeerCount: 1,
+		MaxPeerCount:      2,
+		BlockToLive:       0,
+	}
+}
+
+// collectionDefinition is one entry in a Fabric collections config file.
+type collectionDefinition struct {
+	Name              string `json:"name"`
+	Policy            string `json:"policy"`
+	RequiredPeerCount int    `json:"requiredPeerCount"`
+	MaxPeerCount      int    `json:"maxPeerCount"`
+	BlockToLive       uint64 `json:"blockToLive"`
+	MemberOnlyRead    bool   `json:"memberOnlyRead"`
+	MemberOnlyWrite   bool   `json:"memberOnlyWrite"`
+
+	EndorsementPolicy *collectionEndorsementPolicy `json:"endorsementPolicy,omitempty"`
+}
+
+// collectionEndorsementPolicy overrides the chaincode's endorsement policy
+// for writes to one collection.
+type collectionEndorsementPolicy struct {
+	SignaturePolicy string `json:"signaturePolicy"`
+}
+
+// GenerateCollectionsConfig returns a Fabric collections_config.json declaring
+// one bilateral charges collection for every pair of agencies: N*(N-1)/2
+// collections for N agencies. Collections are ordered by name so the output is
+// stable regardless of the order agencyIDs are given in.
+//
+// The collection of a pair flagged by SetDualEndorsementPairs gets an
+// endorsement policy requiring a member of both agencies' organizations, so
+// that every write to it, creating a charge included, needs both agencies.
+func GenerateCollectionsConfig(agencyIDs []string, template CollectionTemplate) ([]byte, error) {
+	sorted := append([]string(nil), agencyIDs...)
+	sort.Strings(sorted)
+	for i, id := range sorted {
+		if id == "" {
+			return nil, fmt.Errorf("agency IDs must not be empty")
+		}
+		if err := models.ValidateAgencyID("agencyID", id); err != nil {
+			return nil, err
+		}
+		if i > 0 && sorted[i-1] == id {
+			return nil, fmt.Errorf("duplicate agency ID %q", id)
+		}
+	}
+	if len(sorted) < 2 {
+		return nil, fmt.Errorf("at least 2 agencies are required, got %d", len(sorted))
+	}
+	if template.RequiredPeerCount < 0 || template.MaxPeerCount < template.RequiredPeerCount {
+		return nil, fmt.Errorf("maxPeerCount %d must be >= requiredPeerCount %d >= 0", template.MaxPeerCount, template.RequiredPeerCount)
+	}
+
+	var collections []collectionDefinition
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			name := models.BilateralCollectionName(sorted[i], sorted[j])
+			var endorsementPolicy *collectionEndorsementPolicy
+			if dualEndorsementCollections[name] {
+				endorsementPolicy = &collectionEndorsementPolicy{
+					SignaturePolicy: fmt.Sprintf("AND('%s.member', '%s.member')", agencyMSPID(sorted[i]), agencyMSPID(sorted[j])),
+				}
+			}
+			collections = append(collections, collectionDefinition{
+				Name:              name,
+				Policy:            fmt.Sprintf(template.PolicyFormat, sorted[i], sorted[j]),
+				RequiredPeerCount: template.RequiredPeerCount,
+				MaxPeerCount:      template.MaxPeerCount,
+				BlockToLive:       template.BlockToLive,
+				MemberOnlyRead:    true,
+				MemberOnlyWrite:   true,
+				EndorsementPolicy: endorsementPolicy,
+			})
+		}
+	}
+
+	bytes, err := json.MarshalIndent(collections, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal collections config: %w", err)
+	}
+	return append(bytes, '\n'), nil
+}
+
+// collectionProbeKey is the key MissingCollections reads from each collection.
+// Nothing is stored under it; only the read's error matters.
+const collectionProbeKey = "COLLECTION_PROBE"
+
+// MissingCollections returns the bilateral collections for pairs of agencyIDs
+// that the chaincode's collection configuration does not define, ordered by
+// name. Each collection is probed with GetPrivateDataHash, which succeeds on
+// any defined collection whether or not this peer is a member of it.
+func MissingCollections(ctx contractapi.TransactionContextInterface, agencyIDs []string) ([]string, error) {
+	var sorted []string
+	seen := make(map[string]bool, len(agencyIDs))
+	for _, id := range agencyIDs {
+		if !seen[id] {
+			seen[id] = true
+			sorted = append(sorted, id)
+		}
+	}
+	sort.Strings(sorted)
+
+	missing := []string{}
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			collection := models.BilateralCollectionName(sorted[i], sorted[j])
+			if _, err := ctx.GetStub().GetPrivateDataHash(collection, collectionProbeKey); err != nil {
+				if !isUndefinedCollection(err) {
+					return nil, fmt.Errorf("failed to probe collection %s: %w", collection, err)
+				}
+				missing = append(missing, collection)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// isUndefinedCollection reports whether err is the peer's error for a
+// collection missing from the chaincode's collection configuration.
+func isUndefinedCollection(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "could not be found") || strings.Contains(msg, "no such collection")
+}
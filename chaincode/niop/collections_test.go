@@ -0,0 +1,149 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCollectionsConfig(t *testing.T) {
+	decode := func(t *testing.T, bytes []byte) []collectionDefinition {
+		var collections []collectionDefinition
+		require.NoError(t, json.Unmarshal(bytes, &collections))
+		return collections
+	}
+
+	t.Run("three agencies yield three pairs", func(t *testing.T) {
+		bytes, err := GenerateCollectionsConfig([]string{"TCA", "E470", "HCTRA"}, DefaultCollectionTemplate())
+		require.NoError(t, err)
+
+		collections := decode(t, bytes)
+		require.Len(t, collections, 3)
+		assert.Equal(t, "charges_E470_HCTRA", collections[0].Name)
+		assert.Equal(t, "charges_E470_TCA", collections[1].Name)
+		assert.Equal(t, "charges_HCTRA_TCA", collections[2].Name)
+		assert.Equal(t, "OR('HCTRAMSP.member', 'TCAMSP.member')", collections[2].Policy)
+		for _, c := range collections {
+			assert.Equal(t, 1, c.RequiredPeerCount)
+			assert.Equal(t, 2, c.MaxPeerCount)
+			assert.True(t, c.MemberOnlyRead)
+			assert.True(t, c.MemberOnlyWrite)
+		}
+	})
+
+	t.Run("four agencies match the network config", func(t *testing.T) {
+		bytes, err := GenerateCollectionsConfig([]string{"Org4", "Org3", "Org2", "Org1"}, DefaultCollectionTemplate())
+		require.NoError(t, err)
+		require.Len(t, decode(t, bytes), 6)
+
+		expected, err := os.ReadFile("../../network-config/collections/collections_config.json")
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(bytes))
+	})
+
+	t.Run("applies template settings", func(t *testing.T) {
+		template := CollectionTemplate{
+			PolicyFormat:      "AND('%sMSP.peer', '%sMSP.peer')",
+			RequiredPeerCount: 2,
+			MaxPeerCount:      4,
+			BlockToLive:       1000,
+		}
+		bytes, err := GenerateCollectionsConfig([]string{"Org1", "Org2"}, template)
+		require.NoError(t, err)
+
+		collections := decode(t, bytes)
+		require.Len(t, collections, 1)
+		assert.Equal(t, "AND('Org1MSP.peer', 'Org2MSP.peer')", collections[0].Policy)
+		assert.Equal(t, 2, collections[0].RequiredPeerCount)
+		assert.Equal(t, 4, collections[0].MaxPeerCount)
+		assert.Equal(t, uint64(1000), collections[0].BlockToLive)
+	})
+
+	t.Run("flagged pairs need both agencies to endorse", func(t *testing.T) {
+		withDualEndorsementPairs(t, []string{"Org2", "Org1"})
+		bytes, err := GenerateCollectionsConfig([]string{"Org1", "Org2", "Org3"}, DefaultCollectionTemplate())
+		require.NoError(t, err)
+
+		collections := decode(t, bytes)
+		require.Len(t, collections, 3)
+		assert.Equal(t, "charges_Org1_Org2", collections[0].Name)
+		require.NotNil(t, collections[0].EndorsementPolicy)
+		assert.Equal(t, "AND('Org1MSP.member', 'Org2MSP.member')", collections[0].EndorsementPolicy.SignaturePolicy)
+		assert.Nil(t, collections[1].EndorsementPolicy)
+		assert.Nil(t, collections[2].EndorsementPolicy)
+		assert.NotContains(t, string(bytes), `"endorsementPolicy": null`)
+	})
+
+	t.Run("rejects fewer than two agencies", func(t *testing.T) {
+		_, err := GenerateCollectionsConfig([]string{"Org1"}, DefaultCollectionTemplate())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least 2 agencies")
+	})
+
+	t.Run("rejects duplicate agencies", func(t *testing.T) {
+		_, err := GenerateCollectionsConfig([]string{"Org1", "Org2", "Org1"}, DefaultCollectionTemplate())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate agency ID")
+	})
+
+	t.Run("rejects malformed agency IDs", func(t *testing.T) {
+		_, err := GenerateCollectionsConfig([]string{"Org1", "Org_2"}, DefaultCollectionTemplate())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "agencyID contains invalid characters")
+	})
+
+	t.Run("rejects inconsistent peer counts", func(t *testing.T) {
+		template := DefaultCollectionTemplate()
+		template.MaxPeerCount = 0
+		_, err := GenerateCollectionsConfig([]string{"Org1", "Org2"}, template)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maxPeerCount")
+	})
+}
+
+// undefinedCollectionError mimics the peer's error for a collection missing
+// from the collection configuration.
+func undefinedCollectionError(collection string) error {
+	return fmt.Errorf("collection tolling/niop/%s could not be found", collection)
+}
+
+func TestMissingCollections(t *testing.T) {
+	t.Run("returns none when every pair is defined", func(t *testing.T) {
+		ctx := newMockContext()
+
+		missing, err := MissingCollections(ctx, []string{"ORG1", "ORG2", "ORG3"})
+		require.NoError(t, err)
+		assert.NotNil(t, missing)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("lists undefined collections in name order", func(t *testing.T) {
+		ctx := newMockContext()
+		ctx.stub.collectionErrors = map[string]error{
+			"charges_ORG2_ORG3": undefinedCollectionError("charges_ORG2_ORG3"),
+			"charges_ORG1_ORG3": undefinedCollectionError("charges_ORG1_ORG3"),
+		}
+
+		missing, err := MissingCollections(ctx, []string{"ORG3", "ORG1", "ORG2", "ORG1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"charges_ORG1_ORG3", "charges_ORG2_ORG3"}, missing)
+	})
+
+	t.Run("returns other probe errors", func(t *testing.T) {
+		ctx := newMockContext()
+		ctx.stub.collectionErrors = map[string]error{
+			"charges_ORG1_ORG2": errors.New("connection reset"),
+		}
+
+		_, err := MissingCollections(ctx, []string{"ORG1", "ORG2"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to probe collection charges_ORG1_ORG2: connection reset")
+	})
+}
@@ -0,0 +1,269 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// Config holds the tunable business rules applied by the contracts.
+// The chaincode starts with DefaultConfig; cmd/main.go replaces it with
+// ConfigFromEnv before the chaincode starts serving transactions.
+type Config struct {
+	// AutoDisputeOnAmountMismatch moves a charge to "disputed" when its
+	// reconciliation posts an amount that differs from the charged amount.
+	AutoDisputeOnAmountMismatch bool
+
+	// RejectCorrectionSequenceGaps rejects a correction whose sequence number
+	// skips past the next unused number for its charge.
+	RejectCorrectionSequenceGaps bool
+
+	// ComputeChargeFees computes a new charge's fee and net amount from the
+	// agencies' fee schedule, rejecting submitted values that disagree.
+	ComputeChargeFees bool
+
+	// CheckAckReturnMessages rejects an acknowledgement whose return message
+	// contradicts its return code (see models.Acknowledgement.ValidateReturnMessage).
+	CheckAckReturnMessages bool
+
+	// RequirePayByPlatePlates rejects a toll_paybyplate charge without
+	// plate details, even when its record type is tag-based (see
+	// models.Charge.ValidatePayByPlate).
+	RequirePayByPlatePlates bool
+
+	// StrictMode enables validation that reads other ledger entries, such as
+	// checking that an agency's hub exists and can route for it, or that a
+	// tag-based charge's vehicle class and protocol agree with its tag.
+	StrictMode bool
+
+	// ReportAllValidationErrors makes the contracts reject an invalid
+	// entity with every validation failure (models.ValidationErrors) rather
+	// than only the first.
+	ReportAllValidationErrors bool
+
+	// RequireCollections makes InitLedger fail when the collection
+	// configuration lacks a bilateral collection for a pair of agencies on
+	// the ledger, instead of only logging a warning.
+	RequireCollections bool
+
+	// ChargeRetentionDays is how long after its exit date a settled charge
+	// must be kept before PurgeCharge may remove it. Zero disables purging.
+	ChargeRetentionDays int
+}
+
+// DefaultConfig returns the configuration used when nothing is overridden.
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// activeConfig is the configuration consulted by the contracts.
+var activeConfig = DefaultConfig()
+
+// SetConfig replaces the active configuration.
+// Call it during chaincode startup, before any transaction is served.
+func SetConfig(cfg Config) {
+	activeConfig = cfg
+}
+
+// CurrentConfig returns the active configuration.
+func CurrentConfig() Config {
+	return activeConfig
+}
+
+// ConfigFromEnv builds a Config from environment variables, starting from
+// DefaultConfig. Unset or unparseable variables keep their default.
+//
+// Environment variables:
+//   - NIOP_AUTO_DISPUTE_ON_MISMATCH: "true" to enable AutoDisputeOnAmountMismatch
+//   - NIOP_REJECT_CORRECTION_GAPS: "true" to enable RejectCorrectionSequenceGaps
+//   - NIOP_COMPUTE_CHARGE_FEES: "true" to enable ComputeChargeFees
+//   - NIOP_CHECK_ACK_MESSAGES: "true" to enable CheckAckReturnMessages
+//   - NIOP_REQUIRE_PAYBYPLATE_PLATES: "true" to enable RequirePayByPlatePlates
+//   - NIOP_STRICT_MODE: "true" to enable StrictMode
+//   - NIOP_REPORT_ALL_VALIDATION_ERRORS: "true" to enable ReportAllValidationErrors
+//   - NIOP_REQUIRE_COLLECTIONS: "true" to enable RequireCollections
+//   - NIOP_CHARGE_RETENTION_DAYS: days to set ChargeRetentionDays to
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	cfg.AutoDisputeOnAmountMismatch = envBool("NIOP_AUTO_DISPUTE_ON_MISMATCH", cfg.AutoDisputeOnAmountMismatch)
+	cfg.RejectCorrectionSequenceGaps = envBool("NIOP_REJECT_CORRECTION_GAPS", cfg.RejectCorrectionSequenceGaps)
+	cfg.ComputeChargeFees = envBool("NIOP_COMPUTE_CHARGE_FEES", cfg.ComputeChargeFees)
+	cfg.CheckAckReturnMessages = envBool("NIOP_CHECK_ACK_MESSAGES", cfg.CheckAckReturnMessages)
+	cfg.RequirePayByPlatePlates = envBool("NIOP_REQUIRE_PAYBYPLATE_PLATES", cfg.RequirePayByPlatePlates)
+	cfg.StrictMode = envBool("NIOP_STRICT_MODE", cfg.StrictMode)
+	cfg.ReportAllValidationErrors = envBool("NIOP_REPORT_ALL_VALIDATION_ERRORS", cfg.ReportAllValidationErrors)
+	cfg.RequireCollections = envBool("NIOP_REQUIRE_COLLECTIONS", cfg.RequireCollections)
+	cfg.ChargeRetentionDays = envInt("NIOP_CHARGE_RETENTION_DAYS", cfg.ChargeRetentionDays)
+	return cfg
+}
+
+// VehicleClassesFromEnv replaces models.VehicleClasses with the set in the
+// NIOP_VEHICLE_CLASSES environment variable, a JSON object keyed by class
+// number, e.g. {"1":{"description":"Motorcycle","axles":2}}. It leaves the
+// built-in set in place when the variable is unset, and returns an error when
+// the value cannot be parsed or is not a valid set.
+func VehicleClassesFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_VEHICLE_CLASSES")
+	if !ok {
+		return nil
+	}
+	var classes map[int]models.VehicleClass
+	if err := json.Unmarshal([]byte(val), &classes); err != nil {
+		return fmt.Errorf("failed to parse NIOP_VEHICLE_CLASSES: %w", err)
+	}
+	if err := models.SetVehicleClasses(classes); err != nil {
+		return fmt.Errorf("invalid NIOP_VEHICLE_CLASSES: %w", err)
+	}
+	return nil
+}
+
+// VehicleClassRemapsFromEnv replaces models.VehicleClassRemaps with the
+// remaps in the NIOP_VEHICLE_CLASS_REMAPS environment variable, a JSON object
+// mapping a tag class to the vehicle classes its charges may use, e.g.
+// {"2":[3]}. Call it after VehicleClassesFromEnv. It leaves the remaps empty
+// when the variable is unset, and returns an error when the value cannot be
+// parsed or names an unknown class.
+func VehicleClassRemapsFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_VEHICLE_CLASS_REMAPS")
+	if !ok {
+		return nil
+	}
+	var remaps map[int][]int
+	if err := json.Unmarshal([]byte(val), &remaps); err != nil {
+		return fmt.Errorf("failed to parse NIOP_VEHICLE_CLASS_REMAPS: %w", err)
+	}
+	if err := models.SetVehicleClassRemaps(remaps); err != nil {
+		return fmt.Errorf("invalid NIOP_VEHICLE_CLASS_REMAPS: %w", err)
+	}
+	return nil
+}
+
+// ProtocolTagProtocolsFromEnv replaces models.ProtocolTagProtocols with the
+// mapping in the NIOP_TAG_PROTOCOLS environment variable, a JSON object
+// mapping a charge protocol to the tag protocols its charges may be read
+// from, e.g. {"iag":["tdm","6c"]}. It leaves the built-in mapping in place
+// when the variable is unset, and returns an error when the value cannot be
+// parsed or names an unknown protocol.
+func ProtocolTagProtocolsFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_TAG_PROTOCOLS")
+	if !ok {
+		return nil
+	}
+	var mapping map[string][]string
+	if err := json.Unmarshal([]byte(val), &mapping); err != nil {
+		return fmt.Errorf("failed to parse NIOP_TAG_PROTOCOLS: %w", err)
+	}
+	if err := models.SetProtocolTagProtocols(mapping); err != nil {
+		return fmt.Errorf("invalid NIOP_TAG_PROTOCOLS: %w", err)
+	}
+	return nil
+}
+
+// DualEndorsementPairsFromEnv flags the agency pairs in the
+// NIOP_DUAL_ENDORSEMENT_PAIRS environment variable, a JSON array of agency
+// ID pairs such as [["TCA","BATA"]], as needing both agencies to endorse
+// their charges (see SetDualEndorsementPairs). It flags no pairs when the
+// variable is unset, and returns an error when the value cannot be parsed or
+// names an invalid pair.
+func DualEndorsementPairsFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_DUAL_ENDORSEMENT_PAIRS")
+	if !ok {
+		return nil
+	}
+	var pairs [][]string
+	if err := json.Unmarshal([]byte(val), &pairs); err != nil {
+		return fmt.Errorf("failed to parse NIOP_DUAL_ENDORSEMENT_PAIRS: %w", err)
+	}
+	if err := SetDualEndorsementPairs(pairs); err != nil {
+		return fmt.Errorf("invalid NIOP_DUAL_ENDORSEMENT_PAIRS: %w", err)
+	}
+	return nil
+}
+
+// SettlementApproversFromEnv sets the settlement approvers in the
+// NIOP_SETTLEMENT_APPROVERS environment variable, a JSON object mapping a
+// payor agency ID to the MSPs that may approve its settlements, e.g.
+// {"TCA":["TCAMSP","TCA-TREASURYMSP"]} (see SetSettlementApprovers). Each
+// agency's own MSP approves for it when the variable is unset. It returns an
+// error when the value cannot be parsed or names an invalid agency.
+func SettlementApproversFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_SETTLEMENT_APPROVERS")
+	if !ok {
+		return nil
+	}
+	var approvers map[string][]string
+	if err := json.Unmarshal([]byte(val), &approvers); err != nil {
+		return fmt.Errorf("failed to parse NIOP_SETTLEMENT_APPROVERS: %w", err)
+	}
+	if err := SetSettlementApprovers(approvers); err != nil {
+		return fmt.Errorf("invalid NIOP_SETTLEMENT_APPROVERS: %w", err)
+	}
+	return nil
+}
+
+// AllowUnknownPlateCountriesFromEnv sets models.AllowUnknownPlateCountries
+// from the NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES environment variable; "false"
+// rejects plates from countries with no entry in models.PlateJurisdictions.
+// It leaves the default (allowed) in place when the variable is unset, and
+// returns an error when the value is not a boolean.
+func AllowUnknownPlateCountriesFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES")
+	if !ok {
+		return nil
+	}
+	allow, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("invalid NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES %q: must be true or false", val)
+	}
+	models.AllowUnknownPlateCountries = allow
+	return nil
+}
+
+// MaxBatchSizeFromEnv sets MaxBatchSize from the NIOP_MAX_BATCH_SIZE
+// environment variable. It leaves the default in place when the variable is
+// unset, and returns an error when the value is not a positive integer.
+func MaxBatchSizeFromEnv() error {
+	val, ok := os.LookupEnv("NIOP_MAX_BATCH_SIZE")
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid NIOP_MAX_BATCH_SIZE %q: must be a positive integer", val)
+	}
+	MaxBatchSize = n
+	return nil
+}
+
+// envBool reads a boolean environment variable, returning def when the
+// variable is unset or not a valid boolean.
+func envBool(key string, def bool) bool {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// envInt reads a non-negative integer environment variable, returning def
+// when the variable is unset or not a valid non-negative integer.
+func envInt(key string, def int) int {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,276 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"testing"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withConfig applies modify to a copy of the active configuration for the
+// duration of a test and restores the original afterwards.
+func withConfig(t *testing.T, modify func(*Config)) {
+	t.Helper()
+	original := CurrentConfig()
+	cfg := original
+	modify(&cfg)
+	SetConfig(cfg)
+	t.Cleanup(func() { SetConfig(original) })
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults when unset", func(t *testing.T) {
+		assert.Equal(t, DefaultConfig(), ConfigFromEnv())
+	})
+
+	t.Run("reads auto-dispute flag", func(t *testing.T) {
+		t.Setenv("NIOP_AUTO_DISPUTE_ON_MISMATCH", "true")
+		assert.True(t, ConfigFromEnv().AutoDisputeOnAmountMismatch)
+	})
+
+	t.Run("ignores unparseable values", func(t *testing.T) {
+		t.Setenv("NIOP_AUTO_DISPUTE_ON_MISMATCH", "sometimes")
+		assert.False(t, ConfigFromEnv().AutoDisputeOnAmountMismatch)
+	})
+
+	t.Run("reads pay-by-plate plate flag", func(t *testing.T) {
+		t.Setenv("NIOP_REQUIRE_PAYBYPLATE_PLATES", "true")
+		assert.True(t, ConfigFromEnv().RequirePayByPlatePlates)
+	})
+
+	t.Run("reads report-all-validation-errors flag", func(t *testing.T) {
+		t.Setenv("NIOP_REPORT_ALL_VALIDATION_ERRORS", "true")
+		assert.True(t, ConfigFromEnv().ReportAllValidationErrors)
+	})
+
+	t.Run("reads require-collections flag", func(t *testing.T) {
+		t.Setenv("NIOP_REQUIRE_COLLECTIONS", "true")
+		assert.True(t, ConfigFromEnv().RequireCollections)
+	})
+
+	t.Run("reads charge retention days", func(t *testing.T) {
+		t.Setenv("NIOP_CHARGE_RETENTION_DAYS", "730")
+		assert.Equal(t, 730, ConfigFromEnv().ChargeRetentionDays)
+	})
+
+	t.Run("ignores negative retention days", func(t *testing.T) {
+		t.Setenv("NIOP_CHARGE_RETENTION_DAYS", "-1")
+		assert.Equal(t, 0, ConfigFromEnv().ChargeRetentionDays)
+	})
+}
+
+func TestWithConfig_Restores(t *testing.T) {
+	original := CurrentConfig()
+	t.Run("override", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.AutoDisputeOnAmountMismatch = !original.AutoDisputeOnAmountMismatch })
+		assert.NotEqual(t, original, CurrentConfig())
+	})
+	assert.Equal(t, original, CurrentConfig())
+}
+
+func TestVehicleClassesFromEnv(t *testing.T) {
+	original := models.VehicleClasses
+	t.Cleanup(func() { models.VehicleClasses = original })
+
+	t.Run("keeps built-in set when unset", func(t *testing.T) {
+		require.NoError(t, VehicleClassesFromEnv())
+		assert.Equal(t, original, models.VehicleClasses)
+	})
+
+	t.Run("replaces set from JSON", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASSES", `{"1":{"description":"Car","axles":2},"2":{"description":"Truck","axles":3}}`)
+		require.NoError(t, VehicleClassesFromEnv())
+		vc, ok := models.LookupVehicleClass(2)
+		require.True(t, ok)
+		assert.Equal(t, "Truck", vc.Description)
+		_, ok = models.LookupVehicleClass(3)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASSES", "not json")
+		err := VehicleClassesFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse NIOP_VEHICLE_CLASSES")
+	})
+
+	t.Run("rejects invalid set", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASSES", `{"1":{"description":"Car","axles":0}}`)
+		err := VehicleClassesFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_VEHICLE_CLASSES")
+	})
+}
+
+func TestVehicleClassRemapsFromEnv(t *testing.T) {
+	original := models.VehicleClassRemaps
+	t.Cleanup(func() { models.VehicleClassRemaps = original })
+
+	t.Run("keeps remaps when unset", func(t *testing.T) {
+		require.NoError(t, VehicleClassRemapsFromEnv())
+		assert.Equal(t, original, models.VehicleClassRemaps)
+	})
+
+	t.Run("replaces remaps from JSON", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASS_REMAPS", `{"2":[3,4]}`)
+		require.NoError(t, VehicleClassRemapsFromEnv())
+		assert.Equal(t, map[int][]int{2: {3, 4}}, models.VehicleClassRemaps)
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASS_REMAPS", "not json")
+		err := VehicleClassRemapsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse NIOP_VEHICLE_CLASS_REMAPS")
+	})
+
+	t.Run("rejects unknown class", func(t *testing.T) {
+		t.Setenv("NIOP_VEHICLE_CLASS_REMAPS", `{"2":[99]}`)
+		err := VehicleClassRemapsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_VEHICLE_CLASS_REMAPS")
+	})
+}
+
+func TestProtocolTagProtocolsFromEnv(t *testing.T) {
+	original := models.ProtocolTagProtocols
+	t.Cleanup(func() { models.ProtocolTagProtocols = original })
+
+	t.Run("keeps mapping when unset", func(t *testing.T) {
+		require.NoError(t, ProtocolTagProtocolsFromEnv())
+		assert.Equal(t, original, models.ProtocolTagProtocols)
+	})
+
+	t.Run("replaces mapping from JSON", func(t *testing.T) {
+		t.Setenv("NIOP_TAG_PROTOCOLS", `{"iag":["tdm","6c"]}`)
+		require.NoError(t, ProtocolTagProtocolsFromEnv())
+		assert.Equal(t, map[string][]string{"iag": {"tdm", "6c"}}, models.ProtocolTagProtocols)
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		t.Setenv("NIOP_TAG_PROTOCOLS", "not json")
+		err := ProtocolTagProtocolsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse NIOP_TAG_PROTOCOLS")
+	})
+
+	t.Run("rejects unknown tag protocol", func(t *testing.T) {
+		t.Setenv("NIOP_TAG_PROTOCOLS", `{"iag":["rfid"]}`)
+		err := ProtocolTagProtocolsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_TAG_PROTOCOLS")
+	})
+}
+
+func TestMaxBatchSizeFromEnv(t *testing.T) {
+	t.Cleanup(func() { MaxBatchSize = DefaultMaxBatchSize })
+
+	t.Run("keeps default when unset", func(t *testing.T) {
+		require.NoError(t, MaxBatchSizeFromEnv())
+		assert.Equal(t, DefaultMaxBatchSize, MaxBatchSize)
+	})
+
+	t.Run("sets size", func(t *testing.T) {
+		t.Setenv("NIOP_MAX_BATCH_SIZE", "100")
+		require.NoError(t, MaxBatchSizeFromEnv())
+		assert.Equal(t, 100, MaxBatchSize)
+	})
+
+	for _, val := range []string{"0", "-5", "lots"} {
+		t.Run("rejects "+val, func(t *testing.T) {
+			MaxBatchSize = DefaultMaxBatchSize
+			t.Setenv("NIOP_MAX_BATCH_SIZE", val)
+			err := MaxBatchSizeFromEnv()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid NIOP_MAX_BATCH_SIZE")
+			assert.Equal(t, DefaultMaxBatchSize, MaxBatchSize)
+		})
+	}
+}
+
+func TestAllowUnknownPlateCountriesFromEnv(t *testing.T) {
+	t.Cleanup(func() { models.AllowUnknownPlateCountries = true })
+
+	t.Run("keeps default when unset", func(t *testing.T) {
+		require.NoError(t, AllowUnknownPlateCountriesFromEnv())
+		assert.True(t, models.AllowUnknownPlateCountries)
+	})
+
+	t.Run("rejects unknown countries when false", func(t *testing.T) {
+		t.Setenv("NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES", "false")
+		require.NoError(t, AllowUnknownPlateCountriesFromEnv())
+		assert.False(t, models.AllowUnknownPlateCountries)
+		assert.Error(t, models.ValidatePlateJurisdiction("DE", "BY"))
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		models.AllowUnknownPlateCountries = true
+		t.Setenv("NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES", "sometimes")
+		err := AllowUnknownPlateCountriesFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES")
+		assert.True(t, models.AllowUnknownPlateCountries)
+	})
+}
+
+func TestDualEndorsementPairsFromEnv(t *testing.T) {
+	t.Cleanup(func() { dualEndorsementCollections = map[string]bool{} })
+
+	t.Run("flags no pairs when unset", func(t *testing.T) {
+		require.NoError(t, DualEndorsementPairsFromEnv())
+		assert.Empty(t, dualEndorsementCollections)
+	})
+
+	t.Run("flags pairs from JSON", func(t *testing.T) {
+		t.Setenv("NIOP_DUAL_ENDORSEMENT_PAIRS", `[["TCA","BATA"],["ORG2","ORG1"]]`)
+		require.NoError(t, DualEndorsementPairsFromEnv())
+		assert.Equal(t, map[string]bool{"charges_BATA_TCA": true, "charges_ORG1_ORG2": true}, dualEndorsementCollections)
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		t.Setenv("NIOP_DUAL_ENDORSEMENT_PAIRS", "TCA:BATA")
+		err := DualEndorsementPairsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse NIOP_DUAL_ENDORSEMENT_PAIRS")
+	})
+
+	t.Run("rejects invalid pair", func(t *testing.T) {
+		t.Setenv("NIOP_DUAL_ENDORSEMENT_PAIRS", `[["TCA"]]`)
+		err := DualEndorsementPairsFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_DUAL_ENDORSEMENT_PAIRS")
+	})
+}
+
+func TestSettlementApproversFromEnv(t *testing.T) {
+	t.Cleanup(func() { settlementApprovers = map[string][]string{} })
+
+	t.Run("leaves each agency's own MSP when unset", func(t *testing.T) {
+		require.NoError(t, SettlementApproversFromEnv())
+		assert.Equal(t, []string{"TCAMSP"}, settlementApproverMSPs("TCA"))
+	})
+
+	t.Run("sets approvers from JSON", func(t *testing.T) {
+		t.Setenv("NIOP_SETTLEMENT_APPROVERS", `{"TCA":["TCAMSP","TCA-TREASURYMSP"]}`)
+		require.NoError(t, SettlementApproversFromEnv())
+		assert.Equal(t, []string{"TCAMSP", "TCA-TREASURYMSP"}, settlementApproverMSPs("TCA"))
+		assert.Equal(t, []string{"BATAMSP"}, settlementApproverMSPs("BATA"))
+	})
+
+	t.Run("rejects unparseable value", func(t *testing.T) {
+		t.Setenv("NIOP_SETTLEMENT_APPROVERS", "TCA:TCAMSP")
+		err := SettlementApproversFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse NIOP_SETTLEMENT_APPROVERS")
+	})
+
+	t.Run("rejects an agency without approvers", func(t *testing.T) {
+		t.Setenv("NIOP_SETTLEMENT_APPROVERS", `{"TCA":[]}`)
+		err := SettlementApproversFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NIOP_SETTLEMENT_APPROVERS")
+	})
+}
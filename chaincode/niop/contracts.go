@@ -0,0 +1,51 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Contracts returns a new instance of every contract the chaincode serves,
+// in registration order.
+func Contracts() []contractapi.ContractInterface {
+	return []contractapi.ContractInterface{
+		&AgencyContract{},
+		&TagContract{},
+		&ChargeContract{},
+		&CorrectionContract{},
+		&ReconciliationContract{},
+		&AcknowledgementContract{},
+		&SettlementContract{},
+		&DisputeContract{},
+		&FeeScheduleContract{},
+		&MigrationContract{},
+		&PingContract{},
+	}
+}
+
+// ContractNames returns the names the chaincode registers its contracts
+// under, which are the contract type names.
+func ContractNames() []string {
+	contracts := Contracts()
+	names := make([]string, len(contracts))
+	for i, contract := range contracts {
+		names[i] = reflect.TypeOf(contract).Elem().Name()
+	}
+	return names
+}
+
+// txTime returns the transaction's timestamp. Contracts stamp documents with
+// it rather than the clock, which differs between endorsing peers and would
+// give each a different write set.
+func txTime(ctx contractapi.TransactionContextInterface) (time.Time, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get transaction timestamp: %w", err)
+	}
+	return time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC(), nil
+}
@@ -5,6 +5,7 @@ package niop
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -18,43 +19,274 @@ type CorrectionContract struct {
 
 // CreateCorrection creates a new correction for an existing charge.
 // The correction is stored in the same private collection as the original charge.
+// Returns an error if the original charge has already been settled. When
+// Config.StrictMode is set and the charge is on the ledger, the correction's
+// record type must be the charge's with the 'A' suffix, and a correction whose
+// agencies differ from the charge's is rejected rather than filed in another
+// collection.
 func (c *CorrectionContract) CreateCorrection(ctx contractapi.TransactionContextInterface, correctionJSON string) error {
 	var correction models.Correction
 	if err := json.Unmarshal([]byte(correctionJSON), &correction); err != nil {
 		return fmt.Errorf("failed to parse correction JSON: %w", err)
 	}
 
-	if err := correction.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+	return c.createCorrection(ctx, &correction)
+}
+
+// createCorrection validates and stores a new correction. It holds the
+// checks shared by CreateCorrection and ReverseCharge.
+func (c *CorrectionContract) createCorrection(ctx contractapi.TransactionContextInterface, correction *models.Correction) error {
+	if _, err := c.checkCorrection(ctx, correction); err != nil {
+		return err
+	}
+
+	correction.SetCreatedAt()
+
+	bytes, err := json.Marshal(correction)
+	if err != nil {
+		return fmt.Errorf("failed to marshal correction: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(correction.CollectionName(), correction.Key(), bytes)
+}
+
+// checkCorrection applies the checks a new correction must pass and derives
+// its ResubmitCount, without writing anything. It returns the original
+// charge, or nil if the charge has not reached the collection yet.
+func (c *CorrectionContract) checkCorrection(ctx contractapi.TransactionContextInterface, correction *models.Correction) (*models.Charge, error) {
+	if err := validateModel(correction); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	collection := correction.CollectionName()
-	existing, err := ctx.GetStub().GetPrivateData(collection, correction.Key())
+	existing, err := ctx.GetStub().GetPrivateData(correction.CollectionName(), correction.Key())
 	if err != nil {
-		return fmt.Errorf("failed to read private data: %w", err)
+		return nil, fmt.Errorf("failed to read private data: %w", err)
 	}
 	if existing != nil {
-		return fmt.Errorf("correction %s already exists", correction.Key())
+		return nil, fmt.Errorf("correction %s already exists", correction.Key())
 	}
 
-	correction.SetCreatedAt()
+	// Corrections may be filed before the original charge reaches this
+	// collection, so a missing charge is allowed; a settled one is not.
+	charge, err := findCharge(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load original charge: %w", err)
+	}
+	if charge != nil && charge.Status == "settled" {
+		return nil, fmt.Errorf("cannot correct a settled charge")
+	}
+	// Amounts are signed, but together they may not take the charge below
+	// zero.
+	if charge != nil {
+		existing, err := c.GetCorrectionsForCharge(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+		if err != nil {
+			return nil, err
+		}
+		adjusted := models.AdjustedAmount(charge.Amount, existing) + correction.Amount
+		if math.Round(adjusted*100) < 0 {
+			return nil, fmt.Errorf("correction amount %.2f would take charge %s to %.2f: the adjusted amount must not be negative",
+				correction.Amount, charge.ChargeID, adjusted)
+		}
+	}
+	if CurrentConfig().StrictMode && charge != nil {
+		if err := correction.ValidateRecordTypeFor(charge); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	if CurrentConfig().StrictMode && charge == nil {
+		collection, err := findChargeCollection(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+		if err != nil {
+			return nil, err
+		}
+		if collection != "" {
+			return nil, fmt.Errorf("charge %s is in collection %s, not %s: a correction must be filed between the charge's agencies",
+				correction.OriginalChargeID, collection, correction.CollectionName())
+		}
+	}
 
-	bytes, err := json.Marshal(correction)
+	if CurrentConfig().RejectCorrectionSequenceGaps && correction.CorrectionSeqNo > models.FirstCorrectionSeqNo {
+		seqNos, err := c.correctionSeqNos(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+		if err != nil {
+			return nil, err
+		}
+		missing := models.MissingSeqNos(append(seqNos, correction.CorrectionSeqNo))
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("correctionSeqNo %d would leave a gap: missing %v", correction.CorrectionSeqNo, missing)
+		}
+	}
+
+	// ResubmitCount is derived from the ledger so that resubmission counts
+	// stay consistent however the submitting agency numbers them.
+	if correction.ResubmitReason != "" {
+		prior, err := c.GetCorrectionsForCharge(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+		if err != nil {
+			return nil, err
+		}
+		correction.ResubmitCount = models.NextResubmitCount(prior)
+	}
+
+	return charge, nil
+}
+
+// findChargeCollection looks for a charge in the other bilateral collections
+// of agencyA and agencyB and returns the name of the one holding it, or "" if
+// there is none. It checks private data hashes, which are on the public
+// ledger, so collections this peer does not belong to are searched too.
+// Pairs with no collection defined cannot hold the charge and are skipped;
+// any other error reading a hash is returned.
+func findChargeCollection(ctx contractapi.TransactionContextInterface, chargeID string, agencyA string, agencyB string) (string, error) {
+	agencies, err := (&AgencyContract{}).GetAllAgencies(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal correction: %w", err)
+		return "", err
+	}
+
+	own := models.BilateralCollectionName(agencyA, agencyB)
+	key := models.ChargeKey(chargeID)
+	for _, agency := range agencies {
+		for _, party := range []string{agencyA, agencyB} {
+			if agency.AgencyID == party {
+				continue
+			}
+			collection := models.BilateralCollectionName(party, agency.AgencyID)
+			if collection == own {
+				continue
+			}
+			hash, err := ctx.GetStub().GetPrivateDataHash(collection, key)
+			if err != nil {
+				if isUndefinedCollection(err) {
+					continue
+				}
+				return "", fmt.Errorf("failed to check collection %s for charge %s: %w", collection, chargeID, err)
+			}
+			if hash != nil {
+				return collection, nil
+			}
+		}
 	}
+	return "", nil
+}
 
-	return ctx.GetStub().PutPrivateData(collection, correction.Key(), bytes)
+// CorrectionImpact is the result of PreviewCorrectionImpact. Amounts are
+// rounded to the cent; NetAmount figures are the amount less the charge's fee,
+// as settlements total them.
+type CorrectionImpact struct {
+	ChargeID           string   `json:"chargeID"`
+	CorrectionSeqNo    int      `json:"correctionSeqNo"`
+	CurrentAmount      float64  `json:"currentAmount"`
+	ProjectedAmount    float64  `json:"projectedAmount"`
+	Fee                float64  `json:"fee"`
+	CurrentNetAmount   float64  `json:"currentNetAmount"`
+	ProjectedNetAmount float64  `json:"projectedNetAmount"`
+	Warnings           []string `json:"warnings"`
+}
+
+// PreviewCorrectionImpact runs the checks CreateCorrection would apply to a
+// correction and returns the charge amount it would produce, without writing
+// anything; submit it as an evaluate. CurrentAmount is the charge amount with
+// its existing corrections applied and ProjectedAmount the amount with this
+// one added. Warnings flag results that CreateCorrection accepts but that are
+// probably mistakes: an amount below the fee, or a sequence gap when gaps are
+// not rejected. Unlike CreateCorrection, the original charge
+// must already be on the ledger.
+func (c *CorrectionContract) PreviewCorrectionImpact(ctx contractapi.TransactionContextInterface, correctionJSON string) (*CorrectionImpact, error) {
+	var correction models.Correction
+	if err := json.Unmarshal([]byte(correctionJSON), &correction); err != nil {
+		return nil, fmt.Errorf("failed to parse correction JSON: %w", err)
+	}
+
+	charge, err := c.checkCorrection(ctx, &correction)
+	if err != nil {
+		return nil, err
+	}
+	if charge == nil {
+		return nil, fmt.Errorf("charge %s not found in collection %s", correction.OriginalChargeID, correction.CollectionName())
+	}
+
+	existing, err := c.GetCorrectionsForCharge(ctx, correction.OriginalChargeID, correction.FromAgencyID, correction.ToAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	current := models.AdjustedAmount(charge.Amount, existing)
+	projected := models.AdjustedAmount(charge.Amount, append(existing, &correction))
+
+	impact := &CorrectionImpact{
+		ChargeID:           charge.ChargeID,
+		CorrectionSeqNo:    correction.CorrectionSeqNo,
+		CurrentAmount:      math.Round(current*100) / 100,
+		ProjectedAmount:    math.Round(projected*100) / 100,
+		Fee:                charge.Fee,
+		CurrentNetAmount:   math.Round((current-charge.Fee)*100) / 100,
+		ProjectedNetAmount: math.Round((projected-charge.Fee)*100) / 100,
+		Warnings:           []string{},
+	}
+	if impact.ProjectedNetAmount < 0 {
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf("projected amount %.2f is less than the fee %.2f", impact.ProjectedAmount, charge.Fee))
+	}
+	if correction.CorrectionSeqNo > models.FirstCorrectionSeqNo {
+		seqNos := []int{correction.CorrectionSeqNo}
+		for _, e := range existing {
+			seqNos = append(seqNos, e.CorrectionSeqNo)
+		}
+		if missing := models.MissingSeqNos(seqNos); len(missing) > 0 {
+			impact.Warnings = append(impact.Warnings, fmt.Sprintf("correctionSeqNo %d leaves a gap: missing %v", correction.CorrectionSeqNo, missing))
+		}
+	}
+
+	return impact, nil
+}
+
+// ReverseCharge cancels a charge by filing a reversing correction: one whose
+// amount is minus the charge's remaining amount after any earlier
+// corrections, so that the adjusted amount becomes zero. The correction takes
+// the charge's record type with the 'A' suffix and the next unused sequence
+// number, and is filed from the away agency to the home agency.
+// correctionReason is one of models.ValidCorrectionReasons. Returns the
+// correction created, or an error if the charge is already fully reversed or
+// its corrections have taken it below zero.
+func (c *CorrectionContract) ReverseCharge(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string, correctionReason string) (*models.Correction, error) {
+	charge, err := (&ChargeContract{}).GetCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := c.GetCorrectionsForCharge(ctx, chargeID, awayAgencyID, homeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := models.AdjustedAmount(charge.Amount, existing)
+	if centsEqual(remaining, 0) {
+		return nil, fmt.Errorf("charge %s is already fully reversed", chargeID)
+	}
+	if remaining < 0 {
+		return nil, fmt.Errorf("charge %s has a negative adjusted amount %.2f and cannot be reversed", chargeID, remaining)
+	}
+
+	recordType, err := models.CorrectionRecordTypeFor(charge.RecordType)
+	if err != nil {
+		return nil, err
+	}
+
+	seqNo := models.NextCorrectionSeqNo(existing)
+	correction := &models.Correction{
+		CorrectionID:     fmt.Sprintf("%s-REV-%03d", chargeID, seqNo),
+		OriginalChargeID: chargeID,
+		CorrectionSeqNo:  seqNo,
+		CorrectionReason: correctionReason,
+		FromAgencyID:     charge.AwayAgencyID,
+		ToAgencyID:       charge.HomeAgencyID,
+		RecordType:       recordType,
+		Amount:           -remaining,
+	}
+
+	if err := c.createCorrection(ctx, correction); err != nil {
+		return nil, err
+	}
+	return correction, nil
 }
 
 // GetCorrection retrieves a correction by charge ID and sequence number.
 func (c *CorrectionContract) GetCorrection(ctx contractapi.TransactionContextInterface, originalChargeID string, seqNo int, fromAgencyID string, toAgencyID string) (*models.Correction, error) {
-	// Determine collection name using alphabetical sort
-	a, b := fromAgencyID, toAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	collection := "charges_" + a + "_" + b
+	collection := models.BilateralCollectionName(fromAgencyID, toAgencyID)
 	key := fmt.Sprintf("CORRECTION_%s_%03d", originalChargeID, seqNo)
 
 	bytes, err := ctx.GetStub().GetPrivateData(collection, key)
@@ -66,7 +298,7 @@ func (c *CorrectionContract) GetCorrection(ctx contractapi.TransactionContextInt
 	}
 
 	var correction models.Correction
-	if err := json.Unmarshal(bytes, &correction); err != nil {
+	if err := decodeDocument("correction", bytes, &correction); err != nil {
 		return nil, fmt.Errorf("failed to parse correction: %w", err)
 	}
 
@@ -75,12 +307,7 @@ func (c *CorrectionContract) GetCorrection(ctx contractapi.TransactionContextInt
 
 // GetCorrectionsForCharge returns all corrections for a specific charge.
 func (c *CorrectionContract) GetCorrectionsForCharge(ctx contractapi.TransactionContextInterface, originalChargeID string, fromAgencyID string, toAgencyID string) ([]*models.Correction, error) {
-	// Determine collection name using alphabetical sort
-	a, b := fromAgencyID, toAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	collection := "charges_" + a + "_" + b
+	collection := models.BilateralCollectionName(fromAgencyID, toAgencyID)
 
 	startKey := fmt.Sprintf("CORRECTION_%s_", originalChargeID)
 	endKey := fmt.Sprintf("CORRECTION_%s_~", originalChargeID)
@@ -99,7 +326,7 @@ func (c *CorrectionContract) GetCorrectionsForCharge(ctx contractapi.Transaction
 		}
 
 		var correction models.Correction
-		if err := json.Unmarshal(queryResponse.Value, &correction); err != nil {
+		if err := decodeDocument("correction", queryResponse.Value, &correction); err != nil {
 			return nil, fmt.Errorf("failed to parse correction: %w", err)
 		}
 		corrections = append(corrections, &correction)
@@ -107,3 +334,83 @@ func (c *CorrectionContract) GetCorrectionsForCharge(ctx contractapi.Transaction
 
 	return corrections, nil
 }
+
+// GetLatestCorrectionForCharge returns the correction with the highest
+// sequence number for a charge, the most recent adjustment to it. Returns an
+// error if the charge has no corrections.
+func (c *CorrectionContract) GetLatestCorrectionForCharge(ctx contractapi.TransactionContextInterface, originalChargeID string, fromAgencyID string, toAgencyID string) (*models.Correction, error) {
+	corrections, err := c.GetCorrectionsForCharge(ctx, originalChargeID, fromAgencyID, toAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *models.Correction
+	for _, correction := range corrections {
+		if latest == nil || correction.CorrectionSeqNo > latest.CorrectionSeqNo {
+			latest = correction
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no corrections found for charge %s", originalChargeID)
+	}
+
+	return latest, nil
+}
+
+// GetCorrectionsByAgencyPair returns every correction exchanged between two
+// agencies, across all charges. It queries the bilateral collection by
+// docType, so corrections written before docType existed are only found
+// once MigrateCollection has upgraded them.
+func (c *CorrectionContract) GetCorrectionsByAgencyPair(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string) ([]*models.Correction, error) {
+	query, err := newRichQuery("correction", nil).String()
+	if err != nil {
+		return nil, err
+	}
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataQueryResult(models.BilateralCollectionName(agencyA, agencyB), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	corrections := []*models.Correction{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var correction models.Correction
+		if err := decodeDocument("correction", queryResponse.Value, &correction); err != nil {
+			return nil, fmt.Errorf("failed to parse correction: %w", err)
+		}
+		corrections = append(corrections, &correction)
+	}
+
+	return corrections, nil
+}
+
+// ValidateSequenceContiguity returns the correction sequence numbers missing
+// for a charge, from models.FirstCorrectionSeqNo up to the highest number in
+// use. An empty list means the corrections form a contiguous audit trail.
+func (c *CorrectionContract) ValidateSequenceContiguity(ctx contractapi.TransactionContextInterface, originalChargeID string, fromAgencyID string, toAgencyID string) ([]int, error) {
+	seqNos, err := c.correctionSeqNos(ctx, originalChargeID, fromAgencyID, toAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	return models.MissingSeqNos(seqNos), nil
+}
+
+// correctionSeqNos returns the sequence numbers of all stored corrections for a charge.
+func (c *CorrectionContract) correctionSeqNos(ctx contractapi.TransactionContextInterface, originalChargeID string, fromAgencyID string, toAgencyID string) ([]int, error) {
+	corrections, err := c.GetCorrectionsForCharge(ctx, originalChargeID, fromAgencyID, toAgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	seqNos := make([]int, 0, len(corrections))
+	for _, correction := range corrections {
+		seqNos = append(seqNos, correction.CorrectionSeqNo)
+	}
+	return seqNos, nil
+}
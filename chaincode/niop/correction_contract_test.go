@@ -4,6 +4,7 @@ package niop
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -95,6 +96,276 @@ func TestCreateCorrection(t *testing.T) {
 	})
 }
 
+func TestCreateCorrection_ChargeStatus(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	createWithCharge := func(t *testing.T, status string) error {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), status)
+
+		corrJSON, _ := json.Marshal(validCorrection())
+		return contract.CreateCorrection(ctx, string(corrJSON))
+	}
+
+	t.Run("allows correction to posted charge", func(t *testing.T) {
+		assert.NoError(t, createWithCharge(t, "posted"))
+	})
+
+	t.Run("rejects correction to settled charge", func(t *testing.T) {
+		err := createWithCharge(t, "settled")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot correct a settled charge")
+	})
+
+	t.Run("allows correction when charge is not on file", func(t *testing.T) {
+		ctx := newMockContext()
+		corrJSON, _ := json.Marshal(validCorrection())
+		assert.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+	})
+}
+
+func TestCreateCorrection_NegativeAdjustedAmount(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	tests := []struct {
+		name    string
+		earlier []float64
+		amount  float64
+		wantErr string
+	}{
+		{"allows reducing the charge to zero", nil, -4.75, ""},
+		{"rejects taking the charge below zero", nil, -100, "would take charge CHG-TEST-001 to -95.25"},
+		{"counts earlier corrections", []float64{-4.00}, -1.00, "would take charge CHG-TEST-001 to -0.25"},
+		{"allows a decrease an earlier increase covers", []float64{2.00}, -6.00, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newMockContext()
+			createChargeWithStatus(t, ctx, validCharge(), "posted")
+			for i, amount := range tt.earlier {
+				corr := validCorrection()
+				corr.CorrectionSeqNo = i + 1
+				corr.Amount = amount
+				corrJSON, _ := json.Marshal(corr)
+				require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+			}
+
+			corr := validCorrection()
+			corr.CorrectionSeqNo = len(tt.earlier) + 1
+			corr.Amount = tt.amount
+			corrJSON, _ := json.Marshal(corr)
+			err := contract.CreateCorrection(ctx, string(corrJSON))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCreateCorrection_RecordTypeFamily(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	createWithRecordType := func(t *testing.T, ctx *enhancedMockContext, recordType string) error {
+		correction := validCorrection()
+		correction.RecordType = recordType
+		corrJSON, _ := json.Marshal(correction)
+		return contract.CreateCorrection(ctx, string(corrJSON))
+	}
+
+	t.Run("accepts matching record type", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		assert.NoError(t, createWithRecordType(t, ctx, "TB01A"))
+	})
+
+	t.Run("rejects another record type family", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		err := createWithRecordType(t, ctx, "VC02A")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correction recordType does not match charge")
+	})
+
+	t.Run("skips the check when the charge is not on file", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+
+		assert.NoError(t, createWithRecordType(t, ctx, "VC02A"))
+	})
+
+	t.Run("skips the check outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		assert.NoError(t, createWithRecordType(t, ctx, "VC02A"))
+	})
+}
+
+func TestCreateCorrection_ChargeCollection(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	createBetween := func(ctx *enhancedMockContext, from, to string) error {
+		correction := validCorrection()
+		correction.FromAgencyID = from
+		correction.ToAgencyID = to
+		corrJSON, _ := json.Marshal(correction)
+		return contract.CreateCorrection(ctx, string(corrJSON))
+	}
+
+	t.Run("accepts a correction between the charge's agencies", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		assert.NoError(t, createBetween(ctx, "ORG2", "ORG1"))
+	})
+
+	t.Run("rejects a correction filed for another agency pair", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		err := createBetween(ctx, "ORG3", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "charge CHG-TEST-001 is in collection charges_ORG1_ORG2, not charges_ORG1_ORG3")
+		assert.Empty(t, ctx.stub.privateData["charges_ORG1_ORG3"])
+	})
+
+	t.Run("allows a charge that is not on the ledger", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		correction := validCorrection()
+		correction.OriginalChargeID = "CHG-NOT-YET"
+		correction.FromAgencyID = "ORG3"
+		corrJSON, _ := json.Marshal(correction)
+		assert.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+	})
+
+	t.Run("skips the check outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+
+		assert.NoError(t, createBetween(ctx, "ORG3", "ORG1"))
+	})
+
+	t.Run("skips pairs without a collection", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx.stub.collectionErrors = map[string]error{
+			"charges_ORG1_ORG2": undefinedCollectionError("charges_ORG1_ORG2"),
+		}
+
+		assert.NoError(t, createBetween(ctx, "ORG3", "ORG1"))
+	})
+
+	t.Run("returns other collection errors", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		createAgencies(t, ctx, "ORG1", "ORG2", "ORG3")
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx.stub.collectionErrors = map[string]error{
+			"charges_ORG1_ORG2": fmt.Errorf("peer unavailable"),
+		}
+
+		err := createBetween(ctx, "ORG3", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to check collection charges_ORG1_ORG2 for charge CHG-TEST-001: peer unavailable")
+		assert.Empty(t, ctx.stub.privateData["charges_ORG1_ORG3"])
+	})
+}
+
+func TestPreviewCorrectionImpact(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	t.Run("matches the amount after a real apply", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		corrJSON, _ := json.Marshal(validCorrection())
+
+		impact, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.NoError(t, err)
+		assert.Equal(t, 4.75, impact.CurrentAmount)
+		assert.Equal(t, 8.25, impact.ProjectedAmount)
+		assert.Equal(t, 8.20, impact.ProjectedNetAmount)
+		assert.Empty(t, impact.Warnings)
+
+		_, err = contract.GetCorrection(ctx, "CHG-TEST-001", 1, "ORG2", "ORG1")
+		require.Error(t, err, "preview must not write the correction")
+
+		require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+		corrections, err := contract.GetCorrectionsForCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, impact.ProjectedAmount, models.AdjustedAmount(4.75, corrections))
+	})
+
+	t.Run("warns about a sequence gap", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		correction := validCorrection()
+		correction.CorrectionSeqNo = 3
+		corrJSON, _ := json.Marshal(correction)
+
+		impact, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"correctionSeqNo 3 leaves a gap: missing [1 2]"}, impact.Warnings)
+	})
+
+	t.Run("rejects a negative projected amount", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		correction := validCorrection()
+		correction.Amount = -6.00
+		corrJSON, _ := json.Marshal(correction)
+
+		_, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not be negative")
+	})
+
+	t.Run("warns when the amount falls below the fee", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		correction := validCorrection()
+		correction.Amount = -4.72
+		corrJSON, _ := json.Marshal(correction)
+
+		impact, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"projected amount 0.03 is less than the fee 0.05"}, impact.Warnings)
+	})
+
+	t.Run("applies the same checks as CreateCorrection", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "settled")
+		corrJSON, _ := json.Marshal(validCorrection())
+
+		_, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot correct a settled charge")
+	})
+
+	t.Run("requires the original charge", func(t *testing.T) {
+		ctx := newMockContext()
+		corrJSON, _ := json.Marshal(validCorrection())
+
+		_, err := contract.PreviewCorrectionImpact(ctx, string(corrJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "charge CHG-TEST-001 not found")
+	})
+}
+
 func TestGetCorrection(t *testing.T) {
 	contract := &CorrectionContract{}
 
@@ -171,3 +442,320 @@ func TestGetCorrectionsForCharge(t *testing.T) {
 		assert.Equal(t, "CHG-TEST-001", result[0].OriginalChargeID)
 	})
 }
+
+func TestGetLatestCorrectionForCharge(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	create := func(ctx *enhancedMockContext, seqNo int, amount float64) {
+		corr := validCorrection()
+		corr.CorrectionID = fmt.Sprintf("CORR-TEST-%03d", seqNo)
+		corr.CorrectionSeqNo = seqNo
+		corr.Amount = amount
+		corrJSON, _ := json.Marshal(corr)
+		require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+	}
+
+	t.Run("no corrections", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetLatestCorrectionForCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no corrections found")
+	})
+
+	t.Run("one correction", func(t *testing.T) {
+		ctx := newMockContext()
+		create(ctx, 1, 3.50)
+
+		latest, err := contract.GetLatestCorrectionForCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, latest.CorrectionSeqNo)
+	})
+
+	t.Run("several corrections", func(t *testing.T) {
+		ctx := newMockContext()
+		create(ctx, 2, 4.00)
+		create(ctx, 10, 2.25)
+		create(ctx, 1, 3.50)
+
+		latest, err := contract.GetLatestCorrectionForCharge(ctx, "CHG-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 10, latest.CorrectionSeqNo)
+		assert.InDelta(t, 2.25, latest.Amount, 0.0001)
+	})
+}
+
+func TestGetCorrectionsByAgencyPair(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	t.Run("returns empty slice when none", func(t *testing.T) {
+		ctx := newMockContext()
+		result, err := contract.GetCorrectionsByAgencyPair(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("returns corrections for every charge in the pair", func(t *testing.T) {
+		ctx := newMockContext()
+		for i, chargeID := range []string{"CHG-001", "CHG-001", "CHG-002"} {
+			corr := validCorrection()
+			corr.CorrectionID = fmt.Sprintf("CORR-%03d", i)
+			corr.OriginalChargeID = chargeID
+			corr.CorrectionSeqNo = i + 1
+			corrJSON, _ := json.Marshal(corr)
+			require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+		}
+
+		// A charge in the same collection is not a correction.
+		chargeJSON, _ := json.Marshal(validCharge())
+		require.NoError(t, (&ChargeContract{}).CreateCharge(ctx, string(chargeJSON)))
+
+		// Corrections with another partner are in a different collection.
+		other := validCorrection()
+		other.ToAgencyID = "ORG3"
+		otherJSON, _ := json.Marshal(other)
+		require.NoError(t, contract.CreateCorrection(ctx, string(otherJSON)))
+
+		result, err := contract.GetCorrectionsByAgencyPair(ctx, "ORG2", "ORG1")
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		chargeIDs := []string{result[0].OriginalChargeID, result[1].OriginalChargeID, result[2].OriginalChargeID}
+		assert.ElementsMatch(t, []string{"CHG-001", "CHG-001", "CHG-002"}, chargeIDs)
+	})
+}
+
+func TestValidateSequenceContiguity(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	createSeq := func(t *testing.T, ctx *enhancedMockContext, seqNos ...int) {
+		for _, n := range seqNos {
+			corr := validCorrection()
+			corr.CorrectionID = fmt.Sprintf("CORR-TEST-%03d", n)
+			corr.CorrectionSeqNo = n
+			corrJSON, _ := json.Marshal(corr)
+			require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+		}
+	}
+
+	t.Run("contiguous set has no gaps", func(t *testing.T) {
+		ctx := newMockContext()
+		createSeq(t, ctx, 1, 2, 3)
+
+		missing, err := contract.ValidateSequenceContiguity(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("reports missing sequence numbers", func(t *testing.T) {
+		ctx := newMockContext()
+		createSeq(t, ctx, 1, 5)
+
+		missing, err := contract.ValidateSequenceContiguity(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 4}, missing)
+	})
+
+	t.Run("no corrections has no gaps", func(t *testing.T) {
+		ctx := newMockContext()
+
+		missing, err := contract.ValidateSequenceContiguity(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+}
+
+func TestCreateCorrection_RejectSequenceGaps(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	create := func(ctx *enhancedMockContext, seqNo int) error {
+		corr := validCorrection()
+		corr.CorrectionID = fmt.Sprintf("CORR-TEST-%03d", seqNo)
+		corr.CorrectionSeqNo = seqNo
+		corrJSON, _ := json.Marshal(corr)
+		return contract.CreateCorrection(ctx, string(corrJSON))
+	}
+
+	t.Run("gaps are allowed by default", func(t *testing.T) {
+		ctx := newMockContext()
+		require.NoError(t, create(ctx, 1))
+		assert.NoError(t, create(ctx, 5))
+	})
+
+	t.Run("rejects a skipped number when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RejectCorrectionSequenceGaps = true })
+		ctx := newMockContext()
+		require.NoError(t, create(ctx, 1))
+
+		err := create(ctx, 3)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "would leave a gap")
+	})
+
+	t.Run("rejects a first correction past one when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RejectCorrectionSequenceGaps = true })
+		ctx := newMockContext()
+
+		err := create(ctx, 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing [1]")
+	})
+
+	t.Run("accepts the next number when enabled", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.RejectCorrectionSequenceGaps = true })
+		ctx := newMockContext()
+		require.NoError(t, create(ctx, 1))
+		assert.NoError(t, create(ctx, 2))
+	})
+}
+
+func TestCreateCorrection_ResubmitCount(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	create := func(ctx *enhancedMockContext, seqNo int, resubmitReason string, resubmitCount int) *models.Correction {
+		corr := validCorrection()
+		corr.CorrectionID = fmt.Sprintf("CORR-TEST-%03d", seqNo)
+		corr.CorrectionSeqNo = seqNo
+		corr.ResubmitReason = resubmitReason
+		corr.ResubmitCount = resubmitCount
+		corrJSON, _ := json.Marshal(corr)
+		require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+
+		stored, err := contract.GetCorrection(ctx, "CHG-TEST-001", seqNo, "ORG2", "ORG1")
+		require.NoError(t, err)
+		return stored
+	}
+
+	t.Run("first submission has no resubmit count", func(t *testing.T) {
+		ctx := newMockContext()
+		stored := create(ctx, 1, "", 0)
+		assert.Equal(t, 0, stored.ResubmitCount)
+	})
+
+	t.Run("resubmissions are counted from prior corrections", func(t *testing.T) {
+		ctx := newMockContext()
+		create(ctx, 1, "", 0)
+
+		first := create(ctx, 2, "R", 7)
+		assert.Equal(t, 1, first.ResubmitCount, "submitted count should be ignored")
+
+		second := create(ctx, 3, "S", 1)
+		assert.Equal(t, 2, second.ResubmitCount)
+	})
+
+	t.Run("rejects count without reason", func(t *testing.T) {
+		ctx := newMockContext()
+		corr := validCorrection()
+		corr.ResubmitCount = 1
+		corrJSON, _ := json.Marshal(corr)
+
+		err := contract.CreateCorrection(ctx, string(corrJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resubmitReason is required")
+	})
+}
+
+func TestReverseCharge(t *testing.T) {
+	contract := &CorrectionContract{}
+
+	t.Run("reverses the full charge amount", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		reversal, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.NoError(t, err)
+		assert.Equal(t, "TB01A", reversal.RecordType)
+		assert.InDelta(t, -4.75, reversal.Amount, 0.0001)
+		assert.Equal(t, models.FirstCorrectionSeqNo, reversal.CorrectionSeqNo)
+		assert.Equal(t, "ORG2", reversal.FromAgencyID)
+		assert.Equal(t, "ORG1", reversal.ToAgencyID)
+
+		stored, err := contract.GetCorrection(ctx, "CHG-TEST-001", 1, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, reversal.CorrectionID, stored.CorrectionID)
+		assert.NotEmpty(t, stored.CreatedAt)
+	})
+
+	t.Run("reverses an IAG charge", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.Protocol = "iag"
+		charge.RecordType = "ICTX"
+		createChargeWithStatus(t, ctx, charge, "posted")
+		// Strict mode checks the reversal's record type against the charge's.
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+
+		reversal, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.NoError(t, err)
+		assert.Equal(t, "ICTXA", reversal.RecordType)
+		assert.InDelta(t, -4.75, reversal.Amount, 0.0001)
+	})
+
+	t.Run("reverses what earlier corrections left and takes the next sequence number", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		corr := validCorrection()
+		corr.Amount = -1.25
+		corrJSON, _ := json.Marshal(corr)
+		require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+
+		reversal, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.NoError(t, err)
+		assert.Equal(t, 2, reversal.CorrectionSeqNo)
+		assert.InDelta(t, -3.50, reversal.Amount, 0.0001)
+	})
+
+	t.Run("rejects a charge that is already fully reversed", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		_, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.NoError(t, err)
+
+		_, err = contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already fully reversed")
+	})
+
+	t.Run("rejects a charge corrected below zero", func(t *testing.T) {
+		ctx := newMockContext()
+		// Corrections filed before the charge reaches the collection are
+		// not checked against its amount.
+		corr := validCorrection()
+		corr.Amount = -10.00
+		corrJSON, _ := json.Marshal(corr)
+		require.NoError(t, contract.CreateCorrection(ctx, string(corrJSON)))
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		_, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "negative adjusted amount -5.25")
+
+		corrections, err := contract.GetCorrectionsForCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Len(t, corrections, 1, "no reversal may be written")
+	})
+
+	t.Run("rejects a settled charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "settled")
+
+		_, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "I")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot correct a settled charge")
+	})
+
+	t.Run("rejects invalid reason", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		_, err := contract.ReverseCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1", "X")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid correctionReason")
+	})
+
+	t.Run("returns error for nonexistent charge", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.ReverseCharge(ctx, "NONEXISTENT", "ORG2", "ORG1", "I")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// DisputeContract handles Dispute transactions on the ledger.
+// Disputes are stored in the same bilateral private data collections as the
+// charges and settlements they challenge.
+type DisputeContract struct {
+	contractapi.Contract
+}
+
+// CreateDispute opens a dispute and moves the disputed charge or settlement to
+// "disputed" in the same transaction. An entity that is already disputed is
+// left as it is; one that cannot move to disputed fails the whole call.
+func (c *DisputeContract) CreateDispute(ctx contractapi.TransactionContextInterface, disputeJSON string) error {
+	var dispute models.Dispute
+	if err := json.Unmarshal([]byte(disputeJSON), &dispute); err != nil {
+		return fmt.Errorf("failed to parse dispute JSON: %w", err)
+	}
+
+	dispute.Status = "open"
+	dispute.Resolution = ""
+	dispute.ResolvedAt = ""
+
+	if err := validateModel(&dispute); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	collection := dispute.CollectionName()
+	existing, err := ctx.GetStub().GetPrivateData(collection, dispute.Key())
+	if err != nil {
+		return fmt.Errorf("failed to read private data: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("dispute %s already exists", dispute.DisputeID)
+	}
+
+	if err := markEntityDisputed(ctx, &dispute); err != nil {
+		return err
+	}
+
+	dispute.SetCreatedAt()
+
+	bytes, err := json.Marshal(dispute)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dispute: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(collection, dispute.Key(), bytes)
+}
+
+// markEntityDisputed moves the charge or settlement a dispute refers to into
+// "disputed" status, using the owning contract's status update so its
+// transition rules apply.
+func markEntityDisputed(ctx contractapi.TransactionContextInterface, dispute *models.Dispute) error {
+	a, b := dispute.RaisedByAgencyID, dispute.CounterpartyAgencyID
+
+	switch dispute.EntityType {
+	case "charge":
+		charges := &ChargeContract{}
+		charge, err := charges.GetCharge(ctx, dispute.EntityID, a, b)
+		if err != nil {
+			return err
+		}
+		if charge.Status == "disputed" {
+			return nil
+		}
+		return charges.UpdateChargeStatus(ctx, dispute.EntityID, a, b, "disputed")
+	case "settlement":
+		settlements := &SettlementContract{}
+		settlement, err := settlements.GetSettlement(ctx, dispute.EntityID, a, b)
+		if err != nil {
+			return err
+		}
+		if settlement.Status == "disputed" {
+			return nil
+		}
+		return settlements.UpdateSettlementStatus(ctx, dispute.EntityID, a, b, "disputed")
+	}
+	return fmt.Errorf("invalid entityType %q: must be one of %v", dispute.EntityType, models.ValidDisputeEntityTypes)
+}
+
+// GetDispute retrieves a dispute by ID.
+// Requires knowing both agency IDs to determine the collection name.
+func (c *DisputeContract) GetDispute(ctx contractapi.TransactionContextInterface, disputeID string, agencyA string, agencyB string) (*models.Dispute, error) {
+	collection := models.BilateralCollectionName(agencyA, agencyB)
+	key := "DISPUTE_" + disputeID
+
+	bytes, err := ctx.GetStub().GetPrivateData(collection, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private data: %w", err)
+	}
+	if bytes == nil {
+		return nil, fmt.Errorf("dispute %s not found in collection %s", disputeID, collection)
+	}
+
+	var dispute models.Dispute
+	if err := decodeDocument("dispute", bytes, &dispute); err != nil {
+		return nil, fmt.Errorf("failed to parse dispute: %w", err)
+	}
+
+	return &dispute, nil
+}
+
+// GetDisputesForEntity returns all disputes raised against a charge or
+// settlement, open and resolved.
+func (c *DisputeContract) GetDisputesForEntity(ctx contractapi.TransactionContextInterface, entityType string, entityID string, agencyA string, agencyB string) ([]*models.Dispute, error) {
+	if !models.Contains(models.ValidDisputeEntityTypes, entityType) {
+		return nil, fmt.Errorf("invalid entityType %q: must be one of %v", entityType, models.ValidDisputeEntityTypes)
+	}
+
+	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(models.BilateralCollectionName(agencyA, agencyB), "DISPUTE_", "DISPUTE_~")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private data by range: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	var disputes []*models.Dispute
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var dispute models.Dispute
+		if err := decodeDocument("dispute", queryResponse.Value, &dispute); err != nil {
+			return nil, fmt.Errorf("failed to parse dispute: %w", err)
+		}
+		if dispute.EntityType == entityType && dispute.EntityID == entityID {
+			disputes = append(disputes, &dispute)
+		}
+	}
+
+	return disputes, nil
+}
+
+// ResolveDispute closes an open dispute with a resolution. The disputed
+// entity's status is not changed; the agencies update it separately once
+// they have agreed the outcome.
+func (c *DisputeContract) ResolveDispute(ctx contractapi.TransactionContextInterface, disputeID string, agencyA string, agencyB string, resolution string) error {
+	dispute, err := c.GetDispute(ctx, disputeID, agencyA, agencyB)
+	if err != nil {
+		return err
+	}
+
+	if err := dispute.Resolve(resolution); err != nil {
+		return fmt.Errorf("cannot resolve dispute: %w", err)
+	}
+
+	bytes, err := json.Marshal(dispute)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dispute: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(dispute.CollectionName(), dispute.Key(), bytes)
+}
@@ -0,0 +1,181 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validDispute() *models.Dispute {
+	return &models.Dispute{
+		DisputeID:            "DSP-TEST-001",
+		EntityType:           "charge",
+		EntityID:             "CHG-TEST-001",
+		RaisedByAgencyID:     "ORG1",
+		CounterpartyAgencyID: "ORG2",
+		Reason:               "Vehicle class does not match tag record",
+		EvidenceRefs:         []string{"IMG-2026-0001"},
+	}
+}
+
+func TestCreateDispute(t *testing.T) {
+	contract := &DisputeContract{}
+
+	t.Run("opens dispute and disputes the charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+
+		disputeJSON, _ := json.Marshal(validDispute())
+		require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+
+		dispute, err := contract.GetDispute(ctx, "DSP-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "open", dispute.Status)
+		assert.Equal(t, "dispute", dispute.DocType)
+		assert.NotEmpty(t, dispute.CreatedAt)
+
+		charge, err := (&ChargeContract{}).GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "disputed", charge.Status)
+	})
+
+	t.Run("opens dispute and disputes the settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, validSettlement(), "submitted")
+
+		dispute := validDispute()
+		dispute.EntityType = "settlement"
+		dispute.EntityID = "SETTLE-TEST-001"
+		disputeJSON, _ := json.Marshal(dispute)
+		require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+
+		result, err := (&SettlementContract{}).GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "disputed", result.Status)
+	})
+
+	t.Run("allows a second dispute on a disputed charge", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "disputed")
+
+		disputeJSON, _ := json.Marshal(validDispute())
+		assert.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+	})
+
+	t.Run("rejects dispute on a charge that cannot be disputed", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "pending")
+
+		disputeJSON, _ := json.Marshal(validDispute())
+		err := contract.CreateDispute(ctx, string(disputeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status transition")
+
+		_, err = contract.GetDispute(ctx, "DSP-TEST-001", "ORG1", "ORG2")
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects dispute on missing charge", func(t *testing.T) {
+		ctx := newMockContext()
+		disputeJSON, _ := json.Marshal(validDispute())
+		err := contract.CreateDispute(ctx, string(disputeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("rejects duplicate dispute", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		disputeJSON, _ := json.Marshal(validDispute())
+		require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+
+		err := contract.CreateDispute(ctx, string(disputeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("rejects invalid dispute", func(t *testing.T) {
+		ctx := newMockContext()
+		dispute := validDispute()
+		dispute.Reason = ""
+		disputeJSON, _ := json.Marshal(dispute)
+		err := contract.CreateDispute(ctx, string(disputeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
+
+func TestGetDisputesForEntity(t *testing.T) {
+	contract := &DisputeContract{}
+
+	t.Run("returns disputes for the entity only", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, validCharge(), "posted")
+		other := validCharge()
+		other.ChargeID = "CHG-TEST-002"
+		createChargeWithStatus(t, ctx, other, "posted")
+
+		for _, d := range []struct{ id, charge string }{
+			{"DSP-1", "CHG-TEST-001"},
+			{"DSP-2", "CHG-TEST-001"},
+			{"DSP-3", "CHG-TEST-002"},
+		} {
+			dispute := validDispute()
+			dispute.DisputeID = d.id
+			dispute.EntityID = d.charge
+			disputeJSON, _ := json.Marshal(dispute)
+			require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+		}
+
+		result, err := contract.GetDisputesForEntity(ctx, "charge", "CHG-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("rejects invalid entity type", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetDisputesForEntity(ctx, "tag", "TAG-1", "ORG1", "ORG2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid entityType")
+	})
+}
+
+func TestResolveDispute(t *testing.T) {
+	contract := &DisputeContract{}
+
+	disputeJSON, _ := json.Marshal(validDispute())
+
+	t.Run("resolves open dispute", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+		require.NoError(t, contract.ResolveDispute(ctx, "DSP-TEST-001", "ORG1", "ORG2", "Correction issued"))
+
+		dispute, err := contract.GetDispute(ctx, "DSP-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "resolved", dispute.Status)
+		assert.Equal(t, "Correction issued", dispute.Resolution)
+		assert.NotEmpty(t, dispute.ResolvedAt)
+	})
+
+	t.Run("rejects resolving a resolved dispute", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		require.NoError(t, contract.CreateDispute(ctx, string(disputeJSON)))
+		require.NoError(t, contract.ResolveDispute(ctx, "DSP-TEST-001", "ORG1", "ORG2", "Correction issued"))
+
+		err := contract.ResolveDispute(ctx, "DSP-TEST-001", "ORG1", "ORG2", "again")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not open")
+	})
+
+	t.Run("returns error for nonexistent dispute", func(t *testing.T) {
+		ctx := newMockContext()
+		err := contract.ResolveDispute(ctx, "NONEXISTENT", "ORG1", "ORG2", "done")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
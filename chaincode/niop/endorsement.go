@@ -0,0 +1,107 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// dualEndorsementCollections holds the bilateral collections whose charges
+// both agencies must endorse. Set it with SetDualEndorsementPairs during
+// chaincode startup, before any transaction is served.
+var dualEndorsementCollections = map[string]bool{}
+
+// SetDualEndorsementPairs flags agency pairs whose charges need endorsing by
+// both agencies' organizations, not only the submitter's. Creation is
+// enforced by the collection-level endorsement policy GenerateCollectionsConfig
+// writes for a flagged pair, so the same pairs must be flagged when the
+// collections config is generated. Later changes to a charge are also held by
+// key-level policies (see setChargeEndorsementPolicy). Each pair must be two
+// different valid agency IDs, in either order. It replaces any pairs set
+// before.
+func SetDualEndorsementPairs(pairs [][]string) error {
+	collections := make(map[string]bool, len(pairs))
+	for i, pair := range pairs {
+		if len(pair) != 2 {
+			return fmt.Errorf("pair %d must name 2 agencies, got %d", i, len(pair))
+		}
+		for _, id := range pair {
+			if err := models.ValidateAgencyID("agencyID", id); err != nil {
+				return fmt.Errorf("pair %d: %w", i, err)
+			}
+		}
+		if pair[0] == pair[1] {
+			return fmt.Errorf("pair %d must name 2 different agencies, got %s twice", i, pair[0])
+		}
+		collections[models.BilateralCollectionName(pair[0], pair[1])] = true
+	}
+	dualEndorsementCollections = collections
+	return nil
+}
+
+// requiresDualEndorsement reports whether a charge's agency pair is flagged
+// by SetDualEndorsementPairs.
+func requiresDualEndorsement(charge *models.Charge) bool {
+	return dualEndorsementCollections[charge.CollectionName()]
+}
+
+// agencyMSPID returns the MSP ID of an agency's organization: the agency ID
+// with an "MSP" suffix, as in the policies GenerateCollectionsConfig writes.
+func agencyMSPID(agencyID string) string {
+	return agencyID + "MSP"
+}
+
+// setChargeEndorsementPolicy sets a key-level endorsement policy on key, in
+// the charge's collection, requiring a member of both the away and the home
+// agency's organizations to endorse any later change to it. It is set on the
+// charge itself, its chargeByStatus index entries and the collection's charge
+// counter. The policy governs only transactions after the one that sets it,
+// so it keeps a charge under dual endorsement whatever the collection's
+// policy, but it cannot make creation need both agencies: that is the
+// collection-level policy's job. Because every create writes the counter,
+// once the first charge has set its policy every later create needs both
+// agencies too, even in a collection deployed without the dual policy.
+func setChargeEndorsementPolicy(ctx contractapi.TransactionContextInterface, charge *models.Charge, key string) error {
+	ep, err := statebased.NewStateEP(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create endorsement policy: %w", err)
+	}
+	if err := ep.AddOrgs(statebased.RoleTypeMember, agencyMSPID(charge.AwayAgencyID), agencyMSPID(charge.HomeAgencyID)); err != nil {
+		return fmt.Errorf("failed to create endorsement policy: %w", err)
+	}
+	policy, err := ep.Policy()
+	if err != nil {
+		return fmt.Errorf("failed to marshal endorsement policy: %w", err)
+	}
+	if err := ctx.GetStub().SetPrivateDataValidationParameter(charge.CollectionName(), key, policy); err != nil {
+		return fmt.Errorf("failed to set endorsement policy: %w", err)
+	}
+	return nil
+}
+
+// GetChargeEndorsementOrgs returns the MSP IDs that must all endorse changes
+// to a charge, in sorted order. It is empty when the charge has no key-level
+// policy and the collection's endorsement policy applies.
+func (c *ChargeContract) GetChargeEndorsementOrgs(ctx contractapi.TransactionContextInterface, chargeID string, awayAgencyID string, homeAgencyID string) ([]string, error) {
+	collection := models.BilateralCollectionName(awayAgencyID, homeAgencyID)
+	policy, err := ctx.GetStub().GetPrivateDataValidationParameter(collection, models.ChargeKey(chargeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read endorsement policy: %w", err)
+	}
+	if policy == nil {
+		return []string{}, nil
+	}
+
+	ep, err := statebased.NewStateEP(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endorsement policy: %w", err)
+	}
+	orgs := ep.ListOrgs()
+	sort.Strings(orgs)
+	return orgs, nil
+}
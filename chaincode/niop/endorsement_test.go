@@ -0,0 +1,122 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withDualEndorsementPairs flags pairs for the duration of a test.
+func withDualEndorsementPairs(t *testing.T, pairs ...[]string) {
+	t.Helper()
+	original := dualEndorsementCollections
+	require.NoError(t, SetDualEndorsementPairs(pairs))
+	t.Cleanup(func() { dualEndorsementCollections = original })
+}
+
+func TestSetDualEndorsementPairs(t *testing.T) {
+	t.Cleanup(func() { dualEndorsementCollections = map[string]bool{} })
+
+	require.NoError(t, SetDualEndorsementPairs([][]string{{"ORG2", "ORG1"}}))
+	assert.Equal(t, map[string]bool{"charges_ORG1_ORG2": true}, dualEndorsementCollections)
+
+	tests := []struct {
+		pairs   [][]string
+		wantErr string
+	}{
+		{[][]string{{"ORG1"}}, "pair 0 must name 2 agencies, got 1"},
+		{[][]string{{"ORG1", "ORG2", "ORG3"}}, "pair 0 must name 2 agencies, got 3"},
+		{[][]string{{"ORG1", "ORG2"}, {"ORG1", "ORG1"}}, "pair 1 must name 2 different agencies"},
+		{[][]string{{"ORG1", "ORG_2"}}, "pair 0: agencyID contains invalid characters"},
+	}
+	for _, tt := range tests {
+		err := SetDualEndorsementPairs(tt.pairs)
+		require.Error(t, err, tt.wantErr)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+	assert.Equal(t, map[string]bool{"charges_ORG1_ORG2": true}, dualEndorsementCollections, "a rejected set leaves the pairs unchanged")
+}
+
+func TestCreateCharge_DualEndorsement(t *testing.T) {
+	contract := &ChargeContract{}
+
+	create := func(t *testing.T, ctx *enhancedMockContext) {
+		chargeJSON, _ := json.Marshal(validCharge())
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+	}
+
+	t.Run("requires both agencies for a flagged pair", func(t *testing.T) {
+		withDualEndorsementPairs(t, []string{"ORG1", "ORG2"})
+		ctx := newMockContext()
+		create(t, ctx)
+
+		policies := ctx.stub.validationParameters["charges_ORG1_ORG2"]
+		policy := policies["CHARGE_CHG-TEST-001"]
+		assert.NotEmpty(t, policy)
+		orgs, err := contract.GetChargeEndorsementOrgs(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ORG1MSP", "ORG2MSP"}, orgs)
+
+		indexKey, err := ctx.stub.CreateCompositeKey(chargeByStatusIndex, []string{"pending", "CHG-TEST-001"})
+		require.NoError(t, err)
+		assert.Equal(t, policy, policies[indexKey], "index entry")
+		assert.Equal(t, policy, policies[chargeCountKey], "charge counter")
+	})
+
+	t.Run("keeps the policy when the charge changes", func(t *testing.T) {
+		withDualEndorsementPairs(t, []string{"ORG1", "ORG2"})
+		ctx := newMockContext()
+		create(t, ctx)
+		policy := ctx.stub.validationParameters["charges_ORG1_ORG2"]["CHARGE_CHG-TEST-001"]
+
+		require.NoError(t, contract.UpdateChargeStatus(ctx, "CHG-TEST-001", "ORG2", "ORG1", "posted"))
+		assert.Equal(t, policy, ctx.stub.validationParameters["charges_ORG1_ORG2"]["CHARGE_CHG-TEST-001"])
+
+		indexKey, err := ctx.stub.CreateCompositeKey(chargeByStatusIndex, []string{"posted", "CHG-TEST-001"})
+		require.NoError(t, err)
+		assert.Equal(t, policy, ctx.stub.validationParameters["charges_ORG1_ORG2"][indexKey], "new index entry")
+	})
+
+	t.Run("every later create writes the guarded counter", func(t *testing.T) {
+		withDualEndorsementPairs(t, []string{"ORG1", "ORG2"})
+		ctx := newMockContext()
+		create(t, ctx)
+		policy := ctx.stub.validationParameters["charges_ORG1_ORG2"][chargeCountKey]
+		require.NotEmpty(t, policy)
+
+		second := validCharge()
+		second.ChargeID = "CHG-TEST-002"
+		chargeJSON, _ := json.Marshal(second)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		// The second create writes the counter, whose key-level policy
+		// requires both agencies, so it cannot be endorsed by one alone.
+		count, err := contract.GetChargeCount(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, policy, ctx.stub.validationParameters["charges_ORG1_ORG2"][chargeCountKey])
+		ep, err := statebased.NewStateEP(policy)
+		require.NoError(t, err)
+		orgs := ep.ListOrgs()
+		sort.Strings(orgs)
+		assert.Equal(t, []string{"ORG1MSP", "ORG2MSP"}, orgs)
+	})
+
+	t.Run("leaves other pairs to the collection policy", func(t *testing.T) {
+		withDualEndorsementPairs(t, []string{"ORG1", "ORG3"})
+		ctx := newMockContext()
+		create(t, ctx)
+
+		assert.Empty(t, ctx.stub.validationParameters)
+		orgs, err := contract.GetChargeEndorsementOrgs(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.NotNil(t, orgs)
+		assert.Empty(t, orgs)
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// FeeScheduleContract handles FeeSchedule transactions on the ledger.
+// Fee schedules are stored in the bilateral private data collection of the
+// two agencies they apply to, one per charge type.
+type FeeScheduleContract struct {
+	contractapi.Contract
+}
+
+// CreateFeeSchedule records the fee two agencies have agreed for a charge type.
+// Returns an error if a schedule for that pair and charge type already exists.
+func (c *FeeScheduleContract) CreateFeeSchedule(ctx contractapi.TransactionContextInterface, feeScheduleJSON string) error {
+	var schedule models.FeeSchedule
+	if err := json.Unmarshal([]byte(feeScheduleJSON), &schedule); err != nil {
+		return fmt.Errorf("failed to parse fee schedule JSON: %w", err)
+	}
+
+	if err := validateModel(&schedule); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	collection := schedule.CollectionName()
+	existing, err := ctx.GetStub().GetPrivateData(collection, schedule.Key())
+	if err != nil {
+		return fmt.Errorf("failed to read private data: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("fee schedule for %s already exists in collection %s", schedule.ChargeType, collection)
+	}
+
+	schedule.SetCreatedAt()
+
+	bytes, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fee schedule: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(collection, schedule.Key(), bytes)
+}
+
+// GetFeeSchedule retrieves the fee schedule for an agency pair and charge type.
+func (c *FeeScheduleContract) GetFeeSchedule(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, chargeType string) (*models.FeeSchedule, error) {
+	schedule, err := findFeeSchedule(ctx, agencyA, agencyB, chargeType)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("fee schedule for %s not found in collection %s", chargeType, models.BilateralCollectionName(agencyA, agencyB))
+	}
+	return schedule, nil
+}
+
+// findFeeSchedule reads a fee schedule, returning nil without an error if the
+// agencies have not agreed one for the charge type.
+func findFeeSchedule(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, chargeType string) (*models.FeeSchedule, error) {
+	key := models.FeeSchedule{AgencyA: agencyA, AgencyB: agencyB, ChargeType: chargeType}
+	bytes, err := ctx.GetStub().GetPrivateData(key.CollectionName(), key.Key())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private data: %w", err)
+	}
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var schedule models.FeeSchedule
+	if err := decodeDocument("feeschedule", bytes, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse fee schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// applyFeeSchedule sets a charge's fee and net amount from the agencies' fee
+// schedule for its charge type. A charge submitted with a fee or net amount
+// must agree with the schedule to the cent. A charge without a fee breakdown
+// gets the schedule's. Charges with no schedule keep the submitted values.
+func applyFeeSchedule(ctx contractapi.TransactionContextInterface, charge *models.Charge) error {
+	schedule, err := findFeeSchedule(ctx, charge.AwayAgencyID, charge.HomeAgencyID, charge.ChargeType)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return nil
+	}
+
+	fee, netAmount := models.ComputeFee(charge, schedule)
+	submitted := charge.Fee != 0 || charge.NetAmount != 0
+	if submitted && (!centsEqual(charge.Fee, fee) || !centsEqual(charge.NetAmount, netAmount)) {
+		return fmt.Errorf("fee does not match fee schedule: submitted fee %.2f and netAmount %.2f, expected fee %.2f and netAmount %.2f",
+			charge.Fee, charge.NetAmount, fee, netAmount)
+	}
+
+	charge.Fee = fee
+	charge.NetAmount = netAmount
+	if charge.FeeBreakdown == nil {
+		charge.FeeBreakdown = &models.FeeBreakdown{FlatFee: schedule.FlatFee, PercentFee: schedule.PercentFee}
+	}
+	return nil
+}
+
+// centsEqual reports whether two amounts are equal when rounded to the cent.
+func centsEqual(a float64, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
@@ -0,0 +1,168 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validFeeSchedule() *models.FeeSchedule {
+	return &models.FeeSchedule{
+		AgencyA:    "ORG1",
+		AgencyB:    "ORG2",
+		ChargeType: "toll_tag",
+		FlatFee:    0.05,
+	}
+}
+
+// createFeeSchedule stores schedule through the contract.
+func createFeeSchedule(t *testing.T, ctx *enhancedMockContext, schedule *models.FeeSchedule) {
+	t.Helper()
+	scheduleJSON, _ := json.Marshal(schedule)
+	require.NoError(t, (&FeeScheduleContract{}).CreateFeeSchedule(ctx, string(scheduleJSON)))
+}
+
+func TestCreateFeeSchedule(t *testing.T) {
+	contract := &FeeScheduleContract{}
+
+	t.Run("creates and reads back", func(t *testing.T) {
+		ctx := newMockContext()
+		createFeeSchedule(t, ctx, validFeeSchedule())
+
+		schedule, err := contract.GetFeeSchedule(ctx, "ORG2", "ORG1", "toll_tag")
+		require.NoError(t, err)
+		assert.Equal(t, "feeschedule", schedule.DocType)
+		assert.InDelta(t, 0.05, schedule.FlatFee, 0.0001)
+	})
+
+	t.Run("rejects duplicate", func(t *testing.T) {
+		ctx := newMockContext()
+		createFeeSchedule(t, ctx, validFeeSchedule())
+
+		scheduleJSON, _ := json.Marshal(validFeeSchedule())
+		err := contract.CreateFeeSchedule(ctx, string(scheduleJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("rejects invalid schedule", func(t *testing.T) {
+		ctx := newMockContext()
+		schedule := validFeeSchedule()
+		schedule.PercentFee = 1.5
+
+		scheduleJSON, _ := json.Marshal(schedule)
+		err := contract.CreateFeeSchedule(ctx, string(scheduleJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("get missing schedule", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetFeeSchedule(ctx, "ORG1", "ORG2", "toll_tag")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestCreateCharge_ComputesFeeFromSchedule(t *testing.T) {
+	contract := &ChargeContract{}
+
+	t.Run("fills fee from percentage schedule", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ComputeChargeFees = true })
+		ctx := newMockContext()
+		schedule := validFeeSchedule()
+		schedule.FlatFee = 0
+		schedule.PercentFee = 0.02
+		createFeeSchedule(t, ctx, schedule)
+
+		charge := validCharge()
+		charge.Fee = 0
+		charge.NetAmount = 0
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.10, stored.Fee, 0.0001)
+		assert.InDelta(t, 4.65, stored.NetAmount, 0.0001)
+	})
+
+	t.Run("accepts matching flat fee", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ComputeChargeFees = true })
+		ctx := newMockContext()
+		createFeeSchedule(t, ctx, validFeeSchedule())
+
+		chargeJSON, _ := json.Marshal(validCharge())
+		assert.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		require.NotNil(t, stored.FeeBreakdown, "the schedule's breakdown is recorded")
+		assert.InDelta(t, 0.05, stored.FeeBreakdown.FlatFee, 0.0001)
+	})
+
+	t.Run("rejects fee that disagrees with schedule", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ComputeChargeFees = true })
+		ctx := newMockContext()
+		schedule := validFeeSchedule()
+		schedule.FlatFee = 0.25
+		createFeeSchedule(t, ctx, schedule)
+
+		chargeJSON, _ := json.Marshal(validCharge())
+		err := contract.CreateCharge(ctx, string(chargeJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match fee schedule")
+	})
+
+	t.Run("keeps submitted fee without a schedule", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ComputeChargeFees = true })
+		ctx := newMockContext()
+
+		chargeJSON, _ := json.Marshal(validCharge())
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.05, stored.Fee, 0.0001)
+	})
+
+	t.Run("prices pay-by-plate from its own schedule", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.ComputeChargeFees = true })
+		ctx := newMockContext()
+		createFeeSchedule(t, ctx, validFeeSchedule())
+		payByPlate := validFeeSchedule()
+		payByPlate.ChargeType = "toll_paybyplate"
+		payByPlate.FlatFee = 0.50
+		createFeeSchedule(t, ctx, payByPlate)
+
+		// Tag record type, but billed by plate: the pay-by-plate fee applies.
+		charge := validCharge()
+		charge.ChargeType = "toll_paybyplate"
+		charge.PlateState = "CA"
+		charge.PlateNumber = "7ABC123"
+		charge.Fee = 0
+		charge.NetAmount = 0
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+
+		stored, err := contract.GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.50, stored.Fee, 0.0001)
+		assert.InDelta(t, 4.25, stored.NetAmount, 0.0001)
+	})
+
+	t.Run("ignores schedule when disabled", func(t *testing.T) {
+		ctx := newMockContext()
+		schedule := validFeeSchedule()
+		schedule.FlatFee = 0.25
+		createFeeSchedule(t, ctx, schedule)
+
+		chargeJSON, _ := json.Marshal(validCharge())
+		assert.NoError(t, contract.CreateCharge(ctx, string(chargeJSON)))
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// MigrationContract rewrites stored documents to the current schema version.
+// Reads already upgrade documents on the fly; migration makes the upgrade
+// permanent so that rich queries see current-version fields.
+type MigrationContract struct {
+	contractapi.Contract
+}
+
+// MigrateCollection rewrites every entity document in a collection to
+// models.CurrentSchemaVersion and returns the number of documents changed.
+// Pass an empty collection name to migrate world state. Documents already at
+// the current version are not rewritten. Secondary index entries for tags and
+// charges are rewritten for migrated documents and backfilled where missing.
+func (c *MigrationContract) MigrateCollection(ctx contractapi.TransactionContextInterface, collection string) (int, error) {
+	var resultsIterator shim.StateQueryIteratorInterface
+	var err error
+	if collection == "" {
+		resultsIterator, err = ctx.GetStub().GetStateByRange("", "")
+	} else {
+		resultsIterator, err = ctx.GetStub().GetPrivateDataByRange(collection, "", "")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get documents by range: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	migrated := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return 0, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		docType := models.DocTypeForKey(queryResponse.Key)
+		if docType == "" {
+			continue
+		}
+
+		upgraded, changed, err := models.MigrateDocument(docType, queryResponse.Value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to migrate %s: %w", queryResponse.Key, err)
+		}
+
+		// Documents already at the current version are left alone unless
+		// they were written before their secondary index existed and have
+		// no entry yet. Upgraded documents get their entries rewritten.
+		if docType == "tag" && collection == "" {
+			var tag models.Tag
+			if err := json.Unmarshal(upgraded, &tag); err != nil {
+				return 0, fmt.Errorf("failed to parse %s: %w", queryResponse.Key, err)
+			}
+			if write, err := needsIndexEntry(ctx, changed, "", tagByAgencyIndex, tag.TagAgencyID, tag.TagSerialNumber); err != nil {
+				return 0, err
+			} else if write {
+				if err := putTagAgencyIndex(ctx, &tag); err != nil {
+					return 0, err
+				}
+			}
+			if write, err := needsIndexEntry(ctx, changed, "", tagByAccountIndex, tag.AccountID, tag.TagSerialNumber); err != nil {
+				return 0, err
+			} else if write {
+				if err := putTagAccountIndex(ctx, &tag); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if docType == "charge" && collection != "" {
+			var charge models.Charge
+			if err := json.Unmarshal(upgraded, &charge); err != nil {
+				return 0, fmt.Errorf("failed to parse %s: %w", queryResponse.Key, err)
+			}
+			if write, err := needsIndexEntry(ctx, changed, collection, chargeByStatusIndex, charge.Status, charge.ChargeID); err != nil {
+				return 0, err
+			} else if write {
+				if err := putChargeStatusIndex(ctx, collection, &charge); err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		if collection == "" {
+			err = ctx.GetStub().PutState(queryResponse.Key, upgraded)
+		} else {
+			err = ctx.GetStub().PutPrivateData(collection, queryResponse.Key, upgraded)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to write migrated %s: %w", queryResponse.Key, err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// needsIndexEntry reports whether MigrateCollection should write the
+// objectType composite key entry for a document: always when the document
+// was migrated, otherwise only when the entry is missing. An empty collection
+// means world state.
+func needsIndexEntry(ctx contractapi.TransactionContextInterface, changed bool, collection string, objectType string, attributes ...string) (bool, error) {
+	if changed {
+		return true, nil
+	}
+	indexKey, err := ctx.GetStub().CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return false, fmt.Errorf("failed to create index key: %w", err)
+	}
+	var entry []byte
+	if collection == "" {
+		entry, err = ctx.GetStub().GetState(indexKey)
+	} else {
+		entry, err = ctx.GetStub().GetPrivateData(collection, indexKey)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read index entry: %w", err)
+	}
+	return entry == nil, nil
+}
+
+// decodeDocument upgrades stored JSON to the current schema version and
+// unmarshals it into v.
+func decodeDocument(docType string, data []byte, v interface{}) error {
+	upgraded, _, err := models.MigrateDocument(docType, data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(upgraded, v)
+}
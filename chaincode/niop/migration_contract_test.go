@@ -0,0 +1,101 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v1ChargeJSON = `{"chargeID":"CHG-V1-001","chargeType":"toll_tag","recordType":"TB01","protocol":"niop","awayAgencyID":"ORG2","homeAgencyID":"ORG1","tagSerialNumber":"TEST.000000001","facilityID":"SR73","exitDateTime":"2026-01-15T08:30:00Z","vehicleClass":2,"amount":4.75,"fee":0.05,"status":"pending"}`
+
+func TestMigrateCollection(t *testing.T) {
+	contract := &MigrationContract{}
+
+	t.Run("migrates v1 documents in a private collection", func(t *testing.T) {
+		ctx := newMockContext()
+		require.NoError(t, ctx.stub.PutPrivateData("charges_ORG1_ORG2", "CHARGE_CHG-V1-001", []byte(v1ChargeJSON)))
+
+		// A current-version charge should be left untouched.
+		charge := validCharge()
+		chargeJSON, _ := json.Marshal(charge)
+		require.NoError(t, (&ChargeContract{}).CreateCharge(ctx, string(chargeJSON)))
+
+		migrated, err := contract.MigrateCollection(ctx, "charges_ORG1_ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 1, migrated)
+
+		bytes, err := ctx.stub.GetPrivateData("charges_ORG1_ORG2", "CHARGE_CHG-V1-001")
+		require.NoError(t, err)
+		var stored models.Charge
+		require.NoError(t, json.Unmarshal(bytes, &stored))
+		assert.Equal(t, models.CurrentSchemaVersion, stored.SchemaVersion)
+		assert.Equal(t, "charge", stored.DocType)
+		assert.InDelta(t, 4.70, stored.NetAmount, 0.0001)
+
+		pending, err := (&ChargeContract{}).GetChargesByStatus(ctx, "ORG1", "ORG2", "pending")
+		require.NoError(t, err)
+		assert.Len(t, pending, 2, "migration should backfill the status index")
+	})
+
+	t.Run("migrates v1 documents in world state", func(t *testing.T) {
+		ctx := newMockContext()
+		require.NoError(t, ctx.stub.PutState("TAG_TEST.V1", []byte(`{"tagSerialNumber":"TEST.V1","tagAgencyID":"ORG1","homeAgencyID":"ORG1","accountID":"ACCT-1","tagStatus":"valid","tagType":"single","tagClass":2,"tagProtocol":"sego"}`)))
+
+		migrated, err := contract.MigrateCollection(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 1, migrated)
+
+		tag, err := (&TagContract{}).GetTag(ctx, "TEST.V1")
+		require.NoError(t, err)
+		assert.Equal(t, models.CurrentSchemaVersion, tag.SchemaVersion)
+		assert.Equal(t, "tag", tag.DocType)
+
+		tags, err := (&TagContract{}).GetTagsByAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		assert.Len(t, tags, 1, "migration should backfill the agency index")
+	})
+
+	t.Run("second run is a no-op", func(t *testing.T) {
+		ctx := newMockContext()
+		require.NoError(t, ctx.stub.PutPrivateData("charges_ORG1_ORG2", "CHARGE_CHG-V1-001", []byte(v1ChargeJSON)))
+
+		_, err := contract.MigrateCollection(ctx, "charges_ORG1_ORG2")
+		require.NoError(t, err)
+
+		migrated, err := contract.MigrateCollection(ctx, "charges_ORG1_ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 0, migrated)
+	})
+
+	t.Run("writes nothing for indexed current documents", func(t *testing.T) {
+		ctx := newContextWithTags(t, "ACCT-1", "ACCT-2")
+		writes := func() int {
+			total := 0
+			for _, mods := range ctx.stub.history {
+				total += len(mods)
+			}
+			return total
+		}
+		before := writes()
+
+		migrated, err := contract.MigrateCollection(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, migrated)
+		assert.Equal(t, before, writes(), "no document or index entry should be rewritten")
+	})
+}
+
+func TestGetCharge_UpgradesV1OnRead(t *testing.T) {
+	ctx := newMockContext()
+	require.NoError(t, ctx.stub.PutPrivateData("charges_ORG1_ORG2", "CHARGE_CHG-V1-001", []byte(v1ChargeJSON)))
+
+	charge, err := (&ChargeContract{}).GetCharge(ctx, "CHG-V1-001", "ORG2", "ORG1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrentSchemaVersion, charge.SchemaVersion)
+	assert.InDelta(t, 4.70, charge.NetAmount, 0.0001)
+}
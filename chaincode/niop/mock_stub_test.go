@@ -3,29 +3,60 @@
 package niop
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
 )
 
+// compositeKeyNamespace is the prefix Fabric uses for composite keys.
+const compositeKeyNamespace = "\x00"
+
 // enhancedMockStub wraps shimtest.MockStub to provide GetPrivateDataByRange support.
 // The standard MockStub doesn't implement this method, which is needed for testing
 // range queries on private data collections.
 type enhancedMockStub struct {
 	*shimtest.MockStub
 	privateData map[string]map[string][]byte // collection -> key -> value
+	// privateDataHashes mirrors the hashes Fabric commits to the public
+	// ledger. Tests can edit privateData directly to simulate tampering.
+	privateDataHashes map[string]map[string][]byte // collection -> key -> SHA-256
+	stateReads        int                          // GetState calls made by contract code
+	events            []mockEvent                  // events set by contract code, in order
+	// history records every world state write per key, oldest first, for
+	// GetHistoryForKey. MockStub does not keep history.
+	history map[string][]*queryresult.KeyModification
+	// collectionErrors makes GetPrivateDataHash fail for a collection, to
+	// simulate one missing from the collection configuration.
+	collectionErrors map[string]error
+	// validationParameters holds key-level endorsement policies set on
+	// private data, by collection and key.
+	validationParameters map[string]map[string][]byte
+}
+
+// mockEvent is a chaincode event recorded by the enhanced mock stub.
+type mockEvent struct {
+	name    string
+	payload []byte
 }
 
 // newEnhancedMockStub creates a new enhanced mock stub with private data range support.
 func newEnhancedMockStub(name string) *enhancedMockStub {
 	return &enhancedMockStub{
-		MockStub:    shimtest.NewMockStub(name, nil),
-		privateData: make(map[string]map[string][]byte),
+		MockStub:          shimtest.NewMockStub(name, nil),
+		privateData:       make(map[string]map[string][]byte),
+		privateDataHashes: make(map[string]map[string][]byte),
+		history:           make(map[string][]*queryresult.KeyModification),
 	}
 }
 
@@ -36,10 +67,95 @@ func (e *enhancedMockStub) PutPrivateData(collection string, key string, value [
 		e.privateData[collection] = make(map[string][]byte)
 	}
 	e.privateData[collection][key] = value
+	if e.privateDataHashes[collection] == nil {
+		e.privateDataHashes[collection] = make(map[string][]byte)
+	}
+	hash := sha256.Sum256(value)
+	e.privateDataHashes[collection][key] = hash[:]
 	// Also call the parent to maintain compatibility
 	return e.MockStub.PutPrivateData(collection, key, value)
 }
 
+// GetState reads world state and counts the call, so tests can assert how
+// many point reads a query performs.
+func (e *enhancedMockStub) GetState(key string) ([]byte, error) {
+	e.stateReads++
+	return e.MockStub.GetState(key)
+}
+
+// PutState writes world state and records the write in the key's history
+// under the current transaction ID and timestamp. Call MockTransactionStart
+// between writes to give each its own transaction.
+func (e *enhancedMockStub) PutState(key string, value []byte) error {
+	if err := e.MockStub.PutState(key, value); err != nil {
+		return err
+	}
+	e.recordHistory(key, value, len(value) == 0)
+	return nil
+}
+
+// DelState deletes a key from world state and records the delete in its history.
+func (e *enhancedMockStub) DelState(key string) error {
+	if err := e.MockStub.DelState(key); err != nil {
+		return err
+	}
+	e.recordHistory(key, nil, true)
+	return nil
+}
+
+func (e *enhancedMockStub) recordHistory(key string, value []byte, isDelete bool) {
+	e.history[key] = append(e.history[key], &queryresult.KeyModification{
+		TxId:      e.TxID,
+		Value:     value,
+		Timestamp: e.TxTimestamp,
+		IsDelete:  isDelete,
+	})
+}
+
+// GetHistoryForKey returns the recorded writes to a world state key, newest
+// first, as Fabric does.
+func (e *enhancedMockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	recorded := e.history[key]
+	mods := make([]*queryresult.KeyModification, len(recorded))
+	for i, mod := range recorded {
+		mods[len(recorded)-1-i] = mod
+	}
+	return &mockHistoryIterator{mods: mods}, nil
+}
+
+// mockHistoryIterator implements shim.HistoryQueryIteratorInterface for test results.
+type mockHistoryIterator struct {
+	mods  []*queryresult.KeyModification
+	index int
+}
+
+// HasNext returns true if the iterator has more results.
+func (m *mockHistoryIterator) HasNext() bool {
+	return m.index < len(m.mods)
+}
+
+// Next returns the next key modification.
+func (m *mockHistoryIterator) Next() (*queryresult.KeyModification, error) {
+	if m.index >= len(m.mods) {
+		return nil, nil
+	}
+	mod := m.mods[m.index]
+	m.index++
+	return mod, nil
+}
+
+// Close closes the iterator.
+func (m *mockHistoryIterator) Close() error {
+	return nil
+}
+
+// SetEvent records a chaincode event. Fabric keeps only the last event set
+// in a transaction; tests can inspect every call through events.
+func (e *enhancedMockStub) SetEvent(name string, payload []byte) error {
+	e.events = append(e.events, mockEvent{name: name, payload: payload})
+	return nil
+}
+
 // GetPrivateData retrieves data from a private collection.
 func (e *enhancedMockStub) GetPrivateData(collection string, key string) ([]byte, error) {
 	if e.privateData[collection] == nil {
@@ -48,6 +164,75 @@ func (e *enhancedMockStub) GetPrivateData(collection string, key string) ([]byte
 	return e.privateData[collection][key], nil
 }
 
+// DelPrivateData removes a key from a private collection.
+// MockStub does not implement private data deletes.
+func (e *enhancedMockStub) DelPrivateData(collection string, key string) error {
+	delete(e.privateData[collection], key)
+	delete(e.privateDataHashes[collection], key)
+	return nil
+}
+
+// PurgePrivateData removes a key and its hash from a private collection.
+// MockStub does not implement purges.
+func (e *enhancedMockStub) PurgePrivateData(collection string, key string) error {
+	delete(e.privateData[collection], key)
+	delete(e.privateDataHashes[collection], key)
+	return nil
+}
+
+// GetPrivateDataHash returns the SHA-256 hash of a private data value as
+// committed on the public ledger. MockStub does not implement it.
+func (e *enhancedMockStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	if err := e.collectionErrors[collection]; err != nil {
+		return nil, err
+	}
+	return e.privateDataHashes[collection][key], nil
+}
+
+// SetPrivateDataValidationParameter records a key-level endorsement policy
+// for a private data key. The mock does not enforce it.
+func (e *enhancedMockStub) SetPrivateDataValidationParameter(collection string, key string, ep []byte) error {
+	if e.validationParameters == nil {
+		e.validationParameters = make(map[string]map[string][]byte)
+	}
+	if e.validationParameters[collection] == nil {
+		e.validationParameters[collection] = make(map[string][]byte)
+	}
+	e.validationParameters[collection][key] = ep
+	return nil
+}
+
+// GetPrivateDataValidationParameter returns the key-level endorsement policy
+// set for a private data key, or nil if there is none.
+func (e *enhancedMockStub) GetPrivateDataValidationParameter(collection string, key string) ([]byte, error) {
+	return e.validationParameters[collection][key], nil
+}
+
+// GetPrivateDataByPartialCompositeKey returns the composite keys in a private
+// collection that begin with objectType and the given attributes, in key order.
+func (e *enhancedMockStub) GetPrivateDataByPartialCompositeKey(collection string, objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := e.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionData := e.privateData[collection]
+	var keys []string
+	for k := range collectionData {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	values := make([][]byte, len(keys))
+	for i, k := range keys {
+		values[i] = collectionData[k]
+	}
+
+	return &mockKVIterator{keys: keys, values: values, index: 0}, nil
+}
+
 // GetPrivateDataByRange implements range queries on private data.
 // This is the key method that shimtest.MockStub doesn't implement.
 func (e *enhancedMockStub) GetPrivateDataByRange(collection string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
@@ -56,10 +241,14 @@ func (e *enhancedMockStub) GetPrivateDataByRange(collection string, startKey str
 		return &mockKVIterator{keys: nil, values: nil}, nil
 	}
 
-	// Collect matching keys
+	// Collect matching keys. An empty endKey means the range is unbounded,
+	// and composite keys are never returned by simple-key range queries.
 	var keys []string
 	for k := range collectionData {
-		if startKey <= k && k < endKey {
+		if strings.HasPrefix(k, compositeKeyNamespace) {
+			continue
+		}
+		if startKey <= k && (endKey == "" || k < endKey) {
 			keys = append(keys, k)
 		}
 	}
@@ -133,24 +322,93 @@ func (e *enhancedMockStub) GetStateByRange(startKey string, endKey string) (shim
 }
 
 // GetQueryResult implements CouchDB rich queries for testing.
-// This is a simplified implementation that parses the selector and filters results.
-// It supports basic equality selectors like {"selector":{"docType":"tag","tagAgencyID":"ORG1"}}.
+// See runMockQuery for the supported subset of Mango query syntax.
 func (e *enhancedMockStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
-	// Parse the query to extract selector conditions
-	var queryObj struct {
-		Selector map[string]interface{} `json:"selector"`
+	var keys []string
+	for element := e.MockStub.Keys.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(string))
+	}
+	return runMockQuery(query, keys, func(key string) []byte {
+		val, _ := e.MockStub.GetState(key)
+		return val
+	})
+}
+
+// GetQueryResultWithPagination implements paginated CouchDB rich queries.
+// The mock's bookmark is the key of the last result on the previous page, and
+// the returned bookmark is empty once a page comes back empty.
+func (e *enhancedMockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	results, err := e.GetQueryResult(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	all := results.(*mockKVIterator)
+
+	start := 0
+	if bookmark != "" {
+		start = len(all.keys)
+		for i, key := range all.keys {
+			if key == bookmark {
+				start = i + 1
+				break
+			}
+		}
 	}
+	end := start + int(pageSize)
+	if end > len(all.keys) {
+		end = len(all.keys)
+	}
+
+	page := &mockKVIterator{keys: all.keys[start:end], values: all.values[start:end]}
+	metadata := &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(page.keys))}
+	if len(page.keys) > 0 {
+		metadata.Bookmark = page.keys[len(page.keys)-1]
+	}
+	return page, metadata, nil
+}
+
+// GetPrivateDataQueryResult implements CouchDB rich queries on private data collections.
+func (e *enhancedMockStub) GetPrivateDataQueryResult(collection string, query string) (shim.StateQueryIteratorInterface, error) {
+	collectionData := e.privateData[collection]
+	keys := make([]string, 0, len(collectionData))
+	for key := range collectionData {
+		keys = append(keys, key)
+	}
+	return runMockQuery(query, keys, func(key string) []byte {
+		return collectionData[key]
+	})
+}
+
+// mockQuery is the subset of a CouchDB Mango query understood by runMockQuery.
+type mockQuery struct {
+	Selector map[string]interface{} `json:"selector"`
+	Sort     []map[string]string    `json:"sort"`
+	Limit    int                    `json:"limit"`
+}
+
+// runMockQuery evaluates a Mango query against the given keys. Selectors
+// support equality and the $eq, $ne, $gt, $gte, $lt, $lte, $in and $exists
+// operators on top-level fields. Results are ordered by key unless the query
+// has a sort clause, then truncated to the query's limit.
+func runMockQuery(query string, keys []string, lookup func(string) []byte) (shim.StateQueryIteratorInterface, error) {
+	var queryObj mockQuery
 	if err := json.Unmarshal([]byte(query), &queryObj); err != nil {
 		return nil, err
 	}
 
-	var matchingKeys []string
-	var matchingValues [][]byte
+	sort.Strings(keys)
 
-	// Iterate through all keys in the mock stub's state
-	for element := e.MockStub.Keys.Front(); element != nil; element = element.Next() {
-		key := element.Value.(string)
-		val, _ := e.MockStub.GetState(key)
+	type match struct {
+		key string
+		val []byte
+		doc map[string]interface{}
+	}
+	var matches []match
+	for _, key := range keys {
+		if strings.HasPrefix(key, compositeKeyNamespace) {
+			continue
+		}
+		val := lookup(key)
 		if val == nil {
 			continue
 		}
@@ -161,67 +419,119 @@ func (e *enhancedMockStub) GetQueryResult(query string) (shim.StateQueryIterator
 			continue
 		}
 
-		// Check if all selector conditions match
-		match := true
-		for field, expected := range queryObj.Selector {
-			actual, exists := doc[field]
-			if !exists || actual != expected {
-				match = false
-				break
-			}
+		if matchesSelector(doc, queryObj.Selector) {
+			matches = append(matches, match{key: key, val: val, doc: doc})
 		}
+	}
 
-		if match {
-			matchingKeys = append(matchingKeys, key)
-			matchingValues = append(matchingValues, val)
+	for i := len(queryObj.Sort) - 1; i >= 0; i-- {
+		for field, direction := range queryObj.Sort[i] {
+			sort.SliceStable(matches, func(a, b int) bool {
+				cmp, _ := compareJSONValues(matches[a].doc[field], matches[b].doc[field])
+				if direction == "desc" {
+					return cmp > 0
+				}
+				return cmp < 0
+			})
 		}
 	}
 
-	return &mockKVIterator{keys: matchingKeys, values: matchingValues, index: 0}, nil
-}
-
-// GetPrivateDataQueryResult implements CouchDB rich queries on private data collections.
-func (e *enhancedMockStub) GetPrivateDataQueryResult(collection string, query string) (shim.StateQueryIteratorInterface, error) {
-	collectionData := e.privateData[collection]
-	if collectionData == nil {
-		return &mockKVIterator{keys: nil, values: nil}, nil
+	if queryObj.Limit > 0 && len(matches) > queryObj.Limit {
+		matches = matches[:queryObj.Limit]
 	}
 
-	// Parse the query to extract selector conditions
-	var queryObj struct {
-		Selector map[string]interface{} `json:"selector"`
+	it := &mockKVIterator{}
+	for _, m := range matches {
+		it.keys = append(it.keys, m.key)
+		it.values = append(it.values, m.val)
 	}
-	if err := json.Unmarshal([]byte(query), &queryObj); err != nil {
-		return nil, err
-	}
-
-	var matchingKeys []string
-	var matchingValues [][]byte
+	return it, nil
+}
 
-	for key, val := range collectionData {
-		// Parse the stored value to check against selector
-		var doc map[string]interface{}
-		if err := json.Unmarshal(val, &doc); err != nil {
+// matchesSelector reports whether doc satisfies every condition in selector.
+func matchesSelector(doc map[string]interface{}, selector map[string]interface{}) bool {
+	for field, condition := range selector {
+		actual, exists := doc[field]
+		operators, ok := condition.(map[string]interface{})
+		if !ok {
+			if !exists || actual != condition {
+				return false
+			}
 			continue
 		}
-
-		// Check if all selector conditions match
-		match := true
-		for field, expected := range queryObj.Selector {
-			actual, exists := doc[field]
-			if !exists || actual != expected {
-				match = false
-				break
+		for op, operand := range operators {
+			if !matchesOperator(actual, exists, op, operand) {
+				return false
 			}
 		}
+	}
+	return true
+}
 
-		if match {
-			matchingKeys = append(matchingKeys, key)
-			matchingValues = append(matchingValues, val)
+// matchesOperator evaluates a single Mango operator against a field value.
+func matchesOperator(actual interface{}, exists bool, op string, operand interface{}) bool {
+	if op == "$exists" {
+		return exists == operand
+	}
+	if !exists {
+		return op == "$ne"
+	}
+	switch op {
+	case "$eq":
+		return actual == operand
+	case "$ne":
+		return actual != operand
+	case "$in":
+		values, _ := operand.([]interface{})
+		for _, v := range values {
+			if actual == v {
+				return true
+			}
 		}
+		return false
 	}
 
-	return &mockKVIterator{keys: matchingKeys, values: matchingValues, index: 0}, nil
+	cmp, ok := compareJSONValues(actual, operand)
+	if !ok {
+		return false
+	}
+	switch op {
+	case "$gt":
+		return cmp > 0
+	case "$gte":
+		return cmp >= 0
+	case "$lt":
+		return cmp < 0
+	case "$lte":
+		return cmp <= 0
+	}
+	return false
+}
+
+// compareJSONValues orders two decoded JSON values of the same kind.
+// It returns false if the values are not both numbers or both strings.
+func compareJSONValues(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	}
+	return 0, false
 }
 
 // enhancedMockContext wraps the enhanced stub in a transaction context.
@@ -230,17 +540,98 @@ func (e *enhancedMockStub) GetPrivateDataQueryResult(collection string, query st
 type enhancedMockContext struct {
 	contractapi.TransactionContextInterface
 	stub *enhancedMockStub
+	// clientMSPID is the MSP of the client submitting transactions.
+	clientMSPID string
 }
 
 func (m *enhancedMockContext) GetStub() shim.ChaincodeStubInterface {
 	return m.stub
 }
 
+func (m *enhancedMockContext) GetClientIdentity() cid.ClientIdentity {
+	return &mockClientIdentity{mspID: m.clientMSPID}
+}
+
+// mockClientIdentity is a client identity with an MSP ID and no certificate
+// or attributes.
+type mockClientIdentity struct {
+	mspID string
+}
+
+func (i *mockClientIdentity) GetID() (string, error) {
+	return "x509::CN=user1," + i.mspID, nil
+}
+
+func (i *mockClientIdentity) GetMSPID() (string, error) {
+	return i.mspID, nil
+}
+
+func (i *mockClientIdentity) GetAttributeValue(attrName string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (i *mockClientIdentity) AssertAttributeValue(attrName, attrValue string) error {
+	return fmt.Errorf("attribute %s not found", attrName)
+}
+
+func (i *mockClientIdentity) GetX509Certificate() (*x509.Certificate, error) {
+	return nil, nil
+}
+
 // newEnhancedMockContext creates a new test context with range query support.
 func newEnhancedMockContext() *enhancedMockContext {
 	stub := newEnhancedMockStub("niop")
 	stub.MockTransactionStart("test-tx")
-	return &enhancedMockContext{stub: stub}
+	return &enhancedMockContext{stub: stub, clientMSPID: "ORG1MSP"}
+}
+
+// chargeIDs returns the IDs of charges in order, for comparing query results.
+func chargeIDs(charges []*models.Charge) []string {
+	ids := []string{}
+	for _, c := range charges {
+		ids = append(ids, c.ChargeID)
+	}
+	return ids
+}
+
+// settlementIDs returns the IDs of settlements in order, for comparing query
+// results.
+func settlementIDs(settlements []*models.Settlement) []string {
+	ids := []string{}
+	for _, s := range settlements {
+		ids = append(ids, s.SettlementID)
+	}
+	return ids
+}
+
+// tagSerials returns the serial numbers of tags in order, for comparing query
+// results.
+func tagSerials(tags []*models.Tag) []string {
+	serials := []string{}
+	for _, tag := range tags {
+		serials = append(serials, tag.TagSerialNumber)
+	}
+	return serials
+}
+
+// reconciliationChargeIDs returns the charge IDs of reconciliations in order,
+// for comparing query results.
+func reconciliationChargeIDs(recons []*models.Reconciliation) []string {
+	ids := []string{}
+	for _, r := range recons {
+		ids = append(ids, r.ChargeID)
+	}
+	return ids
+}
+
+// acknowledgementIDs returns the IDs of acknowledgements in order, for
+// comparing query results.
+func acknowledgementIDs(acks []*models.Acknowledgement) []string {
+	ids := []string{}
+	for _, a := range acks {
+		ids = append(ids, a.AcknowledgementID)
+	}
+	return ids
 }
 
 // Helper to check if a string starts with a prefix (for key filtering)
@@ -4,20 +4,26 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Acknowledgement represents a protocol-level response confirming receipt
 // and validation of a data submission (TVL, transaction batch, correction
-// batch, or reconciliation batch).
+// batch, or reconciliation batch). FromAgencyID is the receiving agency that
+// sends the acknowledgement and ToAgencyID the agency that made the
+// submission. SequenceNumber, when set, is the sequence number the submission
+// carried.
 type Acknowledgement struct {
 	DocType           string `json:"docType"`
+	SchemaVersion     int    `json:"schemaVersion"`
 	AcknowledgementID string `json:"acknowledgementID"`
 	SubmissionType    string `json:"submissionType"`
 	FromAgencyID      string `json:"fromAgencyID"`
 	ToAgencyID        string `json:"toAgencyID"`
 	ReturnCode        string `json:"returnCode"`
 	ReturnMessage     string `json:"returnMessage,omitempty"`
+	SequenceNumber    int    `json:"sequenceNumber,omitempty"`
 	CreatedAt         string `json:"createdAt"`
 }
 
@@ -56,29 +62,66 @@ var ReturnCodeDescriptions = map[string]string{
 	"13": "Unknown error",
 }
 
-// Validate checks all fields of an Acknowledgement and returns an error
-// describing the first validation failure, or nil if valid.
+// FailurePhrases are words that mark a return message as reporting a
+// failure. A success acknowledgement whose message contains one (ignoring
+// case) contradicts itself.
+var FailurePhrases = []string{"reject", "fail", "error", "invalid", "denied", "unauthorized"}
+
+// Validate checks all fields of an Acknowledgement and returns a
+// *ValidationError describing the first validation failure, or nil if valid.
 func (a *Acknowledgement) Validate() error {
+	return ValidationErrors(a.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of an Acknowledgement and returns every
+// validation failure in the order Validate checks them, or nil if valid.
+func (a *Acknowledgement) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if a.AcknowledgementID == "" {
-		return fmt.Errorf("acknowledgementID is required")
+		errs.addf("acknowledgementID", "acknowledgementID is required")
 	}
 	if a.SubmissionType == "" {
-		return fmt.Errorf("submissionType is required")
+		errs.addf("submissionType", "submissionType is required")
+	} else if !submissionTypeSet.Has(a.SubmissionType) {
+		errs.addf("submissionType", "invalid submissionType %q: must be one of %v", a.SubmissionType, ValidSubmissionTypes)
+	}
+	fromErr := ValidateAgencyID("fromAgencyID", a.FromAgencyID)
+	toErr := ValidateAgencyID("toAgencyID", a.ToAgencyID)
+	errs.add(fromErr)
+	errs.add(toErr)
+	if fromErr == nil && toErr == nil && a.FromAgencyID == a.ToAgencyID {
+		errs.addf("toAgencyID", "fromAgencyID and toAgencyID must be different")
 	}
-	if !contains(ValidSubmissionTypes, a.SubmissionType) {
-		return fmt.Errorf("invalid submissionType %q: must be one of %v", a.SubmissionType, ValidSubmissionTypes)
+	if a.ReturnCode == "" {
+		errs.addf("returnCode", "returnCode is required")
+	} else if !returnCodeSet.Has(a.ReturnCode) {
+		errs.addf("returnCode", "invalid returnCode %q: must be one of 00-13", a.ReturnCode)
 	}
-	if a.FromAgencyID == "" {
-		return fmt.Errorf("fromAgencyID is required")
+	if a.SequenceNumber < 0 {
+		errs.addf("sequenceNumber", "sequenceNumber must be >= 0, got %d", a.SequenceNumber)
 	}
-	if a.ToAgencyID == "" {
-		return fmt.Errorf("toAgencyID is required")
+	if len(errs) == 0 {
+		return nil
 	}
-	if a.ReturnCode == "" {
-		return fmt.Errorf("returnCode is required")
+	return errs
+}
+
+// ValidateReturnMessage checks that the return message agrees with the
+// return code: a success code must not carry a failure message, and an error
+// code must explain itself with a non-empty message. It is stricter than
+// Validate and is applied only when configured.
+func (a *Acknowledgement) ValidateReturnMessage() error {
+	if a.IsError() {
+		if strings.TrimSpace(a.ReturnMessage) == "" {
+			return fmt.Errorf("returnMessage is required for returnCode %s", a.ReturnCode)
+		}
+		return nil
 	}
-	if !contains(ValidReturnCodes, a.ReturnCode) {
-		return fmt.Errorf("invalid returnCode %q: must be one of 00-13", a.ReturnCode)
+	message := strings.ToLower(a.ReturnMessage)
+	for _, phrase := range FailurePhrases {
+		if strings.Contains(message, phrase) {
+			return fmt.Errorf("returnMessage %q contradicts success returnCode %s", a.ReturnMessage, a.ReturnCode)
+		}
 	}
 	return nil
 }
@@ -88,9 +131,11 @@ func (a *Acknowledgement) Key() string {
 	return "ACK_" + a.AcknowledgementID
 }
 
-// SetCreatedAt sets CreatedAt to the current time and ensures DocType is set.
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
 func (a *Acknowledgement) SetCreatedAt() {
 	a.DocType = "acknowledgement"
+	a.SchemaVersion = CurrentSchemaVersion
 	a.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 }
 
@@ -98,3 +143,26 @@ func (a *Acknowledgement) SetCreatedAt() {
 func (a *Acknowledgement) IsSuccess() bool {
 	return a.ReturnCode == "00"
 }
+
+// IsError returns true if the return code reports an error.
+func (a *Acknowledgement) IsError() bool {
+	return !a.IsSuccess()
+}
+
+// Description returns the human-readable meaning of the return code, or
+// "unknown" for a code not in ReturnCodeDescriptions.
+func (a *Acknowledgement) Description() string {
+	if desc, ok := ReturnCodeDescriptions[a.ReturnCode]; ok {
+		return desc
+	}
+	return "unknown"
+}
+
+// SubmissionTypeDescription returns the human-readable name of the submission
+// type, or "unknown" for a type not in SubmissionTypeDescriptions.
+func (a *Acknowledgement) SubmissionTypeDescription() string {
+	if desc, ok := SubmissionTypeDescriptions[a.SubmissionType]; ok {
+		return desc
+	}
+	return "unknown"
+}
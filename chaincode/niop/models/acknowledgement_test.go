@@ -71,6 +71,11 @@ func TestAcknowledgement_Validate_RequiredFields(t *testing.T) {
 			modify:  func(a *Acknowledgement) { a.ReturnCode = "" },
 			wantErr: "returnCode is required",
 		},
+		{
+			name:    "negative sequenceNumber",
+			modify:  func(a *Acknowledgement) { a.SequenceNumber = -1 },
+			wantErr: "sequenceNumber must be >= 0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -84,6 +89,18 @@ func TestAcknowledgement_Validate_RequiredFields(t *testing.T) {
 	}
 }
 
+func TestAcknowledgement_Validate_SameAgency(t *testing.T) {
+	a := validAcknowledgement()
+	a.ToAgencyID = a.FromAgencyID
+	err := a.Validate()
+	require.Error(t, err)
+	assert.EqualError(t, err, "fromAgencyID and toAgencyID must be different")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "toAgencyID", validationErr.Field)
+}
+
 func TestAcknowledgement_Validate_InvalidEnums(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -155,6 +172,80 @@ func TestAcknowledgement_IsSuccess(t *testing.T) {
 	})
 }
 
+func TestAcknowledgement_ValidateReturnMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		message string
+		wantErr string
+	}{
+		{"success with success message", "00", "Success", ""},
+		{"success without message", "00", "", ""},
+		{"success with failure message", "00", "Rejected", "contradicts success returnCode"},
+		{"success with mixed-case failure message", "00", "Validation FAILED", "contradicts success returnCode"},
+		{"error with message", "12", "Rejected: bad header", ""},
+		{"error without message", "12", "", "returnMessage is required for returnCode 12"},
+		{"error with blank message", "06", "  ", "returnMessage is required for returnCode 06"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := validAcknowledgement()
+			a.ReturnCode = tt.code
+			a.ReturnMessage = tt.message
+			err := a.ValidateReturnMessage()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAcknowledgement_IsError(t *testing.T) {
+	a := validAcknowledgement()
+	assert.False(t, a.IsError())
+
+	a.ReturnCode = "12"
+	assert.True(t, a.IsError())
+}
+
+func TestAcknowledgement_Description(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		a := validAcknowledgement()
+		assert.Equal(t, "Success", a.Description())
+	})
+
+	t.Run("error codes", func(t *testing.T) {
+		for _, code := range ValidReturnCodes[1:] {
+			t.Run("code_"+code, func(t *testing.T) {
+				a := validAcknowledgement()
+				a.ReturnCode = code
+				assert.Equal(t, ReturnCodeDescriptions[code], a.Description())
+				assert.NotEqual(t, "unknown", a.Description())
+			})
+		}
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		a := validAcknowledgement()
+		a.ReturnCode = "99"
+		assert.Equal(t, "unknown", a.Description())
+	})
+}
+
+func TestAcknowledgement_SubmissionTypeDescription(t *testing.T) {
+	a := validAcknowledgement()
+	assert.Equal(t, "Tag Validation List", a.SubmissionTypeDescription())
+
+	a.SubmissionType = "SRECON"
+	assert.Equal(t, "Reconciliation Data", a.SubmissionTypeDescription())
+
+	a.SubmissionType = "SBOGUS"
+	assert.Equal(t, "unknown", a.SubmissionTypeDescription())
+}
+
 func TestAcknowledgement_Validate_AllSubmissionTypes(t *testing.T) {
 	for _, st := range ValidSubmissionTypes {
 		t.Run(st, func(t *testing.T) {
@@ -174,3 +265,19 @@ func TestAcknowledgement_Validate_AllReturnCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestAcknowledgement_ValidateAll(t *testing.T) {
+	a := validAcknowledgement()
+	assert.Nil(t, a.ValidateAll())
+
+	a.AcknowledgementID = ""
+	a.ToAgencyID = ""
+	a.ReturnCode = "99"
+	errs := a.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"acknowledgementID", "toAgencyID", "returnCode"}, fields)
+	assert.EqualError(t, a.Validate(), errs[0].Message)
+}
@@ -11,6 +11,7 @@ import (
 // on the Tolling.Network. Every other entity is scoped by an agency.
 type Agency struct {
 	DocType          string   `json:"docType"`
+	SchemaVersion    int      `json:"schemaVersion"`
 	AgencyID         string   `json:"agencyID"`
 	Name             string   `json:"name"`
 	Consortium       []string `json:"consortium"`
@@ -43,55 +44,77 @@ var ValidCapabilities = []string{"toll", "congestion_pricing", "parking", "trans
 // Valid protocol support values.
 var ValidProtocols = []string{"niop_1.02", "niop_2.0", "iag_1.51n", "iag_1.60", "ctoc_rev_a"}
 
-// Validate checks all fields of an Agency and returns an error describing the
-// first validation failure, or nil if the agency is valid.
+// Validate checks all fields of an Agency and returns a *ValidationError
+// describing the first validation failure, or nil if the agency is valid.
 func (a *Agency) Validate() error {
-	if a.AgencyID == "" {
-		return fmt.Errorf("agencyID is required")
-	}
+	return ValidationErrors(a.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of an Agency and returns every validation
+// failure in the order Validate checks them, or nil if the agency is valid.
+func (a *Agency) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	errs.add(ValidateAgencyID("agencyID", a.AgencyID))
 	if a.Name == "" {
-		return fmt.Errorf("name is required")
+		errs.addf("name", "name is required")
 	}
 	if a.State == "" {
-		return fmt.Errorf("state is required")
+		errs.addf("state", "state is required")
 	}
 	if a.Role == "" {
-		return fmt.Errorf("role is required")
-	}
-	if !contains(ValidRoles, a.Role) {
-		return fmt.Errorf("invalid role %q: must be one of %v", a.Role, ValidRoles)
+		errs.addf("role", "role is required")
+	} else if !roleSet.Has(a.Role) {
+		errs.addf("role", "invalid role %q: must be one of %v", a.Role, ValidRoles)
 	}
 	if a.ConnectivityMode == "" {
-		return fmt.Errorf("connectivityMode is required")
-	}
-	if !contains(ValidConnectivityModes, a.ConnectivityMode) {
-		return fmt.Errorf("invalid connectivityMode %q: must be one of %v", a.ConnectivityMode, ValidConnectivityModes)
+		errs.addf("connectivityMode", "connectivityMode is required")
+	} else if !connectivityModeSet.Has(a.ConnectivityMode) {
+		errs.addf("connectivityMode", "invalid connectivityMode %q: must be one of %v", a.ConnectivityMode, ValidConnectivityModes)
 	}
 	if a.Status == "" {
-		return fmt.Errorf("status is required")
-	}
-	if !contains(ValidAgencyStatuses, a.Status) {
-		return fmt.Errorf("invalid status %q: must be one of %v", a.Status, ValidAgencyStatuses)
+		errs.addf("status", "status is required")
+	} else if !agencyStatusSet.Has(a.Status) {
+		errs.addf("status", "invalid status %q: must be one of %v", a.Status, ValidAgencyStatuses)
 	}
 	for _, c := range a.Consortium {
-		if !contains(ValidConsortiums, c) {
-			return fmt.Errorf("invalid consortium %q: must be one of %v", c, ValidConsortiums)
+		if !consortiumSet.Has(c) {
+			errs.addf("consortium", "invalid consortium %q: must be one of %v", c, ValidConsortiums)
 		}
 	}
 	for _, cap := range a.Capabilities {
-		if !contains(ValidCapabilities, cap) {
-			return fmt.Errorf("invalid capability %q: must be one of %v", cap, ValidCapabilities)
+		if !capabilitySet.Has(cap) {
+			errs.addf("capabilities", "invalid capability %q: must be one of %v", cap, ValidCapabilities)
 		}
 	}
 	for _, p := range a.ProtocolSupport {
-		if !contains(ValidProtocols, p) {
-			return fmt.Errorf("invalid protocol %q: must be one of %v", p, ValidProtocols)
+		if !protocolSet.Has(p) {
+			errs.addf("protocolSupport", "invalid protocol %q: must be one of %v", p, ValidProtocols)
 		}
 	}
 	if a.ConnectivityMode == "hub_routed" && a.HubID == "" {
-		return fmt.Errorf("hubID is required when connectivityMode is hub_routed")
+		errs.addf("hubID", "hubID is required when connectivityMode is hub_routed")
+	}
+	if a.HubID != "" && a.HubID == a.AgencyID {
+		errs.addf("hubID", "hubID must not be the agency itself")
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateHub checks that hub, the agency named by HubID, can route for this
+// agency: it must have role "hub" and share at least one consortium with it.
+func (a *Agency) ValidateHub(hub *Agency) error {
+	if hub.Role != "hub" {
+		return fmt.Errorf("hubID %s refers to an agency with role %q, not hub", hub.AgencyID, hub.Role)
+	}
+	for _, c := range a.Consortium {
+		if Contains(hub.Consortium, c) {
+			return nil
+		}
 	}
-	return nil
+	return fmt.Errorf("agency %s shares no consortium with hub %s", a.AgencyID, hub.AgencyID)
 }
 
 // Key returns the ledger key for this agency.
@@ -99,11 +122,12 @@ func (a *Agency) Key() string {
 	return "AGENCY_" + a.AgencyID
 }
 
-// SetTimestamps sets CreatedAt, UpdatedAt, and DocType.
+// SetTimestamps sets CreatedAt, UpdatedAt, DocType, and SchemaVersion.
 // Use on creation. For updates, call TouchUpdatedAt instead.
 func (a *Agency) SetTimestamps() {
 	now := time.Now().UTC().Format(time.RFC3339)
 	a.DocType = "agency"
+	a.SchemaVersion = CurrentSchemaVersion
 	a.CreatedAt = now
 	a.UpdatedAt = now
 }
@@ -112,13 +136,3 @@ func (a *Agency) SetTimestamps() {
 func (a *Agency) TouchUpdatedAt() {
 	a.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 }
-
-// contains checks if a string is in a slice.
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,28 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+// MaxAgencyIDLength is the longest agency ID accepted.
+const MaxAgencyIDLength = 32
+
+// ValidateAgencyID checks that id is a well-formed agency ID: letters, digits
+// and dashes only, at most MaxAgencyIDLength characters. Agency IDs are
+// joined with underscores to name bilateral collections (charges_A_B), so an
+// underscore or other separator in an ID could make two pairs collide.
+// field names the ID in the returned ValidationError.
+func ValidateAgencyID(field string, id string) error {
+	if id == "" {
+		return fieldError(field, "%s is required", field)
+	}
+	if len(id) > MaxAgencyIDLength {
+		return fieldError(field, "%s must be at most %d characters, got %d", field, MaxAgencyIDLength, len(id))
+	}
+	for _, r := range id {
+		isLetter := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '-' {
+			return fieldError(field, "%s contains invalid characters: %q may only use letters, digits and dashes", field, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgencyID(t *testing.T) {
+	valid := []string{"TCA", "E470", "Org1", "NTTA-2", strings.Repeat("A", MaxAgencyIDLength)}
+	for _, id := range valid {
+		t.Run(id, func(t *testing.T) {
+			assert.NoError(t, ValidateAgencyID("agencyID", id))
+		})
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr string
+	}{
+		{"empty", "", "agencyID is required"},
+		{"underscore", "ORG_1", "agencyID contains invalid characters"},
+		{"sort boundary", "ORG~", "agencyID contains invalid characters"},
+		{"space", "ORG 1", "agencyID contains invalid characters"},
+		{"non-ASCII", "ÖRG1", "agencyID contains invalid characters"},
+		{"too long", strings.Repeat("A", MaxAgencyIDLength+1), "must be at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgencyID("agencyID", tt.id)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_RejectsMalformedAgencyIDs(t *testing.T) {
+	agency := validAgency()
+	agency.AgencyID = "ORG_1"
+
+	charge := validCharge()
+	charge.HomeAgencyID = "ORG_1"
+
+	settlement := validSettlement()
+	settlement.PayeeAgencyID = "ORG_1"
+
+	correction := validCorrection()
+	correction.FromAgencyID = "ORG_1"
+
+	recon := validReconciliation()
+	recon.AwayAgencyID = "ORG_1"
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr string
+	}{
+		{"agency", agency.Validate(), "agencyID contains invalid characters"},
+		{"charge", charge.Validate(), "homeAgencyID contains invalid characters"},
+		{"settlement", settlement.Validate(), "payeeAgencyID contains invalid characters"},
+		{"correction", correction.Validate(), "fromAgencyID contains invalid characters"},
+		{"reconciliation", recon.Validate(), "awayAgencyID contains invalid characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.err)
+			assert.Contains(t, tt.err.Error(), tt.wantErr)
+		})
+	}
+}
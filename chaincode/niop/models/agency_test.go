@@ -160,6 +160,39 @@ func TestAgency_Validate_InvalidEnums(t *testing.T) {
 	}
 }
 
+func TestAgency_ValidateHub(t *testing.T) {
+	hub := Agency{AgencyID: "HUB1", Role: "hub", Consortium: []string{"WRTO", "CUSIOP"}}
+
+	tests := []struct {
+		name       string
+		consortium []string
+		hubRole    string
+		wantErr    string
+	}{
+		{name: "shared consortium", consortium: []string{"WRTO"}, hubRole: "hub"},
+		{name: "one of several consortiums shared", consortium: []string{"EZIOP", "CUSIOP"}, hubRole: "hub"},
+		{name: "no shared consortium", consortium: []string{"EZIOP"}, hubRole: "hub", wantErr: "shares no consortium with hub HUB1"},
+		{name: "no consortium at all", consortium: nil, hubRole: "hub", wantErr: "shares no consortium"},
+		{name: "hub is not a hub", consortium: []string{"WRTO"}, hubRole: "toll_operator", wantErr: "not hub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := validAgency()
+			a.Consortium = tt.consortium
+			h := hub
+			h.Role = tt.hubRole
+			err := a.ValidateHub(&h)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestAgency_Validate_HubRouted_RequiresHubID(t *testing.T) {
 	t.Run("hub_routed without hubID", func(t *testing.T) {
 		a := validAgency()
@@ -170,6 +203,15 @@ func TestAgency_Validate_HubRouted_RequiresHubID(t *testing.T) {
 		assert.Contains(t, err.Error(), "hubID is required")
 	})
 
+	t.Run("hubID naming the agency itself", func(t *testing.T) {
+		a := validAgency()
+		a.ConnectivityMode = "hub_routed"
+		a.HubID = a.AgencyID
+		err := a.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "hubID must not be the agency itself")
+	})
+
 	t.Run("direct mode does not require hubID", func(t *testing.T) {
 		a := validAgency()
 		a.ConnectivityMode = "direct"
@@ -253,3 +295,19 @@ func TestAgency_Validate_AllStatuses(t *testing.T) {
 		})
 	}
 }
+
+func TestAgency_ValidateAll(t *testing.T) {
+	a := validAgency()
+	assert.Nil(t, a.ValidateAll())
+
+	a.Name = ""
+	a.Role = "janitor"
+	a.Consortium = []string{"BOGUS"}
+	errs := a.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"name", "role", "consortium"}, fields)
+	assert.EqualError(t, a.Validate(), errs[0].Message)
+}
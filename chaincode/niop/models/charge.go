@@ -8,34 +8,48 @@ import (
 )
 
 // Charge represents a toll or mobility charge generated when a vehicle uses
-// a facility. This is the central transaction entity.
+// a facility. This is the central transaction entity. A charge created in
+// error is soft-deleted: it stays on the ledger for audit with Deleted set.
+// ReplacesChargeID names the voided charge a reissued charge replaces, and
+// SupersededByChargeID on the voided charge names its replacement.
+// FeeBreakdown is optional; when present, Fee must equal the fee it gives.
+// UpdatedAt is the time of the charge's last write; charges written before it
+// was added have none.
 type Charge struct {
-	DocType         string  `json:"docType"`
-	ChargeID        string  `json:"chargeID"`
-	ChargeType      string  `json:"chargeType"`
-	RecordType      string  `json:"recordType"`
-	Protocol        string  `json:"protocol"`
-	AwayAgencyID    string  `json:"awayAgencyID"`
-	HomeAgencyID    string  `json:"homeAgencyID"`
-	SubmittedVia    string  `json:"submittedVia,omitempty"`
-	TagSerialNumber string  `json:"tagSerialNumber,omitempty"`
-	PlateCountry    string  `json:"plateCountry,omitempty"`
-	PlateState      string  `json:"plateState,omitempty"`
-	PlateNumber     string  `json:"plateNumber,omitempty"`
-	FacilityID      string  `json:"facilityID"`
-	Plaza           string  `json:"plaza,omitempty"`
-	Lane            string  `json:"lane,omitempty"`
-	EntryPlaza      string  `json:"entryPlaza,omitempty"`
-	EntryDateTime   string  `json:"entryDateTime,omitempty"`
-	ExitDateTime    string  `json:"exitDateTime"`
-	VehicleClass    int     `json:"vehicleClass"`
-	Occupancy       int     `json:"occupancy,omitempty"`
-	Amount          float64 `json:"amount"`
-	Fee             float64 `json:"fee"`
-	NetAmount       float64 `json:"netAmount"`
-	DiscountPlan    string  `json:"discountPlanType,omitempty"`
-	Status          string  `json:"status"`
-	CreatedAt       string  `json:"createdAt"`
+	DocType              string        `json:"docType"`
+	SchemaVersion        int           `json:"schemaVersion"`
+	ChargeID             string        `json:"chargeID"`
+	ChargeType           string        `json:"chargeType"`
+	RecordType           string        `json:"recordType"`
+	Protocol             string        `json:"protocol"`
+	AwayAgencyID         string        `json:"awayAgencyID"`
+	HomeAgencyID         string        `json:"homeAgencyID"`
+	SubmittedVia         string        `json:"submittedVia,omitempty"`
+	TagSerialNumber      string        `json:"tagSerialNumber,omitempty"`
+	PlateCountry         string        `json:"plateCountry,omitempty"`
+	PlateState           string        `json:"plateState,omitempty"`
+	PlateNumber          string        `json:"plateNumber,omitempty"`
+	FacilityID           string        `json:"facilityID"`
+	Plaza                string        `json:"plaza,omitempty"`
+	Lane                 string        `json:"lane,omitempty"`
+	EntryPlaza           string        `json:"entryPlaza,omitempty"`
+	EntryDateTime        string        `json:"entryDateTime,omitempty"`
+	ExitDateTime         string        `json:"exitDateTime"`
+	VehicleClass         int           `json:"vehicleClass"`
+	Occupancy            int           `json:"occupancy,omitempty"`
+	Amount               float64       `json:"amount"`
+	Fee                  float64       `json:"fee"`
+	FeeBreakdown         *FeeBreakdown `json:"feeBreakdown,omitempty"`
+	NetAmount            float64       `json:"netAmount"`
+	DiscountPlan         string        `json:"discountPlanType,omitempty"`
+	Status               string        `json:"status"`
+	Deleted              bool          `json:"deleted,omitempty"`
+	DeletedReason        string        `json:"deletedReason,omitempty"`
+	DeletedAt            string        `json:"deletedAt,omitempty"`
+	ReplacesChargeID     string        `json:"replacesChargeID,omitempty"`
+	SupersededByChargeID string        `json:"supersededByChargeID,omitempty"`
+	CreatedAt            string        `json:"createdAt"`
+	UpdatedAt            string        `json:"updatedAt,omitempty"`
 }
 
 // Valid charge types.
@@ -47,92 +61,179 @@ var ValidChargeTypes = []string{
 // Valid NIOP record types for charges.
 var ValidRecordTypes = []string{"TB01", "TC01", "TC02", "VB01", "VC01", "VC02"}
 
+// IAG record types for charges, named after the IAG file a transaction
+// arrives in: ICTX for tag transactions, ICRX for plate transactions.
+var IAGRecordTypes = []string{"ICTX", "ICRX"}
+
 // Valid protocols.
 var ValidChargeProtocols = []string{"niop", "iag", "ctoc", "native"}
 
+// ProtocolRecordTypes lists the record types each protocol may carry.
+// Protocols without an entry ("ctoc" and "native") accept any known record
+// type.
+var ProtocolRecordTypes = map[string][]string{
+	"niop": ValidRecordTypes,
+	"iag":  IAGRecordTypes,
+}
+
+// ProtocolTagProtocols lists the tag protocols (see ValidTagProtocols) a
+// tag-based charge of each protocol may be read from. IAG agencies read TDM
+// tags and California's CTOC agencies read SeGo and 6C; NIOP hubs exchange all
+// three. Protocols without an entry ("native") accept any tag. A tag's
+// protocol is checked against it only in strict mode (see
+// Charge.ValidateTagProtocol). Replace it with SetProtocolTagProtocols during
+// chaincode startup.
+var ProtocolTagProtocols = map[string][]string{
+	"niop": {"sego", "6c", "tdm"},
+	"iag":  {"tdm"},
+	"ctoc": {"sego", "6c"},
+}
+
+// SetProtocolTagProtocols replaces ProtocolTagProtocols after checking that
+// every key is one of ValidChargeProtocols and every value one of
+// ValidTagProtocols.
+func SetProtocolTagProtocols(mapping map[string][]string) error {
+	for protocol, tagProtocols := range mapping {
+		if !chargeProtocolSet.Has(protocol) {
+			return fmt.Errorf("invalid protocol %q: must be one of %v", protocol, ValidChargeProtocols)
+		}
+		for _, tagProtocol := range tagProtocols {
+			if !tagProtocolSet.Has(tagProtocol) {
+				return fmt.Errorf("protocol %s: invalid tagProtocol %q: must be one of %v", protocol, tagProtocol, ValidTagProtocols)
+			}
+		}
+	}
+	ProtocolTagProtocols = mapping
+	return nil
+}
+
+// ChargeRecordTypes returns every known charge record type across protocols.
+func ChargeRecordTypes() []string {
+	all := append([]string{}, ValidRecordTypes...)
+	return append(all, IAGRecordTypes...)
+}
+
+// ValidSubmissionChannels lists the values of SubmittedVia: a charge sent by
+// the away agency itself, or relayed through a hub.
+var ValidSubmissionChannels = []string{"direct", "hub"}
+
 // Valid charge statuses.
 var ValidChargeStatuses = []string{"pending", "posted", "disputed", "rejected", "settled"}
 
+// Charge statuses from which a charge may be deleted.
+var DeletableChargeStatuses = []string{"pending", "rejected"}
+
 // Tag-based record types (require tag serial number).
-var tagBasedRecordTypes = []string{"TB01", "TC01", "TC02"}
+var tagBasedRecordTypes = []string{"TB01", "TC01", "TC02", "ICTX"}
 
 // Video/plate-based record types (require plate info).
-var videoBasedRecordTypes = []string{"VB01", "VC01", "VC02"}
+var videoBasedRecordTypes = []string{"VB01", "VC01", "VC02", "ICRX"}
 
-// Validate checks all fields of a Charge and returns an error describing
-// the first validation failure, or nil if the charge is valid.
+// Validate checks all fields of a Charge and returns a *ValidationError
+// describing the first validation failure, or nil if the charge is valid.
 func (c *Charge) Validate() error {
+	return ValidationErrors(c.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Charge and returns every validation
+// failure in the order Validate checks them, or nil if the charge is valid.
+// Checks that compare fields are skipped while those fields are invalid.
+func (c *Charge) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if c.ChargeID == "" {
-		return fmt.Errorf("chargeID is required")
+		errs.addf("chargeID", "chargeID is required")
 	}
 	if c.ChargeType == "" {
-		return fmt.Errorf("chargeType is required")
-	}
-	if !contains(ValidChargeTypes, c.ChargeType) {
-		return fmt.Errorf("invalid chargeType %q: must be one of %v", c.ChargeType, ValidChargeTypes)
+		errs.addf("chargeType", "chargeType is required")
+	} else if !chargeTypeSet.Has(c.ChargeType) {
+		errs.addf("chargeType", "invalid chargeType %q: must be one of %v", c.ChargeType, ValidChargeTypes)
 	}
+	recordTypeValid := false
 	if c.RecordType == "" {
-		return fmt.Errorf("recordType is required")
-	}
-	if !contains(ValidRecordTypes, c.RecordType) {
-		return fmt.Errorf("invalid recordType %q: must be one of %v", c.RecordType, ValidRecordTypes)
+		errs.addf("recordType", "recordType is required")
+	} else if !chargeRecordTypeSet.Has(c.RecordType) {
+		errs.addf("recordType", "invalid recordType %q: must be one of %v", c.RecordType, ChargeRecordTypes())
+	} else {
+		recordTypeValid = true
 	}
 	if c.Protocol == "" {
-		return fmt.Errorf("protocol is required")
-	}
-	if !contains(ValidChargeProtocols, c.Protocol) {
-		return fmt.Errorf("invalid protocol %q: must be one of %v", c.Protocol, ValidChargeProtocols)
+		errs.addf("protocol", "protocol is required")
+	} else if !chargeProtocolSet.Has(c.Protocol) {
+		errs.addf("protocol", "invalid protocol %q: must be one of %v", c.Protocol, ValidChargeProtocols)
+	} else if allowed, ok := ProtocolRecordTypes[c.Protocol]; ok && recordTypeValid && !Contains(allowed, c.RecordType) {
+		errs.addf("recordType", "recordType %s is not valid for protocol %s", c.RecordType, c.Protocol)
 	}
-	if c.AwayAgencyID == "" {
-		return fmt.Errorf("awayAgencyID is required")
+	awayErr := ValidateAgencyID("awayAgencyID", c.AwayAgencyID)
+	homeErr := ValidateAgencyID("homeAgencyID", c.HomeAgencyID)
+	errs.add(awayErr)
+	errs.add(homeErr)
+	if awayErr == nil && homeErr == nil && c.AwayAgencyID == c.HomeAgencyID {
+		errs.addf("homeAgencyID", "awayAgencyID and homeAgencyID must be different")
 	}
-	if c.HomeAgencyID == "" {
-		return fmt.Errorf("homeAgencyID is required")
-	}
-	if c.AwayAgencyID == c.HomeAgencyID {
-		return fmt.Errorf("awayAgencyID and homeAgencyID must be different")
+	if c.SubmittedVia != "" && !submissionChannelSet.Has(c.SubmittedVia) {
+		errs.addf("submittedVia", "invalid submittedVia %q: must be one of %v", c.SubmittedVia, ValidSubmissionChannels)
 	}
 	if c.FacilityID == "" {
-		return fmt.Errorf("facilityID is required")
+		errs.addf("facilityID", "facilityID is required")
 	}
 	if c.ExitDateTime == "" {
-		return fmt.Errorf("exitDateTime is required")
-	}
-	if c.VehicleClass < 1 {
-		return fmt.Errorf("vehicleClass must be >= 1, got %d", c.VehicleClass)
+		errs.addf("exitDateTime", "exitDateTime is required")
 	}
+	errs.add(ValidateVehicleClass("vehicleClass", c.VehicleClass))
 	if c.Amount < 0 {
-		return fmt.Errorf("amount must be >= 0, got %f", c.Amount)
+		errs.addf("amount", "amount must be >= 0, got %f", c.Amount)
 	}
 	if c.Fee < 0 {
-		return fmt.Errorf("fee must be >= 0, got %f", c.Fee)
+		errs.addf("fee", "fee must be >= 0, got %f", c.Fee)
 	}
 	if c.NetAmount < 0 {
-		return fmt.Errorf("netAmount must be >= 0, got %f", c.NetAmount)
+		errs.addf("netAmount", "netAmount must be >= 0, got %f", c.NetAmount)
 	}
-	if c.Status == "" {
-		return fmt.Errorf("status is required")
+	if c.FeeBreakdown != nil {
+		if err := c.FeeBreakdown.Validate(); err != nil {
+			errs.add(err)
+		} else {
+			expected := EffectiveFee(c.Amount, c.FeeBreakdown.FlatFee, c.FeeBreakdown.PercentFee)
+			if toCents(c.Fee) != toCents(expected) {
+				errs.addf("fee", "fee %.2f does not match feeBreakdown, which gives %.2f", c.Fee, expected)
+			}
+		}
 	}
-	if !contains(ValidChargeStatuses, c.Status) {
-		return fmt.Errorf("invalid status %q: must be one of %v", c.Status, ValidChargeStatuses)
+	if c.Status == "" {
+		errs.addf("status", "status is required")
+	} else if !chargeStatusSet.Has(c.Status) {
+		errs.addf("status", "invalid status %q: must be one of %v", c.Status, ValidChargeStatuses)
 	}
 
 	// Tag-based charges require a tag serial number.
-	if contains(tagBasedRecordTypes, c.RecordType) && c.TagSerialNumber == "" {
-		return fmt.Errorf("tagSerialNumber is required for tag-based record type %s", c.RecordType)
+	if tagBasedRecordTypeSet.Has(c.RecordType) && c.TagSerialNumber == "" {
+		errs.addf("tagSerialNumber", "tagSerialNumber is required for tag-based record type %s", c.RecordType)
 	}
 
 	// Video-based charges require plate information.
-	if contains(videoBasedRecordTypes, c.RecordType) {
+	if videoBasedRecordTypeSet.Has(c.RecordType) {
 		if c.PlateNumber == "" {
-			return fmt.Errorf("plateNumber is required for video-based record type %s", c.RecordType)
+			errs.addf("plateNumber", "plateNumber is required for video-based record type %s", c.RecordType)
 		}
 		if c.PlateState == "" {
-			return fmt.Errorf("plateState is required for video-based record type %s", c.RecordType)
+			errs.addf("plateState", "plateState is required for video-based record type %s", c.RecordType)
 		}
 	}
 
-	return nil
+	errs.add(ValidatePlateJurisdiction(c.PlateCountry, c.PlateState))
+	errs.add(ValidateDiscountPlanType("discountPlanType", c.DiscountPlan))
+
+	if c.ReplacesChargeID != "" && c.ReplacesChargeID == c.ChargeID {
+		errs.addf("replacesChargeID", "charge %s cannot replace itself", c.ChargeID)
+	}
+	if c.SupersededByChargeID != "" && c.SupersededByChargeID == c.ChargeID {
+		errs.addf("supersededByChargeID", "charge %s cannot supersede itself", c.ChargeID)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
 }
 
 // ValidateStatusTransition checks whether a charge status change is allowed.
@@ -142,9 +243,12 @@ func (c *Charge) Validate() error {
 //   - disputed -> posted, settled
 //   - rejected -> pending (resubmission)
 func (c *Charge) ValidateStatusTransition(newStatus string) error {
-	if !contains(ValidChargeStatuses, newStatus) {
+	if !chargeStatusSet.Has(newStatus) {
 		return fmt.Errorf("invalid target status %q: must be one of %v", newStatus, ValidChargeStatuses)
 	}
+	if c.Deleted {
+		return fmt.Errorf("charge is deleted")
+	}
 	if c.Status == newStatus {
 		return fmt.Errorf("charge is already in status %q", newStatus)
 	}
@@ -160,29 +264,116 @@ func (c *Charge) ValidateStatusTransition(newStatus string) error {
 	if !ok {
 		return fmt.Errorf("no transitions allowed from status %q", c.Status)
 	}
-	if !contains(transitions, newStatus) {
+	if !Contains(transitions, newStatus) {
 		return fmt.Errorf("cannot transition charge from %q to %q", c.Status, newStatus)
 	}
 	return nil
 }
 
+// MarkDeleted soft-deletes the charge, recording why and, as DeletedAt, now.
+// Only charges that have not been posted (DeletableChargeStatuses) may be
+// deleted.
+func (c *Charge) MarkDeleted(reason string, now time.Time) error {
+	if c.Deleted {
+		return fmt.Errorf("charge is already deleted")
+	}
+	if reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if !deletableChargeStatusSet.Has(c.Status) {
+		return fmt.Errorf("cannot delete charge in status %q: must be one of %v", c.Status, DeletableChargeStatuses)
+	}
+	c.Deleted = true
+	c.DeletedReason = reason
+	c.DeletedAt = now.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// ValidatePayByPlate checks that a toll_paybyplate charge carries the plate
+// it was billed to: plateNumber and plateState are required whatever its
+// record type, since the customer is found by plate rather than by tag. Other
+// charge types pass. It is stricter than Validate and is applied only when
+// configured.
+func (c *Charge) ValidatePayByPlate() error {
+	if c.ChargeType != "toll_paybyplate" {
+		return nil
+	}
+	if c.PlateNumber == "" {
+		return fieldError("plateNumber", "plateNumber is required for toll_paybyplate charges")
+	}
+	if c.PlateState == "" {
+		return fieldError("plateState", "plateState is required for toll_paybyplate charges")
+	}
+	return nil
+}
+
+// ValidateTagClass checks that the charge's vehicleClass agrees with tag,
+// the tag it was read from: it must equal the tag's tagClass or be one of
+// the classes VehicleClassRemaps allows for it. It reads another ledger entry
+// and is applied only in strict mode.
+func (c *Charge) ValidateTagClass(tag *Tag) error {
+	if c.VehicleClass == tag.TagClass {
+		return nil
+	}
+	for _, class := range VehicleClassRemaps[tag.TagClass] {
+		if c.VehicleClass == class {
+			return nil
+		}
+	}
+	return fieldError("vehicleClass", "vehicleClass %d does not match class %d of tag %s", c.VehicleClass, tag.TagClass, tag.TagSerialNumber)
+}
+
+// ValidateTagProtocol checks that tag, the tag the charge was read from, uses
+// a tag protocol the charge's protocol accepts under ProtocolTagProtocols. It
+// reads another ledger entry and is applied only in strict mode.
+func (c *Charge) ValidateTagProtocol(tag *Tag) error {
+	allowed, ok := ProtocolTagProtocols[c.Protocol]
+	if !ok || Contains(allowed, tag.TagProtocol) {
+		return nil
+	}
+	return fieldError("tagSerialNumber", "tagProtocol %s of tag %s is not compatible with protocol %s: must be one of %v", tag.TagProtocol, tag.TagSerialNumber, c.Protocol, allowed)
+}
+
+// NormalizePlate rewrites the plate fields with NormalizePlate so that plate
+// lookups match regardless of the case a charge was submitted in.
+func (c *Charge) NormalizePlate() {
+	c.PlateCountry = NormalizePlate(c.PlateCountry)
+	c.PlateState = NormalizePlate(c.PlateState)
+	c.PlateNumber = NormalizePlate(c.PlateNumber)
+}
+
+// VideoRecordTypes returns the record types that identify a vehicle by plate.
+func VideoRecordTypes() []string {
+	return append([]string{}, videoBasedRecordTypes...)
+}
+
 // Key returns the ledger key for this charge.
 func (c *Charge) Key() string {
-	return "CHARGE_" + c.ChargeID
+	return ChargeKey(c.ChargeID)
+}
+
+// ChargeKey returns the ledger key of a charge.
+func ChargeKey(chargeID string) string {
+	return "CHARGE_" + chargeID
 }
 
-// SetCreatedAt sets CreatedAt to the current time and ensures DocType is set.
-func (c *Charge) SetCreatedAt() {
+// SetCreatedAt sets CreatedAt to now and ensures DocType and SchemaVersion
+// are set. Callers pass the transaction timestamp so that every endorser
+// writes the same value.
+func (c *Charge) SetCreatedAt(now time.Time) {
 	c.DocType = "charge"
-	c.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	c.SchemaVersion = CurrentSchemaVersion
+	c.CreatedAt = now.UTC().Format(time.RFC3339)
+}
+
+// TouchUpdatedAt sets UpdatedAt to now, which callers take from the
+// transaction timestamp.
+func (c *Charge) TouchUpdatedAt(now time.Time) {
+	c.UpdatedAt = now.UTC().Format(time.RFC3339)
 }
 
 // CollectionName returns the private data collection name for this charge.
 // Charges are stored in bilateral collections between away and home agency.
 func (c *Charge) CollectionName() string {
-	a, b := c.AwayAgencyID, c.HomeAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	return "charges_" + a + "_" + b
+	return BilateralCollectionName(c.AwayAgencyID, c.HomeAgencyID)
 }
@@ -3,7 +3,9 @@
 package models
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -119,6 +121,11 @@ func TestCharge_Validate_RequiredFields(t *testing.T) {
 			modify:  func(c *Charge) { c.VehicleClass = 0 },
 			wantErr: "vehicleClass must be >= 1",
 		},
+		{
+			name:    "vehicleClass unknown",
+			modify:  func(c *Charge) { c.VehicleClass = 99 },
+			wantErr: "unknown vehicleClass 99",
+		},
 	}
 
 	for _, tt := range tests {
@@ -153,6 +160,11 @@ func TestCharge_Validate_InvalidEnums(t *testing.T) {
 			modify:  func(c *Charge) { c.Protocol = "soap" },
 			wantErr: "invalid protocol",
 		},
+		{
+			name:    "invalid submittedVia",
+			modify:  func(c *Charge) { c.SubmittedVia = "email" },
+			wantErr: `invalid submittedVia "email": must be one of [direct hub]`,
+		},
 		{
 			name:    "invalid status",
 			modify:  func(c *Charge) { c.Status = "cancelled" },
@@ -180,6 +192,22 @@ func TestCharge_Validate_SameAgency(t *testing.T) {
 	assert.Contains(t, err.Error(), "must be different")
 }
 
+func TestCharge_Validate_ReplacesItself(t *testing.T) {
+	c := validCharge()
+	c.ReplacesChargeID = c.ChargeID
+	err := c.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot replace itself")
+
+	c.ReplacesChargeID = "CHG-TEST-000"
+	assert.NoError(t, c.Validate())
+
+	c.SupersededByChargeID = c.ChargeID
+	err = c.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot supersede itself")
+}
+
 func TestCharge_Validate_NegativeAmounts(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -214,6 +242,86 @@ func TestCharge_Validate_NegativeAmounts(t *testing.T) {
 	}
 }
 
+func TestCharge_Validate_FeeBreakdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		fee       float64
+		breakdown *FeeBreakdown
+		wantErr   string
+	}{
+		{name: "no breakdown", fee: 0.05},
+		{name: "flat fee only", fee: 0.05, breakdown: &FeeBreakdown{FlatFee: 0.05}},
+		{name: "flat and percent", fee: 0.15, breakdown: &FeeBreakdown{FlatFee: 0.05, PercentFee: 0.02}},
+		{name: "fee disagrees with breakdown", fee: 0.05, breakdown: &FeeBreakdown{FlatFee: 0.05, PercentFee: 0.02}, wantErr: "fee 0.05 does not match feeBreakdown, which gives 0.15"},
+		{name: "negative flat fee", fee: 0, breakdown: &FeeBreakdown{FlatFee: -0.05}, wantErr: "feeBreakdown.flatFee must be >= 0"},
+		{name: "percent above one", fee: 0, breakdown: &FeeBreakdown{PercentFee: 2}, wantErr: "feeBreakdown.percentFee must be between 0 and 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validCharge()
+			c.Amount = 4.75
+			c.Fee = tt.fee
+			c.FeeBreakdown = tt.breakdown
+			err := c.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCharge_Validate_ReturnsValidationError(t *testing.T) {
+	c := validCharge()
+	c.HomeAgencyID = ""
+	err := c.Validate()
+	require.Error(t, err)
+
+	var ve *ValidationError
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, "homeAgencyID", ve.Field)
+	assert.Equal(t, "homeAgencyID is required", ve.Message)
+}
+
+func TestCharge_ValidateAll(t *testing.T) {
+	t.Run("valid charge has no errors", func(t *testing.T) {
+		c := validCharge()
+		assert.Nil(t, c.ValidateAll())
+	})
+
+	t.Run("collects every failure in order", func(t *testing.T) {
+		c := validCharge()
+		c.ChargeType = "toll_bogus"
+		c.FacilityID = ""
+		c.Amount = -1
+		c.FeeBreakdown = &FeeBreakdown{FlatFee: -0.05}
+		c.TagSerialNumber = ""
+
+		errs := c.ValidateAll()
+		var fields []string
+		for _, e := range errs {
+			fields = append(fields, e.Field)
+		}
+		assert.Equal(t, []string{"chargeType", "facilityID", "amount", "feeBreakdown.flatFee", "tagSerialNumber"}, fields)
+		assert.Equal(t, errs[0].Message, c.Validate().Error(), "Validate returns the first failure")
+	})
+
+	t.Run("skips comparisons between invalid fields", func(t *testing.T) {
+		c := validCharge()
+		c.AwayAgencyID = "ORG_1"
+		c.HomeAgencyID = "ORG_1"
+
+		errs := c.ValidateAll()
+		require.Len(t, errs, 2)
+		assert.Equal(t, "awayAgencyID", errs[0].Field)
+		assert.Equal(t, "homeAgencyID", errs[1].Field)
+		assert.Contains(t, errs[1].Message, "invalid characters")
+	})
+}
+
 func TestCharge_Validate_TagBased_RequiresTagSerial(t *testing.T) {
 	for _, rt := range []string{"TB01", "TC01", "TC02"} {
 		t.Run(rt+"_missing_tag", func(t *testing.T) {
@@ -288,20 +396,74 @@ func TestCharge_ValidateStatusTransition(t *testing.T) {
 	}
 }
 
+func TestCharge_MarkDeleted(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	for _, status := range DeletableChargeStatuses {
+		t.Run(status, func(t *testing.T) {
+			c := validCharge()
+			c.Status = status
+			require.NoError(t, c.MarkDeleted("entered in error", now))
+			assert.True(t, c.Deleted)
+			assert.Equal(t, "entered in error", c.DeletedReason)
+			assert.Equal(t, "2026-03-01T09:30:00Z", c.DeletedAt)
+		})
+	}
+
+	t.Run("rejects posted charge", func(t *testing.T) {
+		c := validCharge()
+		c.Status = "posted"
+		err := c.MarkDeleted("entered in error", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot delete charge in status")
+		assert.False(t, c.Deleted)
+	})
+
+	t.Run("requires reason", func(t *testing.T) {
+		c := validCharge()
+		err := c.MarkDeleted("", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reason is required")
+	})
+
+	t.Run("rejects already deleted", func(t *testing.T) {
+		c := validCharge()
+		require.NoError(t, c.MarkDeleted("entered in error", now))
+		err := c.MarkDeleted("again", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already deleted")
+	})
+
+	t.Run("blocks status transitions", func(t *testing.T) {
+		c := validCharge()
+		require.NoError(t, c.MarkDeleted("entered in error", now))
+		err := c.ValidateStatusTransition("posted")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "charge is deleted")
+	})
+}
+
 func TestCharge_Key(t *testing.T) {
 	c := Charge{ChargeID: "CHG-001"}
 	assert.Equal(t, "CHARGE_CHG-001", c.Key())
+	assert.Equal(t, c.Key(), ChargeKey("CHG-001"))
 }
 
 func TestCharge_SetCreatedAt(t *testing.T) {
 	c := validCharge()
 	assert.Empty(t, c.CreatedAt)
 	assert.Empty(t, c.DocType)
-	c.SetCreatedAt()
-	assert.NotEmpty(t, c.CreatedAt)
+	c.SetCreatedAt(time.Date(2026, 3, 1, 4, 30, 0, 0, time.FixedZone("EST", -5*60*60)))
+	assert.Equal(t, "2026-03-01T09:30:00Z", c.CreatedAt)
 	assert.Equal(t, "charge", c.DocType)
 }
 
+func TestCharge_TouchUpdatedAt(t *testing.T) {
+	c := validCharge()
+	c.TouchUpdatedAt(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	assert.Equal(t, "2026-03-01T09:30:00Z", c.UpdatedAt)
+}
+
 func TestCharge_CollectionName(t *testing.T) {
 	t.Run("alphabetical order A-B", func(t *testing.T) {
 		c := Charge{AwayAgencyID: "ORG2", HomeAgencyID: "ORG1"}
@@ -336,7 +498,7 @@ func TestCharge_Validate_AllRecordTypes(t *testing.T) {
 			c := validCharge()
 			c.RecordType = rt
 			// Video-based record types need plate info instead of tag
-			if contains(videoBasedRecordTypes, rt) {
+			if Contains(videoBasedRecordTypes, rt) {
 				c.TagSerialNumber = ""
 				c.PlateNumber = "7ABC123"
 				c.PlateState = "CA"
@@ -347,11 +509,64 @@ func TestCharge_Validate_AllRecordTypes(t *testing.T) {
 	}
 }
 
+func TestCharge_Validate_ProtocolRecordType(t *testing.T) {
+	tests := []struct {
+		protocol   string
+		recordType string
+		wantErr    bool
+	}{
+		{"niop", "TB01", false},
+		{"niop", "VC02", false},
+		{"iag", "ICTX", false},
+		{"iag", "ICRX", false},
+		{"iag", "TB01", true},
+		{"niop", "ICTX", true},
+		{"native", "TB01", false},
+		{"native", "ICTX", false},
+		{"ctoc", "TC01", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol+"/"+tt.recordType, func(t *testing.T) {
+			c := validCharge()
+			c.Protocol = tt.protocol
+			c.RecordType = tt.recordType
+			if Contains(videoBasedRecordTypes, tt.recordType) {
+				c.PlateNumber = "7ABC123"
+				c.PlateState = "CA"
+				c.PlateCountry = "US"
+			}
+			err := c.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "recordType "+tt.recordType+" is not valid for protocol "+tt.protocol)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCharge_Validate_DiscountPlan(t *testing.T) {
+	c := validCharge()
+	assert.NoError(t, c.Validate(), "discount plan is optional")
+
+	c.DiscountPlan = "commuter"
+	assert.NoError(t, c.Validate())
+
+	c.DiscountPlan = "vip"
+	err := c.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid discountPlanType")
+}
+
 func TestCharge_Validate_AllProtocols(t *testing.T) {
 	for _, p := range ValidChargeProtocols {
 		t.Run(p, func(t *testing.T) {
 			c := validCharge()
 			c.Protocol = p
+			if allowed, ok := ProtocolRecordTypes[p]; ok {
+				c.RecordType = allowed[0]
+			}
 			assert.NoError(t, c.Validate())
 		})
 	}
@@ -364,3 +579,117 @@ func TestCharge_Validate_ZeroAmount(t *testing.T) {
 	c.NetAmount = 0
 	assert.NoError(t, c.Validate())
 }
+
+func TestCharge_ValidatePayByPlate(t *testing.T) {
+	t.Run("other charge types pass", func(t *testing.T) {
+		c := validCharge()
+		assert.NoError(t, c.ValidatePayByPlate())
+	})
+
+	t.Run("tag record type still needs a plate", func(t *testing.T) {
+		c := validCharge()
+		c.ChargeType = "toll_paybyplate"
+		err := c.ValidatePayByPlate()
+		require.Error(t, err)
+		var ve *ValidationError
+		require.True(t, errors.As(err, &ve))
+		assert.Equal(t, "plateNumber", ve.Field)
+
+		c.PlateNumber = "7ABC123"
+		assert.EqualError(t, c.ValidatePayByPlate(), "plateState is required for toll_paybyplate charges")
+
+		c.PlateState = "CA"
+		assert.NoError(t, c.ValidatePayByPlate())
+	})
+}
+
+func TestCharge_ValidateTagClass(t *testing.T) {
+	original := VehicleClassRemaps
+	t.Cleanup(func() { VehicleClassRemaps = original })
+	VehicleClassRemaps = map[int][]int{2: {3}}
+
+	tag := &Tag{TagSerialNumber: "TEST.000000001", TagClass: 2}
+
+	t.Run("matching class", func(t *testing.T) {
+		c := validCharge()
+		c.VehicleClass = 2
+		assert.NoError(t, c.ValidateTagClass(tag))
+	})
+
+	t.Run("allowed remap", func(t *testing.T) {
+		c := validCharge()
+		c.VehicleClass = 3
+		assert.NoError(t, c.ValidateTagClass(tag))
+	})
+
+	t.Run("divergent class", func(t *testing.T) {
+		c := validCharge()
+		c.VehicleClass = 5
+		err := c.ValidateTagClass(tag)
+		require.Error(t, err)
+		var ve *ValidationError
+		require.True(t, errors.As(err, &ve))
+		assert.Equal(t, "vehicleClass", ve.Field)
+		assert.Equal(t, "vehicleClass 5 does not match class 2 of tag TEST.000000001", ve.Message)
+	})
+}
+
+func TestCharge_ValidateTagProtocol(t *testing.T) {
+	original := ProtocolTagProtocols
+	t.Cleanup(func() { ProtocolTagProtocols = original })
+	ProtocolTagProtocols = map[string][]string{"niop": {"sego", "6c", "tdm"}, "iag": {"tdm"}}
+
+	sixC := &Tag{TagSerialNumber: "TEST.000000001", TagProtocol: "6c"}
+	tdm := &Tag{TagSerialNumber: "TEST.000000002", TagProtocol: "tdm"}
+
+	t.Run("compatible", func(t *testing.T) {
+		c := validCharge()
+		assert.NoError(t, c.ValidateTagProtocol(sixC))
+
+		c.Protocol = "iag"
+		c.RecordType = "ICTX"
+		assert.NoError(t, c.ValidateTagProtocol(tdm))
+	})
+
+	t.Run("protocol without a mapping accepts any tag", func(t *testing.T) {
+		c := validCharge()
+		c.Protocol = "ctoc"
+		assert.NoError(t, c.ValidateTagProtocol(tdm))
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		c := validCharge()
+		c.Protocol = "iag"
+		c.RecordType = "ICTX"
+		err := c.ValidateTagProtocol(sixC)
+		require.Error(t, err)
+		var ve *ValidationError
+		require.True(t, errors.As(err, &ve))
+		assert.Equal(t, "tagSerialNumber", ve.Field)
+		assert.Equal(t, "tagProtocol 6c of tag TEST.000000001 is not compatible with protocol iag: must be one of [tdm]", ve.Message)
+	})
+}
+
+func TestSetProtocolTagProtocols(t *testing.T) {
+	original := ProtocolTagProtocols
+	t.Cleanup(func() { ProtocolTagProtocols = original })
+
+	require.NoError(t, SetProtocolTagProtocols(map[string][]string{"iag": {"tdm", "6c"}}))
+	assert.Equal(t, map[string][]string{"iag": {"tdm", "6c"}}, ProtocolTagProtocols)
+
+	err := SetProtocolTagProtocols(map[string][]string{"e-zpass": {"tdm"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid protocol "e-zpass"`)
+
+	err = SetProtocolTagProtocols(map[string][]string{"iag": {"rfid"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `protocol iag: invalid tagProtocol "rfid"`)
+}
+
+func TestCharge_Validate_SubmittedVia(t *testing.T) {
+	for _, via := range []string{"", "direct", "hub"} {
+		c := validCharge()
+		c.SubmittedVia = via
+		assert.NoError(t, c.Validate(), "submittedVia %q", via)
+	}
+}
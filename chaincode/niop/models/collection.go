@@ -0,0 +1,29 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BilateralCollectionName returns the private data collection shared by two
+// agencies, in either order: charges_{A}_{B} with A and B sorted.
+//
+// ValidateAgencyID rejects underscores, but stored or hand-built IDs may
+// predate it. Joining such IDs plainly would let two pairs share a name
+// ("ORG" + "1_2" and "ORG_1" + "2" both give charges_ORG_1_2), so when
+// either ID contains an underscore the first ID is length-prefixed instead:
+// charges_{len(A)}_{A}_{B}. Names for clean IDs have exactly two
+// underscores and length-prefixed names have at least four, so the two
+// forms never collide.
+func BilateralCollectionName(agencyA string, agencyB string) string {
+	a, b := agencyA, agencyB
+	if a > b {
+		a, b = b, a
+	}
+	if strings.Contains(a, "_") || strings.Contains(b, "_") {
+		return "charges_" + strconv.Itoa(len(a)) + "_" + a + "_" + b
+	}
+	return "charges_" + a + "_" + b
+}
@@ -0,0 +1,57 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBilateralCollectionName(t *testing.T) {
+	t.Run("clean IDs keep the plain form", func(t *testing.T) {
+		assert.Equal(t, "charges_ORG1_ORG2", BilateralCollectionName("ORG1", "ORG2"))
+		assert.Equal(t, "charges_ORG1_ORG2", BilateralCollectionName("ORG2", "ORG1"))
+	})
+
+	t.Run("distinct pairs do not collide", func(t *testing.T) {
+		pairs := [][2]string{
+			{"ORG", "1_2"},
+			{"ORG_1", "2"},
+			{"ORG", "ORG_1_2"},
+			{"ORG_1", "ORG_2"},
+			{"ORG", "1"},
+			{"1", "2"},
+			{"1_ORG", "2"},
+		}
+		seen := map[string][2]string{}
+		for _, p := range pairs {
+			name := BilateralCollectionName(p[0], p[1])
+			if prev, ok := seen[name]; ok {
+				t.Fatalf("pairs %v and %v both map to %s", prev, p, name)
+			}
+			seen[name] = p
+		}
+	})
+
+	t.Run("underscored IDs are length-prefixed", func(t *testing.T) {
+		assert.Equal(t, "charges_3_1_2_ORG", BilateralCollectionName("ORG", "1_2"))
+		assert.Equal(t, "charges_1_2_ORG_1", BilateralCollectionName("ORG_1", "2"))
+	})
+}
+
+func TestCollectionName_SameForEveryEntity(t *testing.T) {
+	charge := Charge{AwayAgencyID: "ORG2", HomeAgencyID: "ORG1"}
+	settlement := Settlement{PayorAgencyID: "ORG1", PayeeAgencyID: "ORG2"}
+	correction := Correction{FromAgencyID: "ORG2", ToAgencyID: "ORG1"}
+	dispute := Dispute{RaisedByAgencyID: "ORG1", CounterpartyAgencyID: "ORG2"}
+	schedule := FeeSchedule{AgencyA: "ORG2", AgencyB: "ORG1"}
+
+	want := BilateralCollectionName("ORG1", "ORG2")
+	assert.Equal(t, "charges_ORG1_ORG2", want)
+	assert.Equal(t, want, charge.CollectionName())
+	assert.Equal(t, want, settlement.CollectionName())
+	assert.Equal(t, want, correction.CollectionName())
+	assert.Equal(t, want, dispute.CollectionName())
+	assert.Equal(t, want, schedule.CollectionName())
+}
@@ -4,13 +4,19 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Correction represents an adjustment to a previously submitted charge.
-// Corrections maintain a full audit trail via sequence numbers.
+// Amount is signed and is added to the charge amount: a negative amount
+// reduces what is owed, and a correction of minus the remaining amount
+// reverses the charge. Corrections maintain a full audit trail via sequence
+// numbers, which are one-based: the first correction to a charge is
+// FirstCorrectionSeqNo.
 type Correction struct {
 	DocType          string  `json:"docType"`
+	SchemaVersion    int     `json:"schemaVersion"`
 	CorrectionID     string  `json:"correctionID"`
 	OriginalChargeID string  `json:"originalChargeID"`
 	CorrectionSeqNo  int     `json:"correctionSeqNo"`
@@ -24,6 +30,45 @@ type Correction struct {
 	CreatedAt        string  `json:"createdAt"`
 }
 
+// FirstCorrectionSeqNo is the sequence number of a charge's first correction.
+// Zero passes Validate for imported records but is not part of the
+// contiguous sequence.
+const FirstCorrectionSeqNo = 1
+
+// MissingSeqNos returns the sequence numbers between FirstCorrectionSeqNo and
+// the highest number in seqNos that are not present, in ascending order.
+func MissingSeqNos(seqNos []int) []int {
+	present := make(map[int]bool, len(seqNos))
+	highest := 0
+	for _, n := range seqNos {
+		present[n] = true
+		if n > highest {
+			highest = n
+		}
+	}
+
+	missing := []int{}
+	for n := FirstCorrectionSeqNo; n < highest; n++ {
+		if !present[n] {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+// NextResubmitCount returns the ResubmitCount for a resubmission of a charge
+// given the charge's prior corrections: one more than the number of prior
+// corrections that were themselves resubmissions.
+func NextResubmitCount(prior []*Correction) int {
+	count := 1
+	for _, c := range prior {
+		if c.ResubmitReason != "" {
+			count++
+		}
+	}
+	return count
+}
+
 // Valid correction reason codes.
 var ValidCorrectionReasons = []string{"C", "I", "L", "T", "O"}
 
@@ -39,70 +84,123 @@ var CorrectionReasonDescriptions = map[string]string{
 // Valid resubmit reason codes.
 var ValidResubmitReasons = []string{"R", "S"}
 
-// Valid correction record types (original type with 'A' suffix).
+// Valid correction record types (original type with 'A' suffix), for NIOP and
+// IAG charges alike.
 var ValidCorrectionRecordTypes = []string{
 	"TB01A", "TC01A", "TC02A", "VB01A", "VC01A", "VC02A",
+	"ICTXA", "ICRXA",
+}
+
+// CorrectionRecordTypeFor returns the correction record type for a charge
+// record type of any protocol: the same type with an 'A' suffix, e.g. TB01 ->
+// TB01A or ICTX -> ICTXA.
+func CorrectionRecordTypeFor(chargeRecordType string) (string, error) {
+	if !chargeRecordTypeSet.Has(chargeRecordType) {
+		return "", fmt.Errorf("invalid recordType %q: must be one of %v", chargeRecordType, ChargeRecordTypes())
+	}
+	return chargeRecordType + "A", nil
 }
 
-// Validate checks all fields of a Correction and returns an error describing
-// the first validation failure, or nil if the correction is valid.
+// Validate checks all fields of a Correction and returns a *ValidationError
+// describing the first validation failure, or nil if the correction is valid.
 func (c *Correction) Validate() error {
+	return ValidationErrors(c.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Correction and returns every validation
+// failure in the order Validate checks them, or nil if the correction is
+// valid.
+func (c *Correction) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if c.CorrectionID == "" {
-		return fmt.Errorf("correctionID is required")
+		errs.addf("correctionID", "correctionID is required")
 	}
 	if c.OriginalChargeID == "" {
-		return fmt.Errorf("originalChargeID is required")
+		errs.addf("originalChargeID", "originalChargeID is required")
 	}
 	if c.CorrectionSeqNo < 0 || c.CorrectionSeqNo > 999 {
-		return fmt.Errorf("correctionSeqNo must be between 0 and 999, got %d", c.CorrectionSeqNo)
+		errs.addf("correctionSeqNo", "correctionSeqNo must be between 0 and 999, got %d", c.CorrectionSeqNo)
 	}
 	if c.CorrectionReason == "" {
-		return fmt.Errorf("correctionReason is required")
-	}
-	if !contains(ValidCorrectionReasons, c.CorrectionReason) {
-		return fmt.Errorf("invalid correctionReason %q: must be one of %v", c.CorrectionReason, ValidCorrectionReasons)
+		errs.addf("correctionReason", "correctionReason is required")
+	} else if !correctionReasonSet.Has(c.CorrectionReason) {
+		errs.addf("correctionReason", "invalid correctionReason %q: must be one of %v", c.CorrectionReason, ValidCorrectionReasons)
 	}
-	if c.ResubmitReason != "" && !contains(ValidResubmitReasons, c.ResubmitReason) {
-		return fmt.Errorf("invalid resubmitReason %q: must be one of %v", c.ResubmitReason, ValidResubmitReasons)
+	if c.ResubmitReason != "" && !resubmitReasonSet.Has(c.ResubmitReason) {
+		errs.addf("resubmitReason", "invalid resubmitReason %q: must be one of %v", c.ResubmitReason, ValidResubmitReasons)
 	}
-	if c.FromAgencyID == "" {
-		return fmt.Errorf("fromAgencyID is required")
+	if c.ResubmitCount < 0 {
+		errs.addf("resubmitCount", "resubmitCount must be >= 0, got %d", c.ResubmitCount)
 	}
-	if c.ToAgencyID == "" {
-		return fmt.Errorf("toAgencyID is required")
+	if c.ResubmitCount > 0 && c.ResubmitReason == "" {
+		errs.addf("resubmitReason", "resubmitReason is required when resubmitCount > 0")
 	}
-	if c.FromAgencyID == c.ToAgencyID {
-		return fmt.Errorf("fromAgencyID and toAgencyID must be different")
+	fromErr := ValidateAgencyID("fromAgencyID", c.FromAgencyID)
+	toErr := ValidateAgencyID("toAgencyID", c.ToAgencyID)
+	errs.add(fromErr)
+	errs.add(toErr)
+	if fromErr == nil && toErr == nil && c.FromAgencyID == c.ToAgencyID {
+		errs.addf("toAgencyID", "fromAgencyID and toAgencyID must be different")
 	}
 	if c.RecordType == "" {
-		return fmt.Errorf("recordType is required")
+		errs.addf("recordType", "recordType is required")
+	} else if !correctionRecordTypeSet.Has(c.RecordType) {
+		errs.addf("recordType", "invalid correction recordType %q: must be one of %v (original type with A suffix)", c.RecordType, ValidCorrectionRecordTypes)
 	}
-	if !contains(ValidCorrectionRecordTypes, c.RecordType) {
-		return fmt.Errorf("invalid correction recordType %q: must be one of %v (original type with A suffix)", c.RecordType, ValidCorrectionRecordTypes)
+	if len(errs) == 0 {
+		return nil
 	}
-	if c.Amount < 0 {
-		return fmt.Errorf("amount must be >= 0, got %f", c.Amount)
+	return errs
+}
+
+// ValidateRecordTypeFor checks that the correction's record type belongs to
+// the same family as charge, the charge it corrects: with its 'A' suffix
+// removed it must equal the charge's recordType. It reads another ledger
+// entry and is applied only in strict mode.
+func (c *Correction) ValidateRecordTypeFor(charge *Charge) error {
+	if strings.TrimSuffix(c.RecordType, "A") != charge.RecordType {
+		return fieldError("recordType", "correction recordType does not match charge: %s is not a correction of %s charge %s", c.RecordType, charge.RecordType, charge.ChargeID)
 	}
 	return nil
 }
 
+// AdjustedAmount returns a charge amount with the given corrections applied.
+func AdjustedAmount(chargeAmount float64, corrections []*Correction) float64 {
+	adjusted := chargeAmount
+	for _, c := range corrections {
+		adjusted += c.Amount
+	}
+	return adjusted
+}
+
+// NextCorrectionSeqNo returns the sequence number for a new correction to a
+// charge given its existing corrections: one past the highest in use, or
+// FirstCorrectionSeqNo when there are none.
+func NextCorrectionSeqNo(existing []*Correction) int {
+	next := FirstCorrectionSeqNo
+	for _, c := range existing {
+		if c.CorrectionSeqNo >= next {
+			next = c.CorrectionSeqNo + 1
+		}
+	}
+	return next
+}
+
 // Key returns the ledger key for this correction.
 func (c *Correction) Key() string {
 	return fmt.Sprintf("CORRECTION_%s_%03d", c.OriginalChargeID, c.CorrectionSeqNo)
 }
 
-// SetCreatedAt sets CreatedAt to the current time and ensures DocType is set.
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
 func (c *Correction) SetCreatedAt() {
 	c.DocType = "correction"
+	c.SchemaVersion = CurrentSchemaVersion
 	c.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 }
 
 // CollectionName returns the private data collection name for this correction.
 // Corrections are stored in the same bilateral collection as charges.
 func (c *Correction) CollectionName() string {
-	a, b := c.FromAgencyID, c.ToAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	return "charges_" + a + "_" + b
+	return BilateralCollectionName(c.FromAgencyID, c.ToAgencyID)
 }
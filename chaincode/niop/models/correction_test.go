@@ -172,9 +172,55 @@ func TestCorrection_Validate_SameAgency(t *testing.T) {
 func TestCorrection_Validate_NegativeAmount(t *testing.T) {
 	c := validCorrection()
 	c.Amount = -1.00
-	err := c.Validate()
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "amount must be >= 0")
+	assert.NoError(t, c.Validate(), "negative corrections reduce the charge")
+}
+
+func TestAdjustedAmount(t *testing.T) {
+	corrections := []*Correction{{Amount: -1.25}, {Amount: 0.50}}
+	assert.InDelta(t, 4.00, AdjustedAmount(4.75, corrections), 0.0001)
+	assert.InDelta(t, 4.75, AdjustedAmount(4.75, nil), 0.0001)
+}
+
+func TestNextCorrectionSeqNo(t *testing.T) {
+	assert.Equal(t, FirstCorrectionSeqNo, NextCorrectionSeqNo(nil))
+	assert.Equal(t, FirstCorrectionSeqNo, NextCorrectionSeqNo([]*Correction{{CorrectionSeqNo: 0}}))
+	assert.Equal(t, 6, NextCorrectionSeqNo([]*Correction{{CorrectionSeqNo: 5}, {CorrectionSeqNo: 2}}))
+}
+
+func TestCorrection_Validate_ResubmitCount(t *testing.T) {
+	t.Run("count without reason", func(t *testing.T) {
+		c := validCorrection()
+		c.ResubmitCount = 1
+		err := c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resubmitReason is required when resubmitCount > 0")
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		c := validCorrection()
+		c.ResubmitReason = "R"
+		c.ResubmitCount = -1
+		err := c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resubmitCount must be >= 0")
+	})
+
+	t.Run("count with reason", func(t *testing.T) {
+		c := validCorrection()
+		c.ResubmitReason = "R"
+		c.ResubmitCount = 2
+		assert.NoError(t, c.Validate())
+	})
+}
+
+func TestNextResubmitCount(t *testing.T) {
+	assert.Equal(t, 1, NextResubmitCount(nil))
+	assert.Equal(t, 1, NextResubmitCount([]*Correction{{CorrectionSeqNo: 1}}))
+	assert.Equal(t, 3, NextResubmitCount([]*Correction{
+		{CorrectionSeqNo: 1},
+		{CorrectionSeqNo: 2, ResubmitReason: "R"},
+		{CorrectionSeqNo: 3, ResubmitReason: "S"},
+	}))
 }
 
 func TestCorrection_Key(t *testing.T) {
@@ -249,3 +295,85 @@ func TestCorrection_Validate_AllResubmitReasons(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingSeqNos(t *testing.T) {
+	tests := []struct {
+		name   string
+		seqNos []int
+		want   []int
+	}{
+		{"empty", nil, []int{}},
+		{"contiguous", []int{1, 2, 3}, []int{}},
+		{"unordered contiguous", []int{3, 1, 2}, []int{}},
+		{"single gap", []int{1, 3}, []int{2}},
+		{"missing first", []int{2}, []int{1}},
+		{"zero is ignored", []int{0, 1, 2}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MissingSeqNos(tt.seqNos))
+		})
+	}
+}
+
+func TestCorrectionRecordTypeFor(t *testing.T) {
+	tests := []struct {
+		recordType string
+		want       string
+		wantErr    bool
+	}{
+		{"TB01", "TB01A", false},
+		{"TC01", "TC01A", false},
+		{"TC02", "TC02A", false},
+		{"VB01", "VB01A", false},
+		{"VC01", "VC01A", false},
+		{"VC02", "VC02A", false},
+		{"ICTX", "ICTXA", false},
+		{"ICRX", "ICRXA", false},
+		{"TB01A", "", true},
+		{"XX99", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.recordType, func(t *testing.T) {
+			got, err := CorrectionRecordTypeFor(tt.recordType)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid recordType")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, ValidCorrectionRecordTypes, got)
+		})
+	}
+}
+
+func TestCorrection_ValidateRecordTypeFor(t *testing.T) {
+	charge := &Charge{ChargeID: "CHG-TEST-001", RecordType: "TB01"}
+
+	c := validCorrection()
+	c.RecordType = "TB01A"
+	assert.NoError(t, c.ValidateRecordTypeFor(charge))
+
+	c.RecordType = "VC02A"
+	err := c.ValidateRecordTypeFor(charge)
+	require.Error(t, err)
+	assert.EqualError(t, err, "correction recordType does not match charge: VC02A is not a correction of TB01 charge CHG-TEST-001")
+}
+
+func TestCorrection_ValidateAll(t *testing.T) {
+	c := validCorrection()
+	assert.Nil(t, c.ValidateAll())
+
+	c.CorrectionID = ""
+	c.CorrectionSeqNo = 1000
+	c.RecordType = "TB01"
+	errs := c.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"correctionID", "correctionSeqNo", "recordType"}, fields)
+	assert.EqualError(t, c.Validate(), errs[0].Message)
+}
@@ -0,0 +1,116 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Dispute records an agency's challenge to a charge or settlement: who raised
+// it, why, the supporting evidence, and how it was resolved. Disputes are
+// stored in the bilateral collection of the two agencies involved.
+type Dispute struct {
+	DocType              string   `json:"docType"`
+	SchemaVersion        int      `json:"schemaVersion"`
+	DisputeID            string   `json:"disputeID"`
+	EntityType           string   `json:"entityType"`
+	EntityID             string   `json:"entityID"`
+	RaisedByAgencyID     string   `json:"raisedByAgencyID"`
+	CounterpartyAgencyID string   `json:"counterpartyAgencyID"`
+	Reason               string   `json:"reason"`
+	EvidenceRefs         []string `json:"evidenceRefs,omitempty"`
+	Status               string   `json:"status"`
+	Resolution           string   `json:"resolution,omitempty"`
+	CreatedAt            string   `json:"createdAt"`
+	ResolvedAt           string   `json:"resolvedAt,omitempty"`
+}
+
+// Valid disputed entity types.
+var ValidDisputeEntityTypes = []string{"charge", "settlement"}
+
+// Valid dispute statuses.
+var ValidDisputeStatuses = []string{"open", "resolved"}
+
+// Validate checks all fields of a Dispute and returns a *ValidationError
+// describing the first validation failure, or nil if valid.
+func (d *Dispute) Validate() error {
+	return ValidationErrors(d.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Dispute and returns every validation
+// failure in the order Validate checks them, or nil if valid.
+func (d *Dispute) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	if d.DisputeID == "" {
+		errs.addf("disputeID", "disputeID is required")
+	}
+	if d.EntityType == "" {
+		errs.addf("entityType", "entityType is required")
+	} else if !disputeEntityTypeSet.Has(d.EntityType) {
+		errs.addf("entityType", "invalid entityType %q: must be one of %v", d.EntityType, ValidDisputeEntityTypes)
+	}
+	if d.EntityID == "" {
+		errs.addf("entityID", "entityID is required")
+	}
+	raisedByErr := ValidateAgencyID("raisedByAgencyID", d.RaisedByAgencyID)
+	counterpartyErr := ValidateAgencyID("counterpartyAgencyID", d.CounterpartyAgencyID)
+	errs.add(raisedByErr)
+	errs.add(counterpartyErr)
+	if raisedByErr == nil && counterpartyErr == nil && d.RaisedByAgencyID == d.CounterpartyAgencyID {
+		errs.addf("counterpartyAgencyID", "raisedByAgencyID and counterpartyAgencyID must be different")
+	}
+	if d.Reason == "" {
+		errs.addf("reason", "reason is required")
+	}
+	for i, ref := range d.EvidenceRefs {
+		if ref == "" {
+			errs.addf(fmt.Sprintf("evidenceRefs[%d]", i), "evidenceRefs[%d] must not be empty", i)
+		}
+	}
+	if d.Status == "" {
+		errs.addf("status", "status is required")
+	} else if !disputeStatusSet.Has(d.Status) {
+		errs.addf("status", "invalid status %q: must be one of %v", d.Status, ValidDisputeStatuses)
+	}
+	if d.Status == "resolved" && d.Resolution == "" {
+		errs.addf("resolution", "resolution is required when status is resolved")
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Resolve closes an open dispute with the given resolution.
+func (d *Dispute) Resolve(resolution string) error {
+	if d.Status != "open" {
+		return fmt.Errorf("dispute %s is not open", d.DisputeID)
+	}
+	if resolution == "" {
+		return fmt.Errorf("resolution is required")
+	}
+	d.Status = "resolved"
+	d.Resolution = resolution
+	d.ResolvedAt = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+// Key returns the ledger key for this dispute.
+func (d *Dispute) Key() string {
+	return "DISPUTE_" + d.DisputeID
+}
+
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
+func (d *Dispute) SetCreatedAt() {
+	d.DocType = "dispute"
+	d.SchemaVersion = CurrentSchemaVersion
+	d.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// CollectionName returns the private data collection name for this dispute.
+// Disputes are stored in the same bilateral collection as the disputed entity.
+func (d *Dispute) CollectionName() string {
+	return BilateralCollectionName(d.RaisedByAgencyID, d.CounterpartyAgencyID)
+}
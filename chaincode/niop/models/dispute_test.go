@@ -0,0 +1,161 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validDispute() Dispute {
+	return Dispute{
+		DisputeID:            "DSP-TEST-001",
+		EntityType:           "charge",
+		EntityID:             "CHG-TEST-001",
+		RaisedByAgencyID:     "ORG1",
+		CounterpartyAgencyID: "ORG2",
+		Reason:               "Vehicle class does not match tag record",
+		EvidenceRefs:         []string{"IMG-2026-0001"},
+		Status:               "open",
+	}
+}
+
+func TestDispute_Validate(t *testing.T) {
+	t.Run("valid dispute passes validation", func(t *testing.T) {
+		d := validDispute()
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("valid settlement dispute without evidence", func(t *testing.T) {
+		d := validDispute()
+		d.EntityType = "settlement"
+		d.EntityID = "SETTLE-TEST-001"
+		d.EvidenceRefs = nil
+		assert.NoError(t, d.Validate())
+	})
+}
+
+func TestDispute_Validate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Dispute)
+		wantErr string
+	}{
+		{
+			name:    "missing disputeID",
+			modify:  func(d *Dispute) { d.DisputeID = "" },
+			wantErr: "disputeID is required",
+		},
+		{
+			name:    "invalid entityType",
+			modify:  func(d *Dispute) { d.EntityType = "tag" },
+			wantErr: "invalid entityType",
+		},
+		{
+			name:    "missing entityID",
+			modify:  func(d *Dispute) { d.EntityID = "" },
+			wantErr: "entityID is required",
+		},
+		{
+			name:    "missing raisedByAgencyID",
+			modify:  func(d *Dispute) { d.RaisedByAgencyID = "" },
+			wantErr: "raisedByAgencyID is required",
+		},
+		{
+			name:    "same agencies",
+			modify:  func(d *Dispute) { d.CounterpartyAgencyID = "ORG1" },
+			wantErr: "must be different",
+		},
+		{
+			name:    "missing reason",
+			modify:  func(d *Dispute) { d.Reason = "" },
+			wantErr: "reason is required",
+		},
+		{
+			name:    "empty evidence reference",
+			modify:  func(d *Dispute) { d.EvidenceRefs = []string{""} },
+			wantErr: "evidenceRefs[0] must not be empty",
+		},
+		{
+			name:    "invalid status",
+			modify:  func(d *Dispute) { d.Status = "closed" },
+			wantErr: "invalid status",
+		},
+		{
+			name:    "resolved without resolution",
+			modify:  func(d *Dispute) { d.Status = "resolved" },
+			wantErr: "resolution is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := validDispute()
+			tt.modify(&d)
+			err := d.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDispute_Resolve(t *testing.T) {
+	t.Run("resolves open dispute", func(t *testing.T) {
+		d := validDispute()
+		require.NoError(t, d.Resolve("Class corrected by correction 001"))
+		assert.Equal(t, "resolved", d.Status)
+		assert.Equal(t, "Class corrected by correction 001", d.Resolution)
+		assert.NotEmpty(t, d.ResolvedAt)
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("rejects resolving twice", func(t *testing.T) {
+		d := validDispute()
+		require.NoError(t, d.Resolve("done"))
+		err := d.Resolve("again")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not open")
+	})
+
+	t.Run("requires a resolution", func(t *testing.T) {
+		d := validDispute()
+		err := d.Resolve("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resolution is required")
+	})
+}
+
+func TestDispute_KeyAndCollection(t *testing.T) {
+	d := validDispute()
+	assert.Equal(t, "DISPUTE_DSP-TEST-001", d.Key())
+	assert.Equal(t, "charges_ORG1_ORG2", d.CollectionName())
+
+	d.RaisedByAgencyID, d.CounterpartyAgencyID = "ORG2", "ORG1"
+	assert.Equal(t, "charges_ORG1_ORG2", d.CollectionName())
+}
+
+func TestDispute_SetCreatedAt(t *testing.T) {
+	d := validDispute()
+	d.SetCreatedAt()
+	assert.NotEmpty(t, d.CreatedAt)
+	assert.Equal(t, "dispute", d.DocType)
+	assert.Equal(t, CurrentSchemaVersion, d.SchemaVersion)
+}
+
+func TestDispute_ValidateAll(t *testing.T) {
+	d := validDispute()
+	assert.Nil(t, d.ValidateAll())
+
+	d.EntityType = "tag"
+	d.Reason = ""
+	d.EvidenceRefs = []string{"DOC-1", ""}
+	errs := d.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"entityType", "reason", "evidenceRefs[1]"}, fields)
+	assert.EqualError(t, d.Validate(), errs[0].Message)
+}
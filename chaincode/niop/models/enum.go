@@ -0,0 +1,66 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+// StringSet is a set of strings for constant-time membership checks, such as
+// validating a field against a list of allowed values.
+type StringSet map[string]struct{}
+
+// NewStringSet returns a set holding the given values.
+func NewStringSet(values ...string) StringSet {
+	set := make(StringSet, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Has reports whether value is in the set.
+func (s StringSet) Has(value string) bool {
+	_, ok := s[value]
+	return ok
+}
+
+// Contains reports whether item is in slice. Use it for short lists built at
+// run time; package-level enums are checked with a StringSet.
+func Contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup sets for the enum lists checked during validation, built once from
+// the exported lists. The lists are fixed, so the sets never go stale.
+var (
+	submissionTypeSet        = NewStringSet(ValidSubmissionTypes...)
+	returnCodeSet            = NewStringSet(ValidReturnCodes...)
+	roleSet                  = NewStringSet(ValidRoles...)
+	connectivityModeSet      = NewStringSet(ValidConnectivityModes...)
+	agencyStatusSet          = NewStringSet(ValidAgencyStatuses...)
+	consortiumSet            = NewStringSet(ValidConsortiums...)
+	capabilitySet            = NewStringSet(ValidCapabilities...)
+	protocolSet              = NewStringSet(ValidProtocols...)
+	chargeTypeSet            = NewStringSet(ValidChargeTypes...)
+	recordTypeSet            = NewStringSet(ValidRecordTypes...)
+	chargeRecordTypeSet      = NewStringSet(ChargeRecordTypes()...)
+	chargeProtocolSet        = NewStringSet(ValidChargeProtocols...)
+	submissionChannelSet     = NewStringSet(ValidSubmissionChannels...)
+	chargeStatusSet          = NewStringSet(ValidChargeStatuses...)
+	deletableChargeStatusSet = NewStringSet(DeletableChargeStatuses...)
+	tagBasedRecordTypeSet    = NewStringSet(tagBasedRecordTypes...)
+	videoBasedRecordTypeSet  = NewStringSet(videoBasedRecordTypes...)
+	correctionReasonSet      = NewStringSet(ValidCorrectionReasons...)
+	resubmitReasonSet        = NewStringSet(ValidResubmitReasons...)
+	correctionRecordTypeSet  = NewStringSet(ValidCorrectionRecordTypes...)
+	disputeEntityTypeSet     = NewStringSet(ValidDisputeEntityTypes...)
+	disputeStatusSet         = NewStringSet(ValidDisputeStatuses...)
+	postingDispositionSet    = NewStringSet(ValidPostingDispositions...)
+	settlementStatusSet      = NewStringSet(ValidSettlementStatuses...)
+	discountPlanTypeSet      = NewStringSet(ValidDiscountPlanTypes...)
+	tagStatusSet             = NewStringSet(ValidTagStatuses...)
+	tagTypeSet               = NewStringSet(ValidTagTypes...)
+	tagProtocolSet           = NewStringSet(ValidTagProtocols...)
+)
@@ -0,0 +1,88 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringSet(t *testing.T) {
+	set := NewStringSet("a", "b", "a")
+	assert.Len(t, set, 2)
+	assert.True(t, set.Has("a"))
+	assert.True(t, set.Has("b"))
+	assert.False(t, set.Has("c"))
+	assert.False(t, set.Has(""))
+	assert.False(t, NewStringSet().Has("a"))
+}
+
+func TestContains(t *testing.T) {
+	assert.True(t, Contains([]string{"a", "b"}, "b"))
+	assert.False(t, Contains([]string{"a", "b"}, "c"))
+	assert.False(t, Contains(nil, ""))
+}
+
+// TestEnumSetsMatchLists checks that each lookup set accepts exactly the
+// values of the list it was built from, so validation is unchanged.
+func TestEnumSetsMatchLists(t *testing.T) {
+	tests := []struct {
+		name string
+		set  StringSet
+		list []string
+	}{
+		{"submission types", submissionTypeSet, ValidSubmissionTypes},
+		{"return codes", returnCodeSet, ValidReturnCodes},
+		{"roles", roleSet, ValidRoles},
+		{"connectivity modes", connectivityModeSet, ValidConnectivityModes},
+		{"agency statuses", agencyStatusSet, ValidAgencyStatuses},
+		{"consortiums", consortiumSet, ValidConsortiums},
+		{"capabilities", capabilitySet, ValidCapabilities},
+		{"protocols", protocolSet, ValidProtocols},
+		{"charge types", chargeTypeSet, ValidChargeTypes},
+		{"record types", recordTypeSet, ValidRecordTypes},
+		{"charge record types", chargeRecordTypeSet, ChargeRecordTypes()},
+		{"charge protocols", chargeProtocolSet, ValidChargeProtocols},
+		{"submission channels", submissionChannelSet, ValidSubmissionChannels},
+		{"charge statuses", chargeStatusSet, ValidChargeStatuses},
+		{"deletable charge statuses", deletableChargeStatusSet, DeletableChargeStatuses},
+		{"tag-based record types", tagBasedRecordTypeSet, tagBasedRecordTypes},
+		{"video-based record types", videoBasedRecordTypeSet, videoBasedRecordTypes},
+		{"correction reasons", correctionReasonSet, ValidCorrectionReasons},
+		{"resubmit reasons", resubmitReasonSet, ValidResubmitReasons},
+		{"correction record types", correctionRecordTypeSet, ValidCorrectionRecordTypes},
+		{"dispute entity types", disputeEntityTypeSet, ValidDisputeEntityTypes},
+		{"dispute statuses", disputeStatusSet, ValidDisputeStatuses},
+		{"posting dispositions", postingDispositionSet, ValidPostingDispositions},
+		{"settlement statuses", settlementStatusSet, ValidSettlementStatuses},
+		{"discount plan types", discountPlanTypeSet, ValidDiscountPlanTypes},
+		{"tag statuses", tagStatusSet, ValidTagStatuses},
+		{"tag types", tagTypeSet, ValidTagTypes},
+		{"tag protocols", tagProtocolSet, ValidTagProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.set, len(tt.list))
+			for _, v := range tt.list {
+				assert.True(t, tt.set.Has(v), v)
+			}
+		})
+	}
+}
+
+// The benchmarks compare checking the last charge record type, the worst case
+// for a linear scan, against the lookup set.
+
+func BenchmarkEnumCheck_Contains(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Contains(ChargeRecordTypes(), "ICRX")
+	}
+}
+
+func BenchmarkEnumCheck_StringSet(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		chargeRecordTypeSet.Has("ICRX")
+	}
+}
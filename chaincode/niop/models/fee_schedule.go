@@ -0,0 +1,117 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// FeeSchedule is the fee agreed between two agencies for one charge type.
+// The fee on a charge is FlatFee plus PercentFee of the charge amount, where
+// PercentFee is a fraction (0.02 is 2%). Fee schedules are stored in the
+// bilateral collection of the two agencies.
+type FeeSchedule struct {
+	DocType       string  `json:"docType"`
+	SchemaVersion int     `json:"schemaVersion"`
+	AgencyA       string  `json:"agencyA"`
+	AgencyB       string  `json:"agencyB"`
+	ChargeType    string  `json:"chargeType"`
+	FlatFee       float64 `json:"flatFee"`
+	PercentFee    float64 `json:"percentFee"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+// Validate checks all fields of a FeeSchedule and returns a
+// *ValidationError describing the first validation failure, or nil if valid.
+func (f *FeeSchedule) Validate() error {
+	return ValidationErrors(f.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a FeeSchedule and returns every
+// validation failure in the order Validate checks them, or nil if valid.
+func (f *FeeSchedule) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	aErr := ValidateAgencyID("agencyA", f.AgencyA)
+	bErr := ValidateAgencyID("agencyB", f.AgencyB)
+	errs.add(aErr)
+	errs.add(bErr)
+	if aErr == nil && bErr == nil && f.AgencyA == f.AgencyB {
+		errs.addf("agencyB", "agencyA and agencyB must be different")
+	}
+	if f.ChargeType == "" {
+		errs.addf("chargeType", "chargeType is required")
+	} else if !chargeTypeSet.Has(f.ChargeType) {
+		errs.addf("chargeType", "invalid chargeType %q: must be one of %v", f.ChargeType, ValidChargeTypes)
+	}
+	if f.FlatFee < 0 {
+		errs.addf("flatFee", "flatFee must be >= 0, got %f", f.FlatFee)
+	}
+	if f.PercentFee < 0 || f.PercentFee > 1 {
+		errs.addf("percentFee", "percentFee must be between 0 and 1, got %f", f.PercentFee)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Key returns the ledger key for this fee schedule within its collection.
+func (f *FeeSchedule) Key() string {
+	return "FEESCHEDULE_" + f.ChargeType
+}
+
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
+func (f *FeeSchedule) SetCreatedAt() {
+	f.DocType = "feeschedule"
+	f.SchemaVersion = CurrentSchemaVersion
+	f.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// CollectionName returns the private data collection name for this fee schedule.
+func (f *FeeSchedule) CollectionName() string {
+	return BilateralCollectionName(f.AgencyA, f.AgencyB)
+}
+
+// FeeBreakdown records how a charge's fee was made up: FlatFee plus
+// PercentFee (a fraction, as in FeeSchedule) of the charge amount.
+type FeeBreakdown struct {
+	FlatFee    float64 `json:"flatFee"`
+	PercentFee float64 `json:"percentFee"`
+}
+
+// Validate checks the breakdown's own fields and returns a *ValidationError
+// describing the first failure, or nil if valid.
+func (b *FeeBreakdown) Validate() error {
+	return ValidationErrors(b.ValidateAll()).first()
+}
+
+// ValidateAll checks the breakdown's own fields and returns every failure,
+// or nil if valid.
+func (b *FeeBreakdown) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	if b.FlatFee < 0 {
+		errs.addf("feeBreakdown.flatFee", "feeBreakdown.flatFee must be >= 0, got %f", b.FlatFee)
+	}
+	if b.PercentFee < 0 || b.PercentFee > 1 {
+		errs.addf("feeBreakdown.percentFee", "feeBreakdown.percentFee must be between 0 and 1, got %f", b.PercentFee)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// EffectiveFee returns flatFee plus percentFee (a fraction) of amount,
+// rounded to the cent.
+func EffectiveFee(amount float64, flatFee float64, percentFee float64) float64 {
+	return math.Round((flatFee+amount*percentFee)*100) / 100
+}
+
+// ComputeFee returns the fee and net amount the schedule gives for a charge.
+func ComputeFee(charge *Charge, schedule *FeeSchedule) (fee float64, netAmount float64) {
+	fee = EffectiveFee(charge.Amount, schedule.FlatFee, schedule.PercentFee)
+	netAmount = math.Round((charge.Amount-fee)*100) / 100
+	return fee, netAmount
+}
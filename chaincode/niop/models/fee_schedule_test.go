@@ -0,0 +1,114 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		AgencyA:    "ORG1",
+		AgencyB:    "ORG2",
+		ChargeType: "toll_tag",
+		FlatFee:    0.05,
+	}
+}
+
+func TestFeeSchedule_Validate(t *testing.T) {
+	f := validFeeSchedule()
+	assert.NoError(t, f.Validate())
+}
+
+func TestFeeSchedule_Validate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*FeeSchedule)
+		wantErr string
+	}{
+		{
+			name:    "missing agencyA",
+			modify:  func(f *FeeSchedule) { f.AgencyA = "" },
+			wantErr: "agencyA is required",
+		},
+		{
+			name:    "same agencies",
+			modify:  func(f *FeeSchedule) { f.AgencyB = "ORG1" },
+			wantErr: "must be different",
+		},
+		{
+			name:    "invalid chargeType",
+			modify:  func(f *FeeSchedule) { f.ChargeType = "bogus" },
+			wantErr: "invalid chargeType",
+		},
+		{
+			name:    "negative flatFee",
+			modify:  func(f *FeeSchedule) { f.FlatFee = -0.01 },
+			wantErr: "flatFee must be >= 0",
+		},
+		{
+			name:    "percentFee above one",
+			modify:  func(f *FeeSchedule) { f.PercentFee = 2 },
+			wantErr: "percentFee must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := validFeeSchedule()
+			tt.modify(&f)
+			err := f.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestFeeSchedule_CollectionName(t *testing.T) {
+	f := validFeeSchedule()
+	f.AgencyA, f.AgencyB = "ORG2", "ORG1"
+	assert.Equal(t, "charges_ORG1_ORG2", f.CollectionName())
+}
+
+func TestComputeFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		flat    float64
+		percent float64
+		wantFee float64
+		wantNet float64
+	}{
+		{"flat", 0.05, 0, 0.05, 4.70},
+		{"percentage", 0, 0.02, 0.10, 4.65},
+		{"flat and percentage", 0.05, 0.02, 0.15, 4.60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge := validCharge()
+			schedule := FeeSchedule{FlatFee: tt.flat, PercentFee: tt.percent}
+			fee, net := ComputeFee(&charge, &schedule)
+			assert.InDelta(t, tt.wantFee, fee, 0.0001)
+			assert.InDelta(t, tt.wantNet, net, 0.0001)
+		})
+	}
+}
+
+func TestFeeSchedule_ValidateAll(t *testing.T) {
+	f := validFeeSchedule()
+	assert.Nil(t, f.ValidateAll())
+
+	f.ChargeType = ""
+	f.FlatFee = -1
+	f.PercentFee = 2
+	errs := f.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"chargeType", "flatFee", "percentFee"}, fields)
+	assert.EqualError(t, f.Validate(), errs[0].Message)
+}
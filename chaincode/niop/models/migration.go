@@ -0,0 +1,112 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CurrentSchemaVersion is the document schema version stamped on every entity
+// written by this chaincode. Documents stored without a schemaVersion field
+// predate versioning and are treated as version 1.
+//
+// Version history:
+//   - 1: original documents (no schemaVersion, docType may be missing)
+//   - 2: schemaVersion stamped, docType always set, charge netAmount always present
+const CurrentSchemaVersion = 2
+
+// migration upgrades a decoded document by exactly one schema version.
+type migration func(docType string, doc map[string]interface{}) error
+
+// migrations maps a source schema version to the function that upgrades a
+// document from that version to the next one.
+var migrations = map[int]migration{
+	1: migrateV1ToV2,
+}
+
+// keyPrefixDocTypes maps ledger key prefixes to the docType they hold.
+var keyPrefixDocTypes = []struct {
+	prefix  string
+	docType string
+}{
+	{"AGENCY_", "agency"},
+	{"TAG_", "tag"},
+	{"CHARGE_", "charge"},
+	{"CORRECTION_", "correction"},
+	{"SETTLEMENT_", "settlement"},
+	{"RECONBATCH_", "reconciliationbatch"},
+	{"RECONCORR_", "reconciliation"},
+	{"RECON_", "reconciliation"},
+	{"ACK_", "acknowledgement"},
+	{"DISPUTE_", "dispute"},
+	{"FEESCHEDULE_", "feeschedule"},
+	{"SUBMISSIONSEQ_", "submissionsequence"},
+}
+
+// DocTypeForKey returns the docType stored under a ledger key, based on its
+// prefix, or an empty string if the key is not an entity key.
+func DocTypeForKey(key string) string {
+	for _, p := range keyPrefixDocTypes {
+		if strings.HasPrefix(key, p.prefix) {
+			return p.docType
+		}
+	}
+	return ""
+}
+
+// MigrateDocument upgrades the stored JSON of a document of the given docType
+// to CurrentSchemaVersion. It returns the upgraded JSON and whether any
+// migration was applied. Documents already at the current version are
+// returned unchanged.
+func MigrateDocument(docType string, data []byte) ([]byte, bool, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse %s document: %w", docType, err)
+	}
+
+	version := 1
+	if v, ok := doc["schemaVersion"].(float64); ok && v > 0 {
+		version = int(v)
+	}
+	if version > CurrentSchemaVersion {
+		return nil, false, fmt.Errorf("%s document has schemaVersion %d, newer than supported version %d", docType, version, CurrentSchemaVersion)
+	}
+	if version == CurrentSchemaVersion {
+		return data, false, nil
+	}
+
+	for ; version < CurrentSchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, false, fmt.Errorf("no migration registered from schemaVersion %d", version)
+		}
+		if err := migrate(docType, doc); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate %s document from schemaVersion %d: %w", docType, version, err)
+		}
+	}
+	doc["schemaVersion"] = CurrentSchemaVersion
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal migrated %s document: %w", docType, err)
+	}
+	return upgraded, true, nil
+}
+
+// migrateV1ToV2 fills the defaults introduced in schema version 2.
+func migrateV1ToV2(docType string, doc map[string]interface{}) error {
+	if dt, _ := doc["docType"].(string); dt == "" {
+		doc["docType"] = docType
+	}
+
+	if docType == "charge" {
+		if _, ok := doc["netAmount"]; !ok {
+			amount, _ := doc["amount"].(float64)
+			fee, _ := doc["fee"].(float64)
+			doc["netAmount"] = amount - fee
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v1ChargeJSON is a charge as written before schema versioning: no
+// schemaVersion, no docType, and no netAmount.
+const v1ChargeJSON = `{
+	"chargeID": "CHG-V1-001",
+	"chargeType": "toll_tag",
+	"recordType": "TB01",
+	"protocol": "niop",
+	"awayAgencyID": "ORG2",
+	"homeAgencyID": "ORG1",
+	"tagSerialNumber": "TEST.000000001",
+	"facilityID": "SR73",
+	"exitDateTime": "2026-01-15T08:30:00Z",
+	"vehicleClass": 2,
+	"amount": 4.75,
+	"fee": 0.05,
+	"status": "pending"
+}`
+
+func TestMigrateDocument_ChargeV1ToV2(t *testing.T) {
+	upgraded, changed, err := MigrateDocument("charge", []byte(v1ChargeJSON))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	var c Charge
+	require.NoError(t, json.Unmarshal(upgraded, &c))
+	assert.Equal(t, CurrentSchemaVersion, c.SchemaVersion)
+	assert.Equal(t, "charge", c.DocType)
+	assert.InDelta(t, 4.70, c.NetAmount, 0.0001)
+	assert.Equal(t, "CHG-V1-001", c.ChargeID)
+	assert.NoError(t, c.Validate())
+}
+
+func TestMigrateDocument_PreservesExistingNetAmount(t *testing.T) {
+	doc := `{"chargeID":"CHG-V1-002","amount":4.75,"fee":0.05,"netAmount":4.00}`
+
+	upgraded, changed, err := MigrateDocument("charge", []byte(doc))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	var c Charge
+	require.NoError(t, json.Unmarshal(upgraded, &c))
+	assert.InDelta(t, 4.00, c.NetAmount, 0.0001)
+}
+
+func TestMigrateDocument_CurrentVersionUnchanged(t *testing.T) {
+	c := validCharge()
+	c.SetCreatedAt(time.Now())
+	original, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	upgraded, changed, err := MigrateDocument("charge", original)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, original, upgraded)
+}
+
+func TestMigrateDocument_RejectsNewerVersion(t *testing.T) {
+	_, _, err := MigrateDocument("charge", []byte(`{"schemaVersion":99}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer than supported")
+}
+
+func TestMigrateDocument_RejectsInvalidJSON(t *testing.T) {
+	_, _, err := MigrateDocument("charge", []byte("not json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse")
+}
+
+func TestDocTypeForKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"AGENCY_ORG1", "agency"},
+		{"TAG_TEST.000000001", "tag"},
+		{"CHARGE_CHG-001", "charge"},
+		{"CORRECTION_CHG-001_001", "correction"},
+		{"SETTLEMENT_SETTLE-001", "settlement"},
+		{"RECON_CHG-001", "reconciliation"},
+		{"RECONCORR_CHG-001_001", "reconciliation"},
+		{"RECONBATCH_SRECON-001", "reconciliationbatch"},
+		{"ACK_ACK-001", "acknowledgement"},
+		{"DISPUTE_DSP-001", "dispute"},
+		{"FEESCHEDULE_toll_tag", "feeschedule"},
+		{"UNKNOWN_KEY", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, DocTypeForKey(tt.key))
+		})
+	}
+}
+
+func TestSetCreatedAt_StampsSchemaVersion(t *testing.T) {
+	c := validCharge()
+	c.SetCreatedAt(time.Now())
+	assert.Equal(t, CurrentSchemaVersion, c.SchemaVersion)
+
+	a := Agency{}
+	a.SetTimestamps()
+	assert.Equal(t, CurrentSchemaVersion, a.SchemaVersion)
+
+	tag := Tag{}
+	tag.TouchUpdatedAt()
+	assert.Equal(t, CurrentSchemaVersion, tag.SchemaVersion)
+}
@@ -0,0 +1,119 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in whole cents. It marshals to JSON as a fixed
+// two-decimal string such as "4.70", so amounts never pick up float noise
+// like 4.7000000000001, and sums of Money are exact.
+//
+// Money is opt-in for new fields; existing amounts stay float64. Note that
+// contractapi checks transaction results against a schema derived from Go
+// kinds, which describes Money as an integer, so a Money field must not
+// appear on a type a transaction returns until its schema is overridden.
+type Money int64
+
+// MoneyFromFloat converts a float amount to Money, rounding to the nearest
+// cent.
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// ParseMoney parses a decimal string with at most two decimal places, such
+// as "4.70", "-12" or "0.5".
+func ParseMoney(s string) (Money, error) {
+	text := s
+	negative := strings.HasPrefix(text, "-")
+	if negative {
+		text = text[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(text, ".")
+	if whole == "" || (hasFrac && (frac == "" || len(frac) > 2)) {
+		return 0, fmt.Errorf("invalid amount %q: expected a decimal with at most two decimal places", s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	cents, err := strconv.ParseUint(frac, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// Cents returns the amount in cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount in dollars, for use with float64 fields.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount with two decimal places, e.g. "4.70".
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+// Mul returns m multiplied by factor, rounded to the nearest cent.
+func (m Money) Mul(factor float64) Money {
+	return Money(math.Round(float64(m) * factor))
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes a decimal string produced by MarshalJSON. A JSON
+// null leaves the amount unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("amount must be a decimal string: %w", err)
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
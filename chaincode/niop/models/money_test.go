@@ -0,0 +1,85 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "4.70", Money(470).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "0.00", Money(0).String())
+	assert.Equal(t, "-0.50", Money(-50).String())
+	assert.Equal(t, "14850.00", Money(1485000).String())
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"4.70", 470},
+		{"4.7", 470},
+		{"4", 400},
+		{"0.05", 5},
+		{"-0.50", -50},
+		{"14850.00", 1485000},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "-", ".5", "4.", "4.701", "1e3", "+4.70", "4,70", "abc", "99999999999999999999.00"} {
+		_, err := ParseMoney(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	type payment struct {
+		Amount Money `json:"amount"`
+	}
+
+	bytes, err := json.Marshal(payment{Amount: MoneyFromFloat(4.7)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"4.70"}`, string(bytes))
+
+	var decoded payment
+	require.NoError(t, json.Unmarshal(bytes, &decoded))
+	assert.Equal(t, Money(470), decoded.Amount)
+
+	for _, in := range []string{`{"amount":4.70}`, `{"amount":"4.701"}`} {
+		var p payment
+		assert.Error(t, json.Unmarshal([]byte(in), &p), in)
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MoneyFromFloat(4.75)
+	b := MoneyFromFloat(0.05)
+	assert.Equal(t, "4.80", a.Add(b).String())
+	assert.Equal(t, "4.70", a.Sub(b).String())
+	assert.Equal(t, "-4.70", b.Sub(a).String())
+	assert.Equal(t, "14.25", a.Mul(3).String())
+	assert.Equal(t, "0.12", a.Mul(0.025).String(), "rounded to the nearest cent")
+	assert.Equal(t, 4.75, a.Float64())
+	assert.Equal(t, int64(475), a.Cents())
+}
+
+func TestMoney_SumHasNoDrift(t *testing.T) {
+	var sum Money
+	var floatSum float64
+	for i := 0; i < 1000; i++ {
+		sum = sum.Add(MoneyFromFloat(0.10))
+		floatSum += 0.10
+	}
+	assert.Equal(t, "100.00", sum.String())
+	assert.NotEqual(t, 100.0, floatSum, "float64 accumulates error over the same sum")
+}
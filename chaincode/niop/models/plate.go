@@ -0,0 +1,61 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import "strings"
+
+// PlateJurisdictions maps a plate country code to the state or province codes
+// that issue plates in that country.
+var PlateJurisdictions = map[string][]string{
+	"US": {
+		"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "FL", "GA",
+		"HI", "ID", "IL", "IN", "IA", "KS", "KY", "LA", "ME", "MD",
+		"MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ",
+		"NM", "NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC",
+		"SD", "TN", "TX", "UT", "VT", "VA", "WA", "WV", "WI", "WY",
+		"DC", "PR", "VI", "GU", "AS", "MP",
+		"GV", // U.S. government vehicles
+	},
+	"CA": {
+		"AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE",
+		"QC", "SK", "YT",
+	},
+	"MX": {
+		"AGU", "BCN", "BCS", "CAM", "CHP", "CHH", "CMX", "COA", "COL", "DUR",
+		"GUA", "GRO", "HID", "JAL", "MEX", "MIC", "MOR", "NAY", "NLE", "OAX",
+		"PUE", "QUE", "ROO", "SLP", "SIN", "SON", "TAB", "TAM", "TLA", "VER",
+		"YUC", "ZAC",
+	},
+}
+
+// AllowUnknownPlateCountries accepts plate countries that have no entry in
+// PlateJurisdictions without checking the state. When false, such countries
+// are rejected. The chaincode sets it from NIOP_ALLOW_UNKNOWN_PLATE_COUNTRIES
+// at startup.
+var AllowUnknownPlateCountries = true
+
+// ValidatePlateJurisdiction checks that plateState is issued by plateCountry.
+// Either value being empty skips the check; required-field rules live with
+// the record types that need plates.
+func ValidatePlateJurisdiction(plateCountry string, plateState string) error {
+	if plateCountry == "" || plateState == "" {
+		return nil
+	}
+	states, ok := PlateJurisdictions[plateCountry]
+	if !ok {
+		if AllowUnknownPlateCountries {
+			return nil
+		}
+		return fieldError("plateCountry", "plateCountry %s is not supported", plateCountry)
+	}
+	if !Contains(states, plateState) {
+		return fieldError("plateState", "plateState %s is not valid for country %s", plateState, plateCountry)
+	}
+	return nil
+}
+
+// NormalizePlate returns a plate country, state or number in the form charges
+// store and are matched on: trimmed and upper case.
+func NormalizePlate(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
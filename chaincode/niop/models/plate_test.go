@@ -0,0 +1,78 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePlateJurisdiction(t *testing.T) {
+	valid := []struct{ country, state string }{
+		{"US", "CA"},
+		{"US", "TX"},
+		{"US", "DC"},
+		{"CA", "ON"},
+		{"CA", "BC"},
+		{"MX", "BCN"},
+		{"", "CA"},
+		{"US", ""},
+	}
+	for _, tt := range valid {
+		t.Run(tt.country+"/"+tt.state, func(t *testing.T) {
+			assert.NoError(t, ValidatePlateJurisdiction(tt.country, tt.state))
+		})
+	}
+
+	t.Run("rejects Canadian province on US plate", func(t *testing.T) {
+		err := ValidatePlateJurisdiction("US", "ON")
+		require.Error(t, err)
+		assert.Equal(t, "plateState ON is not valid for country US", err.Error())
+	})
+
+	t.Run("rejects US state on Canadian plate", func(t *testing.T) {
+		err := ValidatePlateJurisdiction("CA", "TX")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateState TX is not valid for country CA")
+	})
+
+	t.Run("rejects California on Mexican plate", func(t *testing.T) {
+		err := ValidatePlateJurisdiction("MX", "CA")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateState CA is not valid for country MX")
+	})
+}
+
+func TestValidatePlateJurisdiction_UnknownCountry(t *testing.T) {
+	t.Run("allowed by default", func(t *testing.T) {
+		assert.NoError(t, ValidatePlateJurisdiction("DE", "BY"))
+	})
+
+	t.Run("rejected when not allowed", func(t *testing.T) {
+		AllowUnknownPlateCountries = false
+		t.Cleanup(func() { AllowUnknownPlateCountries = true })
+
+		err := ValidatePlateJurisdiction("DE", "BY")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plateCountry DE is not supported")
+	})
+}
+
+func TestCharge_Validate_PlateJurisdiction(t *testing.T) {
+	c := validVideoCharge()
+	c.PlateCountry = "MX"
+	c.PlateState = "CA"
+	err := c.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plateState CA is not valid for country MX")
+}
+
+func TestCharge_NormalizePlate(t *testing.T) {
+	c := Charge{PlateCountry: "us", PlateState: " ca", PlateNumber: "7abc123 "}
+	c.NormalizePlate()
+	assert.Equal(t, "US", c.PlateCountry)
+	assert.Equal(t, "CA", c.PlateState)
+	assert.Equal(t, "7ABC123", c.PlateNumber)
+}
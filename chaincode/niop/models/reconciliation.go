@@ -4,16 +4,27 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
 // Reconciliation represents the home agency's response to a submitted charge.
 // It records whether the charge was posted and any adjustments made.
+// CorrectionSeqNo is optional; when set, the reconciliation answers that
+// correction of the charge rather than the charge itself.
+// AwayAgencyID is optional; when present, the reconciliation is checked
+// against the charge it answers. AllowOverpost permits a posted amount above
+// the charged amount, which is otherwise rejected. BatchID is optional and
+// names the ReconciliationBatch the reconciliation arrived in.
 type Reconciliation struct {
 	DocType            string  `json:"docType"`
+	SchemaVersion      int     `json:"schemaVersion"`
 	ReconciliationID   string  `json:"reconciliationID"`
 	ChargeID           string  `json:"chargeID"`
+	BatchID            string  `json:"batchID,omitempty"`
+	CorrectionSeqNo    int     `json:"correctionSeqNo,omitempty"`
 	HomeAgencyID       string  `json:"homeAgencyID"`
+	AwayAgencyID       string  `json:"awayAgencyID,omitempty"`
 	PostingDisposition string  `json:"postingDisposition"`
 	PostedAmount       float64 `json:"postedAmount"`
 	PostedDateTime     string  `json:"postedDateTime,omitempty"`
@@ -22,6 +33,8 @@ type Reconciliation struct {
 	FlatFee            float64 `json:"flatFee"`
 	PercentFee         float64 `json:"percentFee"`
 	DiscountPlanType   string  `json:"discountPlanType,omitempty"`
+	AmountMismatch     bool    `json:"amountMismatch,omitempty"`
+	AllowOverpost      bool    `json:"allowOverpost,omitempty"`
 	CreatedAt          string  `json:"createdAt"`
 }
 
@@ -40,53 +53,109 @@ var PostingDispositionDescriptions = map[string]string{
 	"O": "Transaction too old",
 }
 
-// Validate checks all fields of a Reconciliation and returns an error
-// describing the first validation failure, or nil if valid.
+// Validate checks all fields of a Reconciliation and returns a
+// *ValidationError describing the first validation failure, or nil if valid.
 func (r *Reconciliation) Validate() error {
+	return ValidationErrors(r.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Reconciliation and returns every
+// validation failure in the order Validate checks them, or nil if valid.
+// Fees are compared with the posted amount only when both are valid.
+func (r *Reconciliation) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if r.ReconciliationID == "" {
-		return fmt.Errorf("reconciliationID is required")
+		errs.addf("reconciliationID", "reconciliationID is required")
 	}
 	if r.ChargeID == "" {
-		return fmt.Errorf("chargeID is required")
+		errs.addf("chargeID", "chargeID is required")
 	}
-	if r.HomeAgencyID == "" {
-		return fmt.Errorf("homeAgencyID is required")
+	if r.CorrectionSeqNo < 0 || r.CorrectionSeqNo > 999 {
+		errs.addf("correctionSeqNo", "correctionSeqNo must be between 0 and 999, got %d", r.CorrectionSeqNo)
 	}
-	if r.PostingDisposition == "" {
-		return fmt.Errorf("postingDisposition is required")
+	errs.add(ValidateAgencyID("homeAgencyID", r.HomeAgencyID))
+	if r.AwayAgencyID != "" {
+		errs.add(ValidateAgencyID("awayAgencyID", r.AwayAgencyID))
 	}
-	if !contains(ValidPostingDispositions, r.PostingDisposition) {
-		return fmt.Errorf("invalid postingDisposition %q: must be one of %v", r.PostingDisposition, ValidPostingDispositions)
+	if r.PostingDisposition == "" {
+		errs.addf("postingDisposition", "postingDisposition is required")
+	} else if !postingDispositionSet.Has(r.PostingDisposition) {
+		errs.addf("postingDisposition", "invalid postingDisposition %q: must be one of %v", r.PostingDisposition, ValidPostingDispositions)
 	}
+	amountsValid := true
 	if r.PostedAmount < 0 {
-		return fmt.Errorf("postedAmount must be >= 0, got %f", r.PostedAmount)
+		errs.addf("postedAmount", "postedAmount must be >= 0, got %f", r.PostedAmount)
+		amountsValid = false
 	}
 	if r.AdjustmentCount < 0 {
-		return fmt.Errorf("adjustmentCount must be >= 0, got %d", r.AdjustmentCount)
+		errs.addf("adjustmentCount", "adjustmentCount must be >= 0, got %d", r.AdjustmentCount)
 	}
 	if r.FlatFee < 0 {
-		return fmt.Errorf("flatFee must be >= 0, got %f", r.FlatFee)
+		errs.addf("flatFee", "flatFee must be >= 0, got %f", r.FlatFee)
+		amountsValid = false
 	}
 	if r.PercentFee < 0 {
-		return fmt.Errorf("percentFee must be >= 0, got %f", r.PercentFee)
+		errs.addf("percentFee", "percentFee must be >= 0, got %f", r.PercentFee)
+		amountsValid = false
+	} else if r.PercentFee > 1 {
+		errs.addf("percentFee", "percentFee must be a fraction no greater than 1, got %f", r.PercentFee)
+		amountsValid = false
 	}
+	errs.add(ValidateDiscountPlanType("discountPlanType", r.DiscountPlanType))
 
 	// Posted disposition requires a posted date/time.
 	if r.PostingDisposition == "P" && r.PostedDateTime == "" {
-		return fmt.Errorf("postedDateTime is required when postingDisposition is P")
+		errs.addf("postedDateTime", "postedDateTime is required when postingDisposition is P")
+	}
+	if r.PostedDateTime != "" {
+		if _, err := time.Parse(time.RFC3339, r.PostedDateTime); err != nil {
+			errs.addf("postedDateTime", "postedDateTime must be RFC3339, got %q", r.PostedDateTime)
+		}
+	}
+	if amountsValid && r.IsPosted() && math.Round(r.EffectiveFee()*100) > math.Round(r.PostedAmount*100) {
+		errs.addf("postedAmount", "fees exceed posted amount")
 	}
 
-	return nil
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// EffectiveFee returns the fee the home agency declared on the posted amount:
+// flatFee plus percentFee (a fraction, as in FeeSchedule) of postedAmount.
+func (r *Reconciliation) EffectiveFee() float64 {
+	return EffectiveFee(r.PostedAmount, r.FlatFee, r.PercentFee)
 }
 
 // Key returns the ledger key for this reconciliation.
 func (r *Reconciliation) Key() string {
-	return "RECON_" + r.ChargeID
+	return ReconciliationKey(r.ChargeID, r.CorrectionSeqNo)
 }
 
-// SetCreatedAt sets CreatedAt to the current time and ensures DocType is set.
+// ReconciliationKey returns the ledger key of the reconciliation for a charge
+// (correctionSeqNo 0) or for one of its corrections: RECON_{chargeID} or
+// RECONCORR_{chargeID}_{seqNo:03d}. Correction-level keys have their own
+// prefix because charge IDs may contain "_": with a shared prefix, charge
+// X_001 would have the key of correction 1 of charge X.
+func ReconciliationKey(chargeID string, correctionSeqNo int) string {
+	if correctionSeqNo == 0 {
+		return "RECON_" + chargeID
+	}
+	return fmt.Sprintf("RECONCORR_%s_%03d", chargeID, correctionSeqNo)
+}
+
+// IsCorrectionLevel returns true if the reconciliation answers a correction
+// rather than the original charge.
+func (r *Reconciliation) IsCorrectionLevel() bool {
+	return r.CorrectionSeqNo > 0
+}
+
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
 func (r *Reconciliation) SetCreatedAt() {
 	r.DocType = "reconciliation"
+	r.SchemaVersion = CurrentSchemaVersion
 	r.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 }
 
@@ -94,3 +163,28 @@ func (r *Reconciliation) SetCreatedAt() {
 func (r *Reconciliation) IsPosted() bool {
 	return r.PostingDisposition == "P"
 }
+
+// ValidateAgainstCharge checks the reconciliation against the charge it
+// answers. A posted amount may not exceed the charged amount unless
+// AllowOverpost is set.
+func (r *Reconciliation) ValidateAgainstCharge(charge *Charge) error {
+	if r.AllowOverpost {
+		return nil
+	}
+	if math.Round(r.PostedAmount*100) > math.Round(charge.Amount*100) {
+		return fmt.Errorf("postedAmount exceeds charge amount")
+	}
+	return nil
+}
+
+// HasAmountMismatch returns true if the reconciliation posted the charge at an
+// amount that differs from the charged amount by more than one cent.
+// Only posted reconciliations can mismatch; other dispositions post nothing.
+func (r *Reconciliation) HasAmountMismatch(charge *Charge) bool {
+	if !r.IsPosted() {
+		return false
+	}
+	postedCents := math.Round(r.PostedAmount * 100)
+	chargedCents := math.Round(charge.Amount * 100)
+	return math.Abs(postedCents-chargedCents) > 1
+}
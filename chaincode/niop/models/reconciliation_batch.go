@@ -0,0 +1,96 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReconciliationBatch groups the reconciliations a home agency returned in
+// one SRECON file. Count is the record count declared in the file header, so
+// an acknowledgement with return code "04" (record count mismatch) can be
+// checked against the reconciliations actually listed. SequenceNumber, when
+// set, is the file's sequence number among the home agency's submissions to
+// the away agency (see SubmissionSequence). Batches are stored in world state
+// alongside the reconciliations they group.
+type ReconciliationBatch struct {
+	DocType           string   `json:"docType"`
+	SchemaVersion     int      `json:"schemaVersion"`
+	BatchID           string   `json:"batchID"`
+	HomeAgencyID      string   `json:"homeAgencyID"`
+	AwayAgencyID      string   `json:"awayAgencyID"`
+	ReconciliationIDs []string `json:"reconciliationIDs"`
+	Count             int      `json:"count"`
+	SequenceNumber    int      `json:"sequenceNumber,omitempty"`
+	CreatedAt         string   `json:"createdAt"`
+}
+
+// Validate checks all fields of a ReconciliationBatch and returns a
+// *ValidationError describing the first validation failure, or nil if valid.
+func (b *ReconciliationBatch) Validate() error {
+	return ValidationErrors(b.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a ReconciliationBatch and returns every
+// validation failure in the order Validate checks them, or nil if valid.
+func (b *ReconciliationBatch) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	if b.BatchID == "" {
+		errs.addf("batchID", "batchID is required")
+	}
+	homeErr := ValidateAgencyID("homeAgencyID", b.HomeAgencyID)
+	awayErr := ValidateAgencyID("awayAgencyID", b.AwayAgencyID)
+	errs.add(homeErr)
+	errs.add(awayErr)
+	if homeErr == nil && awayErr == nil && b.HomeAgencyID == b.AwayAgencyID {
+		errs.addf("awayAgencyID", "homeAgencyID and awayAgencyID must be different")
+	}
+	if len(b.ReconciliationIDs) == 0 {
+		errs.addf("reconciliationIDs", "reconciliationIDs must not be empty")
+	}
+	seen := make(map[string]bool, len(b.ReconciliationIDs))
+	for i, id := range b.ReconciliationIDs {
+		if id == "" {
+			errs.addf(fmt.Sprintf("reconciliationIDs[%d]", i), "reconciliationIDs[%d] is empty", i)
+			continue
+		}
+		if seen[id] {
+			errs.addf(fmt.Sprintf("reconciliationIDs[%d]", i), "duplicate reconciliationID %s", id)
+		}
+		seen[id] = true
+	}
+	if len(b.ReconciliationIDs) > 0 && b.Count != len(b.ReconciliationIDs) {
+		errs.addf("count", "record count mismatch: count is %d but %d reconciliationIDs are listed", b.Count, len(b.ReconciliationIDs))
+	}
+	if b.SequenceNumber < 0 {
+		errs.addf("sequenceNumber", "sequenceNumber must be >= 0, got %d", b.SequenceNumber)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Includes returns true if the batch lists reconciliationID.
+func (b *ReconciliationBatch) Includes(reconciliationID string) bool {
+	return Contains(b.ReconciliationIDs, reconciliationID)
+}
+
+// Key returns the ledger key for this batch.
+func (b *ReconciliationBatch) Key() string {
+	return ReconciliationBatchKey(b.BatchID)
+}
+
+// ReconciliationBatchKey returns the ledger key of a reconciliation batch.
+func ReconciliationBatchKey(batchID string) string {
+	return "RECONBATCH_" + batchID
+}
+
+// SetCreatedAt sets CreatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
+func (b *ReconciliationBatch) SetCreatedAt() {
+	b.DocType = "reconciliationbatch"
+	b.SchemaVersion = CurrentSchemaVersion
+	b.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validReconciliationBatch() ReconciliationBatch {
+	return ReconciliationBatch{
+		BatchID:           "SRECON-TEST-001",
+		HomeAgencyID:      "ORG1",
+		AwayAgencyID:      "ORG2",
+		ReconciliationIDs: []string{"RECON-TEST-001", "RECON-TEST-002"},
+		Count:             2,
+	}
+}
+
+func TestReconciliationBatch_Validate(t *testing.T) {
+	b := validReconciliationBatch()
+	assert.NoError(t, b.Validate())
+}
+
+func TestReconciliationBatch_Validate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*ReconciliationBatch)
+		wantErr string
+	}{
+		{
+			name:    "missing batchID",
+			modify:  func(b *ReconciliationBatch) { b.BatchID = "" },
+			wantErr: "batchID is required",
+		},
+		{
+			name:    "same agencies",
+			modify:  func(b *ReconciliationBatch) { b.AwayAgencyID = "ORG1" },
+			wantErr: "must be different",
+		},
+		{
+			name:    "no reconciliations",
+			modify:  func(b *ReconciliationBatch) { b.ReconciliationIDs = nil; b.Count = 0 },
+			wantErr: "reconciliationIDs must not be empty",
+		},
+		{
+			name:    "duplicate reconciliation",
+			modify:  func(b *ReconciliationBatch) { b.ReconciliationIDs[1] = "RECON-TEST-001" },
+			wantErr: "duplicate reconciliationID RECON-TEST-001",
+		},
+		{
+			name:    "count mismatch",
+			modify:  func(b *ReconciliationBatch) { b.Count = 3 },
+			wantErr: "record count mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := validReconciliationBatch()
+			tt.modify(&b)
+			err := b.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestReconciliationBatch_Includes(t *testing.T) {
+	b := validReconciliationBatch()
+	assert.True(t, b.Includes("RECON-TEST-002"))
+	assert.False(t, b.Includes("RECON-TEST-003"))
+}
+
+func TestReconciliationBatch_ValidateAll(t *testing.T) {
+	b := validReconciliationBatch()
+	assert.Nil(t, b.ValidateAll())
+
+	b.BatchID = ""
+	b.ReconciliationIDs[1] = "RECON-TEST-001"
+	b.Count = 3
+	errs := b.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"batchID", "reconciliationIDs[1]", "count"}, fields)
+	assert.EqualError(t, b.Validate(), errs[0].Message)
+}
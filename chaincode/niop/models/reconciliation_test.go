@@ -42,6 +42,14 @@ func TestReconciliation_Validate(t *testing.T) {
 		r.DiscountPlanType = "commuter"
 		assert.NoError(t, r.Validate())
 	})
+
+	t.Run("rejects unknown discount plan", func(t *testing.T) {
+		r := validReconciliation()
+		r.DiscountPlanType = "vip"
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid discountPlanType")
+	})
 }
 
 func TestReconciliation_Validate_RequiredFields(t *testing.T) {
@@ -111,6 +119,37 @@ func TestReconciliation_Validate_NonPostedDoesNotRequireDateTime(t *testing.T) {
 	}
 }
 
+func TestReconciliation_Validate_PostedDateTimeFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		posted      string
+		wantErr     string
+	}{
+		{name: "RFC3339 UTC", disposition: "P", posted: "2026-01-15T10:00:00Z"},
+		{name: "RFC3339 with offset", disposition: "P", posted: "2026-01-15T02:00:00-08:00"},
+		{name: "date only", disposition: "P", posted: "2026-01-15", wantErr: "postedDateTime must be RFC3339"},
+		{name: "free text", disposition: "P", posted: "yesterday", wantErr: "postedDateTime must be RFC3339"},
+		{name: "malformed on non-posted disposition", disposition: "N", posted: "01/15/2026", wantErr: "postedDateTime must be RFC3339"},
+		{name: "absent on non-posted disposition", disposition: "N", posted: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validReconciliation()
+			r.PostingDisposition = tt.disposition
+			r.PostedDateTime = tt.posted
+			err := r.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestReconciliation_Validate_NegativeValues(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -157,9 +196,75 @@ func TestReconciliation_Validate_PostedAmountCanDiffer(t *testing.T) {
 	assert.NoError(t, r.Validate())
 }
 
+func TestReconciliation_Validate_Fees(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		posted      float64
+		flat        float64
+		percent     float64
+		wantErr     string
+	}{
+		{name: "flat and percent within posted amount", disposition: "P", posted: 4.75, flat: 0.05, percent: 0.02},
+		{name: "fees equal posted amount", disposition: "P", posted: 0.50, flat: 0.50},
+		{name: "flat fee exceeds posted amount", disposition: "P", posted: 0.50, flat: 0.75, wantErr: "fees exceed posted amount"},
+		{name: "flat plus percent exceeds posted amount", disposition: "P", posted: 1.00, flat: 0.60, percent: 0.50, wantErr: "fees exceed posted amount"},
+		{name: "percentFee given as whole percent", disposition: "P", posted: 4.75, percent: 2, wantErr: "percentFee must be a fraction"},
+		{name: "non-posted disposition is not checked", disposition: "N", posted: 0, flat: 0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validReconciliation()
+			r.PostingDisposition = tt.disposition
+			r.PostedAmount = tt.posted
+			r.FlatFee = tt.flat
+			r.PercentFee = tt.percent
+			err := r.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestReconciliation_EffectiveFee(t *testing.T) {
+	r := validReconciliation()
+	r.PostedAmount = 10.00
+	r.FlatFee = 0.25
+	r.PercentFee = 0.03
+	assert.InDelta(t, 0.55, r.EffectiveFee(), 0.0001)
+}
+
 func TestReconciliation_Key(t *testing.T) {
 	r := Reconciliation{ChargeID: "CHG-001"}
 	assert.Equal(t, "RECON_CHG-001", r.Key())
+	assert.False(t, r.IsCorrectionLevel())
+
+	r.CorrectionSeqNo = 2
+	assert.Equal(t, "RECONCORR_CHG-001_002", r.Key())
+	assert.True(t, r.IsCorrectionLevel())
+}
+
+func TestReconciliationKey_NoCollision(t *testing.T) {
+	// Charge X_001 and correction 1 of charge X must not share a key.
+	assert.NotEqual(t, ReconciliationKey("X_001", 0), ReconciliationKey("X", 1))
+}
+
+func TestReconciliation_Validate_CorrectionSeqNo(t *testing.T) {
+	r := validReconciliation()
+	r.CorrectionSeqNo = 1
+	assert.NoError(t, r.Validate())
+
+	for _, seq := range []int{-1, 1000} {
+		r.CorrectionSeqNo = seq
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correctionSeqNo must be between 0 and 999")
+	}
 }
 
 func TestReconciliation_SetCreatedAt(t *testing.T) {
@@ -189,6 +294,63 @@ func TestReconciliation_IsPosted(t *testing.T) {
 	})
 }
 
+func TestReconciliation_ValidateAgainstCharge(t *testing.T) {
+	charge := &Charge{Amount: 4.75}
+
+	t.Run("equal amount", func(t *testing.T) {
+		r := validReconciliation()
+		r.PostedAmount = 4.75
+		assert.NoError(t, r.ValidateAgainstCharge(charge))
+	})
+
+	t.Run("under amount", func(t *testing.T) {
+		r := validReconciliation()
+		r.PostedAmount = 4.00
+		assert.NoError(t, r.ValidateAgainstCharge(charge))
+	})
+
+	t.Run("over amount", func(t *testing.T) {
+		r := validReconciliation()
+		r.PostedAmount = 4.76
+		err := r.ValidateAgainstCharge(charge)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "postedAmount exceeds charge amount")
+	})
+
+	t.Run("over amount with allowOverpost", func(t *testing.T) {
+		r := validReconciliation()
+		r.PostedAmount = 4.76
+		r.AllowOverpost = true
+		assert.NoError(t, r.ValidateAgainstCharge(charge))
+	})
+}
+
+func TestReconciliation_HasAmountMismatch(t *testing.T) {
+	charge := &Charge{Amount: 4.75}
+
+	tests := []struct {
+		name        string
+		disposition string
+		posted      float64
+		want        bool
+	}{
+		{"exact match", "P", 4.75, false},
+		{"one cent over", "P", 4.76, false},
+		{"one cent under", "P", 4.74, false},
+		{"two cents over", "P", 4.77, true},
+		{"large shortfall", "P", 3.50, true},
+		{"not posted", "D", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validReconciliation()
+			r.PostingDisposition = tt.disposition
+			r.PostedAmount = tt.posted
+			assert.Equal(t, tt.want, r.HasAmountMismatch(charge))
+		})
+	}
+}
+
 func TestReconciliation_Validate_AllDispositions(t *testing.T) {
 	for _, disp := range ValidPostingDispositions {
 		t.Run(disp, func(t *testing.T) {
@@ -201,3 +363,19 @@ func TestReconciliation_Validate_AllDispositions(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciliation_ValidateAll(t *testing.T) {
+	r := validReconciliation()
+	assert.Nil(t, r.ValidateAll())
+
+	r.ChargeID = ""
+	r.PostingDisposition = "Z"
+	r.FlatFee = -1
+	errs := r.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"chargeID", "postingDisposition", "flatFee"}, fields)
+	assert.EqualError(t, r.Validate(), errs[0].Message)
+}
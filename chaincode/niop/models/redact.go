@@ -0,0 +1,53 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import "strings"
+
+// ChargePIIFields lists the charge fields that can identify a customer's
+// vehicle. Redacted copies of a charge mask these fields.
+var ChargePIIFields = []string{"plateNumber", "tagSerialNumber"}
+
+// Mask replaces all but the first keepFront and last keepBack characters of
+// value with '*'. At least half of the value is always masked, so short
+// values never reveal more than half their characters.
+func Mask(value string, keepFront int, keepBack int) string {
+	runes := []rune(value)
+	n := len(runes)
+	limit := n / 2
+	if keepFront > limit {
+		keepFront = limit
+	}
+	if keepBack > limit-keepFront {
+		keepBack = limit - keepFront
+	}
+	if keepFront < 0 {
+		keepFront = 0
+	}
+	if keepBack < 0 {
+		keepBack = 0
+	}
+
+	masked := n - keepFront - keepBack
+	return string(runes[:keepFront]) + strings.Repeat("*", masked) + string(runes[n-keepBack:])
+}
+
+// MaskPlate masks a plate number, leaving the first three characters
+// visible: "7ABC123" becomes "7AB****".
+func MaskPlate(plate string) string {
+	return Mask(plate, 3, 0)
+}
+
+// MaskTagSerial masks a tag serial number, leaving the last four characters
+// visible: "TEST.000000001" becomes "**********0001".
+func MaskTagSerial(serial string) string {
+	return Mask(serial, 0, 4)
+}
+
+// Redacted returns a copy of the charge with its PII fields masked.
+func (c *Charge) Redacted() *Charge {
+	redacted := *c
+	redacted.PlateNumber = MaskPlate(c.PlateNumber)
+	redacted.TagSerialNumber = MaskTagSerial(c.TagSerialNumber)
+	return &redacted
+}
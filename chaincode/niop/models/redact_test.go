@@ -0,0 +1,55 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskPlate(t *testing.T) {
+	tests := []struct {
+		plate string
+		want  string
+	}{
+		{"7ABC123", "7AB****"},
+		{"ABC1234X", "ABC*****"},
+		{"ABCD", "AB**"},
+		{"AB1", "A**"},
+		{"A", "*"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.plate, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskPlate(tt.plate))
+		})
+	}
+}
+
+func TestMaskTagSerial(t *testing.T) {
+	assert.Equal(t, "**********0001", MaskTagSerial("TEST.000000001"))
+	assert.Equal(t, "***45", MaskTagSerial("12345"))
+	assert.Equal(t, "", MaskTagSerial(""))
+}
+
+func TestMask_NeverRevealsMoreThanHalf(t *testing.T) {
+	assert.Equal(t, "AB***F", Mask("ABCDEF", 2, 2))
+	assert.Equal(t, "A***EF", Mask("ABCDEF", 1, 5))
+	assert.Equal(t, "******", Mask("ABCDEF", -1, 0))
+}
+
+func TestCharge_Redacted(t *testing.T) {
+	c := validCharge()
+	c.PlateNumber = "7ABC123"
+
+	redacted := c.Redacted()
+	assert.Equal(t, "7AB****", redacted.PlateNumber)
+	assert.Equal(t, "**********0001", redacted.TagSerialNumber)
+	assert.Equal(t, c.ChargeID, redacted.ChargeID)
+	assert.Equal(t, c.Amount, redacted.Amount)
+
+	// The original is untouched.
+	assert.Equal(t, "7ABC123", c.PlateNumber)
+	assert.Equal(t, "TEST.000000001", c.TagSerialNumber)
+}
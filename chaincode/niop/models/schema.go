@@ -0,0 +1,160 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the schemas built by
+// JSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaModels lists the documents JSONSchemas describes, keyed by the
+// base name of their schema file.
+var SchemaModels = map[string]interface{}{
+	"acknowledgement": Acknowledgement{},
+	"agency":          Agency{},
+	"charge":          Charge{},
+	"correction":      Correction{},
+	"reconciliation":  Reconciliation{},
+	"settlement":      Settlement{},
+	"tag":             Tag{},
+}
+
+// schemaEnums gives the allowed values of enum fields, by Go type name and
+// JSON field name. The values are the lists validation checks against, so a
+// schema always agrees with Validate. For an array field they constrain the
+// array's items.
+var schemaEnums = map[string]map[string][]string{
+	"Acknowledgement": {
+		"submissionType": ValidSubmissionTypes,
+		"returnCode":     ValidReturnCodes,
+	},
+	"Agency": {
+		"consortium":       ValidConsortiums,
+		"role":             ValidRoles,
+		"connectivityMode": ValidConnectivityModes,
+		"status":           ValidAgencyStatuses,
+		"capabilities":     ValidCapabilities,
+		"protocolSupport":  ValidProtocols,
+	},
+	"Charge": {
+		"chargeType":       ValidChargeTypes,
+		"recordType":       ChargeRecordTypes(),
+		"protocol":         ValidChargeProtocols,
+		"submittedVia":     ValidSubmissionChannels,
+		"discountPlanType": ValidDiscountPlanTypes,
+		"status":           ValidChargeStatuses,
+	},
+	"Correction": {
+		"correctionReason": ValidCorrectionReasons,
+		"resubmitReason":   ValidResubmitReasons,
+		"recordType":       ValidCorrectionRecordTypes,
+	},
+	"DiscountPlan": {
+		"type": ValidDiscountPlanTypes,
+	},
+	"Reconciliation": {
+		"postingDisposition": ValidPostingDispositions,
+		"discountPlanType":   ValidDiscountPlanTypes,
+	},
+	"Settlement": {
+		"status": ValidSettlementStatuses,
+	},
+	"StatusChange": {
+		"status": ValidSettlementStatuses,
+	},
+	"Tag": {
+		"tagStatus":   ValidTagStatuses,
+		"tagType":     ValidTagTypes,
+		"tagProtocol": ValidTagProtocols,
+	},
+}
+
+var moneyType = reflect.TypeOf(Money(0))
+
+// JSONSchema describes the JSON encoding of model as a JSON Schema object:
+// each field's type, with the allowed values of enum fields. Optional fields
+// are omitted from the JSON when empty, so an enum does not list "".
+// Required fields and other rules checked by Validate are not described.
+func JSONSchema(model interface{}) map[string]interface{} {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema := typeSchema(t)
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = t.Name()
+	return schema
+}
+
+// JSONSchemas returns the schema of every model in SchemaModels, keyed the
+// same way.
+func JSONSchemas() map[string]map[string]interface{} {
+	schemas := make(map[string]map[string]interface{}, len(SchemaModels))
+	for name, model := range SchemaModels {
+		schemas[name] = JSONSchema(model)
+	}
+	return schemas
+}
+
+// typeSchema returns the schema of a Go type as encoding/json encodes it.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == moneyType {
+		return map[string]interface{}{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]{1,2})?$`}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the schema of a struct's exported, JSON-encoded
+// fields, applying any enums schemaEnums lists for the struct.
+func structSchema(t reflect.Type) map[string]interface{} {
+	enums := schemaEnums[t.Name()]
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := typeSchema(field.Type)
+		if values, ok := enums[name]; ok {
+			target := schema
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				target = items
+			}
+			target["enum"] = append([]string{}, values...)
+		}
+		properties[name] = schema
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
@@ -0,0 +1,114 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaProperty returns the schema of a top-level property, failing the
+// test if there is none.
+func schemaProperty(t *testing.T, schema map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	properties := schema["properties"].(map[string]interface{})
+	property, ok := properties[name].(map[string]interface{})
+	require.True(t, ok, "no property %q", name)
+	return property
+}
+
+func TestJSONSchema_EnumsMatchValidLists(t *testing.T) {
+	tests := []struct {
+		model interface{}
+		field string
+		want  []string
+	}{
+		{Acknowledgement{}, "submissionType", ValidSubmissionTypes},
+		{Acknowledgement{}, "returnCode", ValidReturnCodes},
+		{Agency{}, "role", ValidRoles},
+		{Agency{}, "connectivityMode", ValidConnectivityModes},
+		{Agency{}, "status", ValidAgencyStatuses},
+		{Charge{}, "chargeType", ValidChargeTypes},
+		{Charge{}, "recordType", ChargeRecordTypes()},
+		{Charge{}, "protocol", ValidChargeProtocols},
+		{Charge{}, "submittedVia", ValidSubmissionChannels},
+		{Charge{}, "discountPlanType", ValidDiscountPlanTypes},
+		{Charge{}, "status", ValidChargeStatuses},
+		{Correction{}, "correctionReason", ValidCorrectionReasons},
+		{Correction{}, "resubmitReason", ValidResubmitReasons},
+		{Correction{}, "recordType", ValidCorrectionRecordTypes},
+		{Reconciliation{}, "postingDisposition", ValidPostingDispositions},
+		{Reconciliation{}, "discountPlanType", ValidDiscountPlanTypes},
+		{Settlement{}, "status", ValidSettlementStatuses},
+		{Tag{}, "tagStatus", ValidTagStatuses},
+		{Tag{}, "tagType", ValidTagTypes},
+		{Tag{}, "tagProtocol", ValidTagProtocols},
+	}
+
+	for _, tt := range tests {
+		schema := JSONSchema(tt.model)
+		property := schemaProperty(t, schema, tt.field)
+		assert.Equal(t, tt.want, property["enum"], "%s.%s", schema["title"], tt.field)
+	}
+}
+
+func TestJSONSchema_ArrayAndNestedEnums(t *testing.T) {
+	agency := JSONSchema(Agency{})
+	for field, want := range map[string][]string{
+		"consortium":      ValidConsortiums,
+		"capabilities":    ValidCapabilities,
+		"protocolSupport": ValidProtocols,
+	} {
+		property := schemaProperty(t, agency, field)
+		assert.Equal(t, "array", property["type"], field)
+		assert.Equal(t, want, property["items"].(map[string]interface{})["enum"], field)
+	}
+
+	plans := schemaProperty(t, JSONSchema(Tag{}), "discountPlans")["items"].(map[string]interface{})
+	assert.Equal(t, ValidDiscountPlanTypes, schemaProperty(t, plans, "type")["enum"])
+
+	history := schemaProperty(t, JSONSchema(&Settlement{}), "statusHistory")["items"].(map[string]interface{})
+	assert.Equal(t, ValidSettlementStatuses, schemaProperty(t, history, "status")["enum"])
+}
+
+func TestJSONSchema_Types(t *testing.T) {
+	schema := JSONSchema(Charge{})
+	assert.Equal(t, JSONSchemaDraft, schema["$schema"])
+	assert.Equal(t, "Charge", schema["title"])
+	assert.Equal(t, "object", schema["type"])
+
+	assert.Equal(t, "string", schemaProperty(t, schema, "chargeID")["type"])
+	assert.Equal(t, "integer", schemaProperty(t, schema, "vehicleClass")["type"])
+	assert.Equal(t, "number", schemaProperty(t, schema, "amount")["type"])
+	assert.Equal(t, "boolean", schemaProperty(t, schema, "deleted")["type"])
+	assert.NotContains(t, schemaProperty(t, schema, "plaza"), "enum")
+
+	breakdown := schemaProperty(t, schema, "feeBreakdown")
+	assert.Equal(t, "object", breakdown["type"])
+	assert.Equal(t, "number", schemaProperty(t, breakdown, "flatFee")["type"])
+
+	type payment struct {
+		Amount Money `json:"amount"`
+	}
+	amount := schemaProperty(t, JSONSchema(payment{}), "amount")
+	assert.Equal(t, "string", amount["type"])
+}
+
+// TestJSONSchema_FilesUpToDate checks the schemas checked in under
+// chaincode/niop/schemas against the models. Regenerate them with
+// go run ./cmd/schemagen if it fails.
+func TestJSONSchema_FilesUpToDate(t *testing.T) {
+	for name, schema := range JSONSchemas() {
+		want, err := json.MarshalIndent(schema, "", "  ")
+		require.NoError(t, err)
+
+		got, err := os.ReadFile(filepath.Join("..", "schemas", name+".schema.json"))
+		require.NoError(t, err, name)
+		assert.Equal(t, string(want)+"\n", string(got), "%s schema is out of date", name)
+	}
+}
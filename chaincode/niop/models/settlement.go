@@ -4,77 +4,176 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
 // Settlement represents a financial settlement between two agencies for
 // a reconciliation period. This aggregates reconciled charges into a net
-// amount owed.
+// amount owed. ChargeIDs links the settlement to the charges it covers and
+// CorrectionIDs to the corrections applied to them; either may be omitted,
+// but when present its length must equal ChargeCount or CorrectionCount.
+// Warnings records charges that could not be settled when it was paid.
+// StatusHistory records every status the settlement has held, oldest first.
+// PaidAmount is the total of the installments paid so far. Supersedes lists
+// the draft settlements a regenerated settlement replaced. PeriodStart and
+// PeriodEnd are both YYYY-MM-DD dates or both RFC3339 timestamps, and are
+// compared as times rather than strings. A settlement with ApprovalsRequired
+// set cannot be submitted until that many distinct MSPs have recorded an
+// approval in Approvals.
 type Settlement struct {
-	DocType         string  `json:"docType"`
-	SettlementID    string  `json:"settlementID"`
-	PeriodStart     string  `json:"periodStart"`
-	PeriodEnd       string  `json:"periodEnd"`
-	PayorAgencyID   string  `json:"payorAgencyID"`
-	PayeeAgencyID   string  `json:"payeeAgencyID"`
-	GrossAmount     float64 `json:"grossAmount"`
-	TotalFees       float64 `json:"totalFees"`
-	NetAmount       float64 `json:"netAmount"`
-	ChargeCount     int     `json:"chargeCount"`
-	CorrectionCount int     `json:"correctionCount"`
-	Status          string  `json:"status"`
-	CreatedAt       string  `json:"createdAt"`
+	DocType           string         `json:"docType"`
+	SchemaVersion     int            `json:"schemaVersion"`
+	SettlementID      string         `json:"settlementID"`
+	PeriodStart       string         `json:"periodStart"`
+	PeriodEnd         string         `json:"periodEnd"`
+	PayorAgencyID     string         `json:"payorAgencyID"`
+	PayeeAgencyID     string         `json:"payeeAgencyID"`
+	GrossAmount       float64        `json:"grossAmount"`
+	TotalFees         float64        `json:"totalFees"`
+	NetAmount         float64        `json:"netAmount"`
+	PaidAmount        float64        `json:"paidAmount"`
+	ChargeCount       int            `json:"chargeCount"`
+	CorrectionCount   int            `json:"correctionCount"`
+	ChargeIDs         []string       `json:"chargeIDs,omitempty"`
+	CorrectionIDs     []string       `json:"correctionIDs,omitempty"`
+	Warnings          []string       `json:"warnings,omitempty"`
+	Supersedes        []string       `json:"supersedes,omitempty"`
+	Status            string         `json:"status"`
+	StatusHistory     []StatusChange `json:"statusHistory,omitempty"`
+	ApprovalsRequired int            `json:"approvalsRequired,omitempty"`
+	Approvals         []Approval     `json:"approvals,omitempty"`
+	CreatedAt         string         `json:"createdAt"`
+}
+
+// parsePeriodDate parses a settlement period bound, which is either a
+// YYYY-MM-DD date or an RFC3339 timestamp, and reports which it is. Months
+// and days must be zero-padded; "2026-1-1" is rejected rather than compared.
+func parsePeriodDate(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// Approval records an MSP's approval of a draft settlement.
+type Approval struct {
+	MSPID      string `json:"mspID"`
+	ApprovedAt string `json:"approvedAt"`
+}
+
+// StatusChange records when an entity entered a status.
+type StatusChange struct {
+	Status    string `json:"status"`
+	ChangedAt string `json:"changedAt"`
 }
 
 // Valid settlement statuses.
 var ValidSettlementStatuses = []string{"draft", "submitted", "accepted", "disputed", "paid"}
 
-// Validate checks all fields of a Settlement and returns an error
+// Validate checks all fields of a Settlement and returns a *ValidationError
 // describing the first validation failure, or nil if valid.
 func (s *Settlement) Validate() error {
+	return ValidationErrors(s.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Settlement and returns every validation
+// failure in the order Validate checks them, or nil if valid.
+func (s *Settlement) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if s.SettlementID == "" {
-		return fmt.Errorf("settlementID is required")
+		errs.addf("settlementID", "settlementID is required")
 	}
+	var start, end time.Time
+	var startDateOnly, endDateOnly bool
+	startValid, endValid := false, false
 	if s.PeriodStart == "" {
-		return fmt.Errorf("periodStart is required")
+		errs.addf("periodStart", "periodStart is required")
+	} else if parsed, dateOnly, err := parsePeriodDate(s.PeriodStart); err != nil {
+		errs.addf("periodStart", "invalid periodStart %q: must be a YYYY-MM-DD date or an RFC3339 timestamp", s.PeriodStart)
+	} else {
+		start, startDateOnly, startValid = parsed, dateOnly, true
 	}
 	if s.PeriodEnd == "" {
-		return fmt.Errorf("periodEnd is required")
-	}
-	if s.PeriodEnd < s.PeriodStart {
-		return fmt.Errorf("periodEnd %q must not be before periodStart %q", s.PeriodEnd, s.PeriodStart)
-	}
-	if s.PayorAgencyID == "" {
-		return fmt.Errorf("payorAgencyID is required")
+		errs.addf("periodEnd", "periodEnd is required")
+	} else if parsed, dateOnly, err := parsePeriodDate(s.PeriodEnd); err != nil {
+		errs.addf("periodEnd", "invalid periodEnd %q: must be a YYYY-MM-DD date or an RFC3339 timestamp", s.PeriodEnd)
+	} else {
+		end, endDateOnly, endValid = parsed, dateOnly, true
 	}
-	if s.PayeeAgencyID == "" {
-		return fmt.Errorf("payeeAgencyID is required")
+	if startValid && endValid {
+		if startDateOnly != endDateOnly {
+			errs.addf("periodEnd", "periodStart and periodEnd must use the same date format")
+		} else if end.Before(start) {
+			errs.addf("periodEnd", "periodEnd %q must not be before periodStart %q", s.PeriodEnd, s.PeriodStart)
+		}
 	}
-	if s.PayorAgencyID == s.PayeeAgencyID {
-		return fmt.Errorf("payorAgencyID and payeeAgencyID must be different")
+	payorErr := ValidateAgencyID("payorAgencyID", s.PayorAgencyID)
+	payeeErr := ValidateAgencyID("payeeAgencyID", s.PayeeAgencyID)
+	errs.add(payorErr)
+	errs.add(payeeErr)
+	if payorErr == nil && payeeErr == nil && s.PayorAgencyID == s.PayeeAgencyID {
+		errs.addf("payeeAgencyID", "payorAgencyID and payeeAgencyID must be different")
 	}
 	if s.GrossAmount < 0 {
-		return fmt.Errorf("grossAmount must be >= 0, got %f", s.GrossAmount)
+		errs.addf("grossAmount", "grossAmount must be >= 0, got %f", s.GrossAmount)
 	}
 	if s.TotalFees < 0 {
-		return fmt.Errorf("totalFees must be >= 0, got %f", s.TotalFees)
+		errs.addf("totalFees", "totalFees must be >= 0, got %f", s.TotalFees)
 	}
 	if s.NetAmount < 0 {
-		return fmt.Errorf("netAmount must be >= 0, got %f", s.NetAmount)
+		errs.addf("netAmount", "netAmount must be >= 0, got %f", s.NetAmount)
+	}
+	if s.PaidAmount < 0 {
+		errs.addf("paidAmount", "paidAmount must be >= 0, got %f", s.PaidAmount)
+	} else if s.NetAmount >= 0 && toCents(s.PaidAmount) > toCents(s.NetAmount) {
+		errs.addf("paidAmount", "paidAmount %.2f must not exceed netAmount %.2f", s.PaidAmount, s.NetAmount)
 	}
 	if s.ChargeCount < 0 {
-		return fmt.Errorf("chargeCount must be >= 0, got %d", s.ChargeCount)
+		errs.addf("chargeCount", "chargeCount must be >= 0, got %d", s.ChargeCount)
 	}
 	if s.CorrectionCount < 0 {
-		return fmt.Errorf("correctionCount must be >= 0, got %d", s.CorrectionCount)
+		errs.addf("correctionCount", "correctionCount must be >= 0, got %d", s.CorrectionCount)
+	}
+	for i, id := range s.ChargeIDs {
+		if id == "" {
+			errs.addf(fmt.Sprintf("chargeIDs[%d]", i), "chargeIDs[%d] must not be empty", i)
+		}
+	}
+	if len(s.ChargeIDs) > 0 && s.ChargeCount >= 0 && s.ChargeCount != len(s.ChargeIDs) {
+		errs.addf("chargeCount", "chargeCount mismatch: chargeCount is %d but %d chargeIDs are listed", s.ChargeCount, len(s.ChargeIDs))
+	}
+	for i, id := range s.CorrectionIDs {
+		if id == "" {
+			errs.addf(fmt.Sprintf("correctionIDs[%d]", i), "correctionIDs[%d] must not be empty", i)
+		}
+	}
+	if len(s.CorrectionIDs) > 0 && s.CorrectionCount >= 0 && s.CorrectionCount != len(s.CorrectionIDs) {
+		errs.addf("correctionCount", "correctionCount mismatch: correctionCount is %d but %d correctionIDs are listed", s.CorrectionCount, len(s.CorrectionIDs))
+	}
+	if s.ApprovalsRequired < 0 {
+		errs.addf("approvalsRequired", "approvalsRequired must be >= 0, got %d", s.ApprovalsRequired)
+	}
+	approvers := make(map[string]bool, len(s.Approvals))
+	for i, approval := range s.Approvals {
+		field := fmt.Sprintf("approvals[%d].mspID", i)
+		if approval.MSPID == "" {
+			errs.addf(field, "%s is required", field)
+		} else if approvers[approval.MSPID] {
+			errs.addf(field, "duplicate approval from %s", approval.MSPID)
+		}
+		approvers[approval.MSPID] = true
 	}
 	if s.Status == "" {
-		return fmt.Errorf("status is required")
+		errs.addf("status", "status is required")
+	} else if !settlementStatusSet.Has(s.Status) {
+		errs.addf("status", "invalid status %q: must be one of %v", s.Status, ValidSettlementStatuses)
 	}
-	if !contains(ValidSettlementStatuses, s.Status) {
-		return fmt.Errorf("invalid status %q: must be one of %v", s.Status, ValidSettlementStatuses)
+	if len(errs) == 0 {
+		return nil
 	}
-	return nil
+	return errs
 }
 
 // ValidateStatusTransition checks whether a settlement status change is allowed.
@@ -84,7 +183,7 @@ func (s *Settlement) Validate() error {
 //   - accepted -> paid
 //   - disputed -> submitted, accepted
 func (s *Settlement) ValidateStatusTransition(newStatus string) error {
-	if !contains(ValidSettlementStatuses, newStatus) {
+	if !settlementStatusSet.Has(newStatus) {
 		return fmt.Errorf("invalid target status %q: must be one of %v", newStatus, ValidSettlementStatuses)
 	}
 	if s.Status == newStatus {
@@ -92,7 +191,7 @@ func (s *Settlement) ValidateStatusTransition(newStatus string) error {
 	}
 
 	allowed := map[string][]string{
-		"draft":    {"submitted"},
+		"draft":     {"submitted"},
 		"submitted": {"accepted", "disputed"},
 		"accepted":  {"paid"},
 		"disputed":  {"submitted", "accepted"},
@@ -102,29 +201,104 @@ func (s *Settlement) ValidateStatusTransition(newStatus string) error {
 	if !ok {
 		return fmt.Errorf("no transitions allowed from status %q", s.Status)
 	}
-	if !contains(transitions, newStatus) {
+	if !Contains(transitions, newStatus) {
 		return fmt.Errorf("cannot transition settlement from %q to %q", s.Status, newStatus)
 	}
+	if s.Status == "draft" && !s.ApprovalsMet() {
+		return fmt.Errorf("settlement needs %d approvals before submission, has %d", s.ApprovalsRequired, len(s.Approvals))
+	}
+	return nil
+}
+
+// Approve records an approval by the MSP mspID. Only draft settlements can
+// be approved, and each MSP may approve once.
+func (s *Settlement) Approve(mspID string) error {
+	if mspID == "" {
+		return fmt.Errorf("approver MSP ID is required")
+	}
+	if s.Status != "draft" {
+		return fmt.Errorf("only draft settlements can be approved, settlement %s is %q", s.SettlementID, s.Status)
+	}
+	for _, approval := range s.Approvals {
+		if approval.MSPID == mspID {
+			return fmt.Errorf("settlement %s has already been approved by %s", s.SettlementID, mspID)
+		}
+	}
+	s.Approvals = append(s.Approvals, Approval{
+		MSPID:      mspID,
+		ApprovedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// ApprovalsMet returns true once the settlement has at least
+// ApprovalsRequired approvals.
+func (s *Settlement) ApprovalsMet() bool {
+	return len(s.Approvals) >= s.ApprovalsRequired
+}
+
+// SetStatus moves the settlement to status and appends the change to
+// StatusHistory.
+func (s *Settlement) SetStatus(status string) {
+	s.Status = status
+	s.StatusHistory = append(s.StatusHistory, StatusChange{
+		Status:    status,
+		ChangedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// RecordPayment adds an installment of amount to PaidAmount. The payment
+// must be positive and must not take PaidAmount past NetAmount. Amounts are
+// compared to the cent.
+func (s *Settlement) RecordPayment(amount float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("payment amount must be > 0, got %f", amount)
+	}
+	paid := toCents(s.PaidAmount) + toCents(amount)
+	if paid > toCents(s.NetAmount) {
+		return fmt.Errorf("payment of %.2f would exceed netAmount: %.2f of %.2f already paid", amount, s.PaidAmount, s.NetAmount)
+	}
+	s.PaidAmount = float64(paid) / 100
 	return nil
 }
 
+// FullyPaid returns true once PaidAmount covers NetAmount.
+func (s *Settlement) FullyPaid() bool {
+	return toCents(s.PaidAmount) >= toCents(s.NetAmount)
+}
+
+// IsNetZero returns true if the settlement's net amount rounds to zero: the
+// agencies owe each other nothing for the period.
+func (s *Settlement) IsNetZero() bool {
+	return toCents(s.NetAmount) == 0
+}
+
+// toCents converts an amount to a whole number of cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// IncludesCharge returns true if the settlement covers the given charge.
+func (s *Settlement) IncludesCharge(chargeID string) bool {
+	return Contains(s.ChargeIDs, chargeID)
+}
+
 // Key returns the ledger key for this settlement.
 func (s *Settlement) Key() string {
 	return "SETTLEMENT_" + s.SettlementID
 }
 
-// SetCreatedAt sets CreatedAt to the current time and ensures DocType is set.
+// SetCreatedAt sets CreatedAt to the current time, ensures DocType and
+// SchemaVersion are set, and starts StatusHistory with the initial status.
 func (s *Settlement) SetCreatedAt() {
 	s.DocType = "settlement"
+	s.SchemaVersion = CurrentSchemaVersion
 	s.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	s.StatusHistory = []StatusChange{{Status: s.Status, ChangedAt: s.CreatedAt}}
 }
 
 // CollectionName returns the private data collection name for this settlement.
 // Settlements are stored in bilateral collections.
 func (s *Settlement) CollectionName() string {
-	a, b := s.PayorAgencyID, s.PayeeAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	return "charges_" + a + "_" + b
+	return BilateralCollectionName(s.PayorAgencyID, s.PayeeAgencyID)
 }
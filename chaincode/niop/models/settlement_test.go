@@ -121,6 +121,66 @@ func TestSettlement_Validate_PeriodEndBeforeStart(t *testing.T) {
 	assert.Contains(t, err.Error(), "must not be before")
 }
 
+func TestSettlement_Validate_PeriodDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		wantErr string
+	}{
+		{"zero-padded", "2026-01-09", "2026-01-10", ""},
+		{"same day", "2026-01-15", "2026-01-15", ""},
+		{"reversed", "2026-01-10", "2026-01-09", `periodEnd "2026-01-09" must not be before periodStart "2026-01-10"`},
+		{"non-padded start", "2026-1-9", "2026-01-10", `invalid periodStart "2026-1-9"`},
+		{"non-padded end", "2026-01-09", "2026-1-10", `invalid periodEnd "2026-1-10"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validSettlement()
+			s.PeriodStart = tt.start
+			s.PeriodEnd = tt.end
+			err := s.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSettlement_Validate_PeriodFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		wantErr string
+	}{
+		{"timestamps", "2026-01-01T00:00:00Z", "2026-01-31T23:59:59Z", ""},
+		{"timestamps compared as times", "2026-01-01T10:00:00+02:00", "2026-01-01T09:00:00Z", ""},
+		{"timestamp end before start across offsets", "2026-01-31T23:00:00-08:00", "2026-02-01T05:00:00Z", "must not be before"},
+		{"date start with timestamp end", "2026-01-01", "2026-01-31T23:59:59Z", "periodStart and periodEnd must use the same date format"},
+		{"timestamp start with date end", "2026-01-01T00:00:00Z", "2026-01-31", "periodStart and periodEnd must use the same date format"},
+		{"unparseable start", "01/01/2026", "2026-01-31", "invalid periodStart"},
+		{"unparseable end", "2026-01-01", "2026-01-32", "invalid periodEnd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validSettlement()
+			s.PeriodStart = tt.start
+			s.PeriodEnd = tt.end
+			err := s.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestSettlement_Validate_NegativeValues(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -142,6 +202,16 @@ func TestSettlement_Validate_NegativeValues(t *testing.T) {
 			modify:  func(s *Settlement) { s.NetAmount = -1.0 },
 			wantErr: "netAmount must be >= 0",
 		},
+		{
+			name:    "negative paidAmount",
+			modify:  func(s *Settlement) { s.PaidAmount = -1.0 },
+			wantErr: "paidAmount must be >= 0",
+		},
+		{
+			name:    "paidAmount above netAmount",
+			modify:  func(s *Settlement) { s.PaidAmount = 14850.01 },
+			wantErr: "paidAmount 14850.01 must not exceed netAmount",
+		},
 		{
 			name:    "negative chargeCount",
 			modify:  func(s *Settlement) { s.ChargeCount = -1 },
@@ -204,6 +274,154 @@ func TestSettlement_ValidateStatusTransition(t *testing.T) {
 	}
 }
 
+func TestSettlement_RecordPayment(t *testing.T) {
+	t.Run("accumulates installments", func(t *testing.T) {
+		s := validSettlement()
+		s.NetAmount = 0.30
+		require.NoError(t, s.RecordPayment(0.10))
+		assert.False(t, s.FullyPaid())
+		require.NoError(t, s.RecordPayment(0.20))
+		assert.Equal(t, 0.30, s.PaidAmount)
+		assert.True(t, s.FullyPaid())
+	})
+
+	t.Run("rejects overpayment", func(t *testing.T) {
+		s := validSettlement()
+		require.NoError(t, s.RecordPayment(14000))
+		err := s.RecordPayment(850.01)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "would exceed netAmount")
+		assert.Equal(t, 14000.0, s.PaidAmount)
+	})
+
+	t.Run("rejects non-positive amounts", func(t *testing.T) {
+		s := validSettlement()
+		for _, amount := range []float64{0, -5} {
+			err := s.RecordPayment(amount)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "payment amount must be > 0")
+		}
+	})
+}
+
+func TestSettlement_Approve(t *testing.T) {
+	t.Run("records approvals until the requirement is met", func(t *testing.T) {
+		s := validSettlement()
+		s.ApprovalsRequired = 2
+		assert.False(t, s.ApprovalsMet())
+
+		require.NoError(t, s.Approve("ORG1MSP"))
+		assert.False(t, s.ApprovalsMet())
+		require.NoError(t, s.Approve("ORG3MSP"))
+		assert.True(t, s.ApprovalsMet())
+
+		require.Len(t, s.Approvals, 2)
+		assert.Equal(t, "ORG1MSP", s.Approvals[0].MSPID)
+		assert.NotEmpty(t, s.Approvals[0].ApprovedAt)
+		assert.NoError(t, s.Validate())
+	})
+
+	t.Run("rejects a second approval from the same MSP", func(t *testing.T) {
+		s := validSettlement()
+		require.NoError(t, s.Approve("ORG1MSP"))
+		err := s.Approve("ORG1MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already been approved by ORG1MSP")
+		assert.Len(t, s.Approvals, 1)
+	})
+
+	t.Run("rejects settlements past draft", func(t *testing.T) {
+		s := validSettlement()
+		s.Status = "submitted"
+		err := s.Approve("ORG1MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only draft settlements can be approved")
+	})
+
+	t.Run("gates submission on the required approvals", func(t *testing.T) {
+		s := validSettlement()
+		s.ApprovalsRequired = 1
+		err := s.ValidateStatusTransition("submitted")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs 1 approvals before submission, has 0")
+
+		require.NoError(t, s.Approve("ORG1MSP"))
+		assert.NoError(t, s.ValidateStatusTransition("submitted"))
+	})
+
+	t.Run("validates approvals", func(t *testing.T) {
+		s := validSettlement()
+		s.ApprovalsRequired = -1
+		assert.ErrorContains(t, s.Validate(), "approvalsRequired must be >= 0")
+
+		s = validSettlement()
+		s.Approvals = []Approval{{MSPID: "ORG1MSP"}, {MSPID: "ORG1MSP"}}
+		assert.ErrorContains(t, s.Validate(), "duplicate approval from ORG1MSP")
+
+		s = validSettlement()
+		s.Approvals = []Approval{{}}
+		assert.ErrorContains(t, s.Validate(), "approvals[0].mspID is required")
+	})
+}
+
+func TestSettlement_IncludesCharge(t *testing.T) {
+	s := validSettlement()
+	s.ChargeIDs = []string{"CHG-001", "CHG-002"}
+	assert.True(t, s.IncludesCharge("CHG-002"))
+	assert.False(t, s.IncludesCharge("CHG-003"))
+}
+
+func TestSettlement_Validate_EmptyChargeID(t *testing.T) {
+	s := validSettlement()
+	s.ChargeIDs = []string{"CHG-001", ""}
+	err := s.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chargeIDs[1] must not be empty")
+}
+
+func TestSettlement_Validate_LinkedIDCounts(t *testing.T) {
+	t.Run("matching counts", func(t *testing.T) {
+		s := validSettlement()
+		s.ChargeIDs = []string{"CHG-001", "CHG-002"}
+		s.ChargeCount = 2
+		s.CorrectionIDs = []string{"CORR-001"}
+		s.CorrectionCount = 1
+		assert.NoError(t, s.Validate())
+	})
+
+	t.Run("omitted arrays are not checked", func(t *testing.T) {
+		s := validSettlement()
+		s.ChargeIDs = nil
+		s.CorrectionIDs = nil
+		assert.NoError(t, s.Validate(), "chargeCount 3000 without chargeIDs is accepted")
+	})
+
+	t.Run("chargeCount mismatch", func(t *testing.T) {
+		s := validSettlement()
+		s.ChargeIDs = []string{"CHG-001", "CHG-002"}
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "chargeCount mismatch: chargeCount is 3000 but 2 chargeIDs are listed")
+	})
+
+	t.Run("correctionCount mismatch", func(t *testing.T) {
+		s := validSettlement()
+		s.CorrectionIDs = []string{"CORR-001"}
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correctionCount mismatch: correctionCount is 15 but 1 correctionIDs are listed")
+	})
+
+	t.Run("empty correctionID", func(t *testing.T) {
+		s := validSettlement()
+		s.CorrectionIDs = []string{""}
+		s.CorrectionCount = 1
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correctionIDs[0] must not be empty")
+	})
+}
+
 func TestSettlement_Key(t *testing.T) {
 	s := Settlement{SettlementID: "SETTLE-001"}
 	assert.Equal(t, "SETTLEMENT_SETTLE-001", s.Key())
@@ -216,6 +434,19 @@ func TestSettlement_SetCreatedAt(t *testing.T) {
 	s.SetCreatedAt()
 	assert.NotEmpty(t, s.CreatedAt)
 	assert.Equal(t, "settlement", s.DocType)
+	require.Len(t, s.StatusHistory, 1)
+	assert.Equal(t, StatusChange{Status: "draft", ChangedAt: s.CreatedAt}, s.StatusHistory[0])
+}
+
+func TestSettlement_SetStatus(t *testing.T) {
+	s := validSettlement()
+	s.SetCreatedAt()
+	s.SetStatus("submitted")
+
+	assert.Equal(t, "submitted", s.Status)
+	require.Len(t, s.StatusHistory, 2)
+	assert.Equal(t, "submitted", s.StatusHistory[1].Status)
+	assert.NotEmpty(t, s.StatusHistory[1].ChangedAt)
 }
 
 func TestSettlement_CollectionName(t *testing.T) {
@@ -249,3 +480,35 @@ func TestSettlement_Validate_ZeroAmounts(t *testing.T) {
 	s.CorrectionCount = 0
 	assert.NoError(t, s.Validate())
 }
+
+func TestSettlement_Validate_NetZeroWithFees(t *testing.T) {
+	s := validSettlement()
+	s.GrossAmount = 150.00
+	s.TotalFees = 150.00
+	s.NetAmount = 0
+	assert.NoError(t, s.Validate())
+}
+
+func TestSettlement_IsNetZero(t *testing.T) {
+	s := validSettlement()
+	assert.False(t, s.IsNetZero())
+
+	s.NetAmount = 0.001
+	assert.True(t, s.IsNetZero(), "amounts round to the cent")
+}
+
+func TestSettlement_ValidateAll(t *testing.T) {
+	s := validSettlement()
+	assert.Nil(t, s.ValidateAll())
+
+	s.PeriodStart = ""
+	s.GrossAmount = -1
+	s.Status = "closed"
+	errs := s.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"periodStart", "grossAmount", "status"}, fields)
+	assert.EqualError(t, s.Validate(), errs[0].Message)
+}
@@ -0,0 +1,58 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SubmissionSequence records the last sequence number a receiving agency
+// acknowledged on a submission from a submitting agency. Each agency numbers
+// the submissions it sends another agency 1, 2, 3 and so on; a number that
+// repeats or skips one is answered with return code "03" (sequence number
+// error). Sequences are stored in world state, one per direction of a pair.
+type SubmissionSequence struct {
+	DocType            string `json:"docType"`
+	SchemaVersion      int    `json:"schemaVersion"`
+	SubmitterAgencyID  string `json:"submitterAgencyID"`
+	ReceiverAgencyID   string `json:"receiverAgencyID"`
+	LastSequenceNumber int    `json:"lastSequenceNumber"`
+	UpdatedAt          string `json:"updatedAt"`
+}
+
+// Next returns the sequence number the next submission must carry.
+func (s *SubmissionSequence) Next() int {
+	return s.LastSequenceNumber + 1
+}
+
+// Check returns an error describing why sequenceNumber cannot follow the
+// last one acknowledged, or nil if it is the next in order.
+func (s *SubmissionSequence) Check(sequenceNumber int) error {
+	switch {
+	case sequenceNumber <= s.LastSequenceNumber:
+		return fmt.Errorf("sequence number %d repeats: last acknowledged is %d, expected %d", sequenceNumber, s.LastSequenceNumber, s.Next())
+	case sequenceNumber > s.Next():
+		return fmt.Errorf("sequence number %d skips ahead: expected %d", sequenceNumber, s.Next())
+	}
+	return nil
+}
+
+// Key returns the ledger key for this sequence.
+func (s *SubmissionSequence) Key() string {
+	return SubmissionSequenceKey(s.SubmitterAgencyID, s.ReceiverAgencyID)
+}
+
+// SubmissionSequenceKey returns the ledger key of the sequence of
+// submissions from submitterAgencyID to receiverAgencyID.
+func SubmissionSequenceKey(submitterAgencyID string, receiverAgencyID string) string {
+	return "SUBMISSIONSEQ_" + submitterAgencyID + "_" + receiverAgencyID
+}
+
+// TouchUpdatedAt sets UpdatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
+func (s *SubmissionSequence) TouchUpdatedAt() {
+	s.DocType = "submissionsequence"
+	s.SchemaVersion = CurrentSchemaVersion
+	s.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+}
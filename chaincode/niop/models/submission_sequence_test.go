@@ -0,0 +1,30 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionSequence_Check(t *testing.T) {
+	s := SubmissionSequence{SubmitterAgencyID: "ORG1", ReceiverAgencyID: "ORG2", LastSequenceNumber: 4}
+	assert.Equal(t, 5, s.Next())
+	assert.NoError(t, s.Check(5))
+
+	err := s.Check(4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sequence number 4 repeats")
+
+	err = s.Check(7)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sequence number 7 skips ahead: expected 5")
+}
+
+func TestSubmissionSequenceKey(t *testing.T) {
+	s := SubmissionSequence{SubmitterAgencyID: "ORG1", ReceiverAgencyID: "ORG2"}
+	assert.Equal(t, "SUBMISSIONSEQ_ORG1_ORG2", s.Key())
+	assert.Equal(t, "submissionsequence", DocTypeForKey(s.Key()))
+}
@@ -8,12 +8,74 @@ import (
 )
 
 // DiscountPlan represents a discount plan associated with a tag.
+// StartDate and EndDate are RFC3339 dates ("2026-01-01") or timestamps
+// ("2026-01-01T00:00:00Z"); an empty EndDate means the plan is open-ended.
 type DiscountPlan struct {
 	Type      string `json:"type"`
 	StartDate string `json:"startDate"`
 	EndDate   string `json:"endDate,omitempty"`
 }
 
+// Valid discount plan types. Tags, charges and reconciliations share this
+// list so discounts can be reported consistently.
+var ValidDiscountPlanTypes = []string{"commuter", "carpool", "resident", "senior", "disability", "veteran", "low_income", "fleet", "frequent", "employee"}
+
+// ValidateDiscountPlanType checks an optional discount plan type against
+// ValidDiscountPlanTypes. An empty value is valid. field names the value in
+// the returned ValidationError.
+func ValidateDiscountPlanType(field string, planType string) error {
+	if planType != "" && !discountPlanTypeSet.Has(planType) {
+		return fieldError(field, "invalid %s %q: must be one of %v", field, planType, ValidDiscountPlanTypes)
+	}
+	return nil
+}
+
+// Validate checks all fields of a DiscountPlan and returns a
+// *ValidationError describing the first validation failure, or nil if valid.
+func (d *DiscountPlan) Validate() error {
+	return ValidationErrors(d.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a DiscountPlan and returns every
+// validation failure in the order Validate checks them, or nil if valid.
+func (d *DiscountPlan) ValidateAll() []ValidationError {
+	var errs ValidationErrors
+	if d.Type == "" {
+		errs.addf("type", "type is required")
+	} else {
+		errs.add(ValidateDiscountPlanType("type", d.Type))
+	}
+	var start time.Time
+	startValid := false
+	if d.StartDate == "" {
+		errs.addf("startDate", "startDate is required")
+	} else if parsed, err := parsePlanDate(d.StartDate); err != nil {
+		errs.addf("startDate", "invalid startDate %q: must be an RFC3339 date or timestamp", d.StartDate)
+	} else {
+		start, startValid = parsed, true
+	}
+	if d.EndDate != "" {
+		end, err := parsePlanDate(d.EndDate)
+		if err != nil {
+			errs.addf("endDate", "invalid endDate %q: must be an RFC3339 date or timestamp", d.EndDate)
+		} else if startValid && end.Before(start) {
+			errs.addf("endDate", "endDate %q must not be before startDate %q", d.EndDate, d.StartDate)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// parsePlanDate parses an RFC3339 full-date or date-time.
+func parsePlanDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 // Plate represents a license plate associated with a tag.
 type Plate struct {
 	Country       string `json:"country"`
@@ -28,6 +90,7 @@ type Plate struct {
 // Tags are the primary identifier for electronic toll collection.
 type Tag struct {
 	DocType         string         `json:"docType"`
+	SchemaVersion   int            `json:"schemaVersion"`
 	TagSerialNumber string         `json:"tagSerialNumber"`
 	TagAgencyID     string         `json:"tagAgencyID"`
 	HomeAgencyID    string         `json:"homeAgencyID"`
@@ -38,55 +101,69 @@ type Tag struct {
 	TagProtocol     string         `json:"tagProtocol"`
 	DiscountPlans   []DiscountPlan `json:"discountPlans,omitempty"`
 	Plates          []Plate        `json:"plates,omitempty"`
+	StatusNote      string         `json:"statusNote,omitempty"`
 	UpdatedAt       string         `json:"updatedAt"`
 }
 
 // Valid tag statuses.
 var ValidTagStatuses = []string{"valid", "invalid", "inactive", "lost", "stolen"}
 
+// CompromisedTagStatuses are the statuses of a tag that is no longer in its
+// owner's possession.
+var CompromisedTagStatuses = []string{"lost", "stolen"}
+
 // Valid tag types.
 var ValidTagTypes = []string{"single", "loaded", "flex", "generic"}
 
 // Valid tag protocols.
 var ValidTagProtocols = []string{"sego", "6c", "tdm"}
 
-// Validate checks all fields of a Tag and returns an error describing
-// the first validation failure, or nil if the tag is valid.
+// Validate checks all fields of a Tag and returns a *ValidationError
+// describing the first validation failure, or nil if the tag is valid.
 func (t *Tag) Validate() error {
+	return ValidationErrors(t.ValidateAll()).first()
+}
+
+// ValidateAll checks all fields of a Tag and returns every validation
+// failure in the order Validate checks them, or nil if the tag is valid.
+// Failures within a discount plan are reported against
+// discountPlans[i].field.
+func (t *Tag) ValidateAll() []ValidationError {
+	var errs ValidationErrors
 	if t.TagSerialNumber == "" {
-		return fmt.Errorf("tagSerialNumber is required")
-	}
-	if t.TagAgencyID == "" {
-		return fmt.Errorf("tagAgencyID is required")
-	}
-	if t.HomeAgencyID == "" {
-		return fmt.Errorf("homeAgencyID is required")
+		errs.addf("tagSerialNumber", "tagSerialNumber is required")
 	}
+	errs.add(ValidateAgencyID("tagAgencyID", t.TagAgencyID))
+	errs.add(ValidateAgencyID("homeAgencyID", t.HomeAgencyID))
 	if t.AccountID == "" {
-		return fmt.Errorf("accountID is required")
+		errs.addf("accountID", "accountID is required")
 	}
 	if t.TagStatus == "" {
-		return fmt.Errorf("tagStatus is required")
-	}
-	if !contains(ValidTagStatuses, t.TagStatus) {
-		return fmt.Errorf("invalid tagStatus %q: must be one of %v", t.TagStatus, ValidTagStatuses)
+		errs.addf("tagStatus", "tagStatus is required")
+	} else if !tagStatusSet.Has(t.TagStatus) {
+		errs.addf("tagStatus", "invalid tagStatus %q: must be one of %v", t.TagStatus, ValidTagStatuses)
 	}
 	if t.TagType == "" {
-		return fmt.Errorf("tagType is required")
-	}
-	if !contains(ValidTagTypes, t.TagType) {
-		return fmt.Errorf("invalid tagType %q: must be one of %v", t.TagType, ValidTagTypes)
-	}
-	if t.TagClass < 1 {
-		return fmt.Errorf("tagClass must be >= 1, got %d", t.TagClass)
+		errs.addf("tagType", "tagType is required")
+	} else if !tagTypeSet.Has(t.TagType) {
+		errs.addf("tagType", "invalid tagType %q: must be one of %v", t.TagType, ValidTagTypes)
 	}
+	errs.add(ValidateVehicleClass("tagClass", t.TagClass))
 	if t.TagProtocol == "" {
-		return fmt.Errorf("tagProtocol is required")
+		errs.addf("tagProtocol", "tagProtocol is required")
+	} else if !tagProtocolSet.Has(t.TagProtocol) {
+		errs.addf("tagProtocol", "invalid tagProtocol %q: must be one of %v", t.TagProtocol, ValidTagProtocols)
 	}
-	if !contains(ValidTagProtocols, t.TagProtocol) {
-		return fmt.Errorf("invalid tagProtocol %q: must be one of %v", t.TagProtocol, ValidTagProtocols)
+	for i := range t.DiscountPlans {
+		prefix := fmt.Sprintf("discountPlans[%d]", i)
+		for _, e := range t.DiscountPlans[i].ValidateAll() {
+			errs.addf(prefix+"."+e.Field, "%s: %s", prefix, e.Message)
+		}
 	}
-	return nil
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
 }
 
 // ValidateStatusTransition checks whether a status change is allowed.
@@ -97,7 +174,7 @@ func (t *Tag) Validate() error {
 //   - lost -> valid, invalid
 //   - stolen -> valid, invalid
 func (t *Tag) ValidateStatusTransition(newStatus string) error {
-	if !contains(ValidTagStatuses, newStatus) {
+	if !tagStatusSet.Has(newStatus) {
 		return fmt.Errorf("invalid target tagStatus %q: must be one of %v", newStatus, ValidTagStatuses)
 	}
 	if t.TagStatus == newStatus {
@@ -116,7 +193,7 @@ func (t *Tag) ValidateStatusTransition(newStatus string) error {
 	if !ok {
 		return fmt.Errorf("unknown current status %q", t.TagStatus)
 	}
-	if !contains(transitions, newStatus) {
+	if !Contains(transitions, newStatus) {
 		return fmt.Errorf("cannot transition from %q to %q", t.TagStatus, newStatus)
 	}
 	return nil
@@ -127,8 +204,10 @@ func (t *Tag) Key() string {
 	return "TAG_" + t.TagSerialNumber
 }
 
-// TouchUpdatedAt sets UpdatedAt to the current time and ensures DocType is set.
+// TouchUpdatedAt sets UpdatedAt to the current time and ensures DocType and
+// SchemaVersion are set.
 func (t *Tag) TouchUpdatedAt() {
 	t.DocType = "tag"
+	t.SchemaVersion = CurrentSchemaVersion
 	t.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 }
@@ -91,6 +91,11 @@ func TestTag_Validate_RequiredFields(t *testing.T) {
 			modify:  func(tag *Tag) { tag.TagClass = -1 },
 			wantErr: "tagClass must be >= 1",
 		},
+		{
+			name:    "tagClass unknown",
+			modify:  func(tag *Tag) { tag.TagClass = 99 },
+			wantErr: "unknown tagClass 99",
+		},
 	}
 
 	for _, tt := range tests {
@@ -250,3 +255,78 @@ func TestTag_Validate_AllProtocols(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDiscountPlanType(t *testing.T) {
+	for _, planType := range ValidDiscountPlanTypes {
+		assert.NoError(t, ValidateDiscountPlanType("discountPlanType", planType))
+	}
+	assert.NoError(t, ValidateDiscountPlanType("discountPlanType", ""), "empty means no plan")
+
+	err := ValidateDiscountPlanType("discountPlanType", "vip")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid discountPlanType "vip"`)
+}
+
+func TestDiscountPlan_Validate(t *testing.T) {
+	valid := []struct {
+		name string
+		plan DiscountPlan
+	}{
+		{"open-ended", DiscountPlan{Type: "commuter", StartDate: "2026-01-01"}},
+		{"date range", DiscountPlan{Type: "carpool", StartDate: "2026-01-01", EndDate: "2026-12-31"}},
+		{"single day", DiscountPlan{Type: "resident", StartDate: "2026-01-01", EndDate: "2026-01-01"}},
+		{"timestamps", DiscountPlan{Type: "senior", StartDate: "2026-01-01T00:00:00Z", EndDate: "2026-06-30T23:59:59Z"}},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.plan.Validate())
+		})
+	}
+
+	invalid := []struct {
+		name    string
+		plan    DiscountPlan
+		wantErr string
+	}{
+		{"missing type", DiscountPlan{StartDate: "2026-01-01"}, "type is required"},
+		{"unknown type", DiscountPlan{Type: "platinum", StartDate: "2026-01-01"}, "invalid type \"platinum\""},
+		{"missing start", DiscountPlan{Type: "commuter"}, "startDate is required"},
+		{"malformed start", DiscountPlan{Type: "commuter", StartDate: "01/01/2026"}, "invalid startDate"},
+		{"malformed end", DiscountPlan{Type: "commuter", StartDate: "2026-01-01", EndDate: "soon"}, "invalid endDate"},
+		{"reversed dates", DiscountPlan{Type: "commuter", StartDate: "2026-06-01", EndDate: "2026-01-01"}, "must not be before startDate"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plan.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTag_Validate_DiscountPlans(t *testing.T) {
+	tag := validTag()
+	tag.DiscountPlans = []DiscountPlan{
+		{Type: "commuter", StartDate: "2026-01-01"},
+		{Type: "carpool", StartDate: "2026-06-01", EndDate: "2026-01-01"},
+	}
+	err := tag.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "discountPlans[1]: endDate")
+}
+
+func TestTag_ValidateAll(t *testing.T) {
+	tag := validTag()
+	assert.Nil(t, tag.ValidateAll())
+
+	tag.AccountID = ""
+	tag.TagType = "huge"
+	tag.DiscountPlans = []DiscountPlan{{Type: "commuter"}}
+	errs := tag.ValidateAll()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"accountID", "tagType", "discountPlans[0].startDate"}, fields)
+	assert.EqualError(t, tag.Validate(), errs[0].Message)
+}
@@ -0,0 +1,81 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes one invalid field of an entity. Field is the
+// field's JSON name, with a dotted path for nested fields
+// ("feeBreakdown.flatFee"), so a client can point at it. Message is the full
+// description and is the error text. Validate methods return a
+// *ValidationError for the first failure; ValidateAll methods return every
+// failure.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error returns the message.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// fieldError returns a ValidationError for field with a formatted message.
+func fieldError(field string, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidationErrors is a list of validation failures in the order the fields
+// were checked. ValidateAll methods collect into it, and it is returned as a
+// single error when every failure is reported at once. errors.As finds its
+// first ValidationError.
+type ValidationErrors []ValidationError
+
+// Error joins the failures' messages with "; ".
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns each failure as an error.
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i := range v {
+		errs[i] = &v[i]
+	}
+	return errs
+}
+
+// addf records a failure of field with a formatted message.
+func (v *ValidationErrors) addf(field string, format string, args ...interface{}) {
+	*v = append(*v, *fieldError(field, format, args...))
+}
+
+// add records err if it is not nil. Errors that are not a *ValidationError
+// are recorded without a field.
+func (v *ValidationErrors) add(err error) {
+	if err == nil {
+		return
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		*v = append(*v, *ve)
+		return
+	}
+	*v = append(*v, ValidationError{Message: err.Error()})
+}
+
+// first returns the first failure, or nil if there were none.
+func (v ValidationErrors) first() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &v[0]
+}
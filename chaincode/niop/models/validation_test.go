@@ -0,0 +1,34 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrors(t *testing.T) {
+	errs := ValidationErrors{
+		{Field: "chargeID", Message: "chargeID is required"},
+		{Field: "amount", Message: "amount must be >= 0, got -1.000000"},
+	}
+
+	t.Run("joins messages", func(t *testing.T) {
+		assert.Equal(t, "chargeID is required; amount must be >= 0, got -1.000000", errs.Error())
+	})
+
+	t.Run("errors.As finds the first failure through wrapping", func(t *testing.T) {
+		wrapped := fmt.Errorf("validation failed: %w", errs)
+		var ve *ValidationError
+		require.True(t, errors.As(wrapped, &ve))
+		assert.Equal(t, "chargeID", ve.Field)
+
+		var all ValidationErrors
+		require.True(t, errors.As(wrapped, &all))
+		assert.Len(t, all, 2)
+	})
+}
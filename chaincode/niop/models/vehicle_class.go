@@ -0,0 +1,103 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VehicleClass describes one vehicle class used for charges and tags.
+type VehicleClass struct {
+	Description string `json:"description"`
+	Axles       int    `json:"axles"`
+}
+
+// VehicleClasses maps each known vehicle class number to its description.
+// Charges and tags must use a class listed here. Replace the set with
+// SetVehicleClasses during chaincode startup, not while serving transactions.
+var VehicleClasses = map[int]VehicleClass{
+	1: {Description: "Motorcycle", Axles: 2},
+	2: {Description: "Two-axle passenger vehicle", Axles: 2},
+	3: {Description: "Three-axle vehicle", Axles: 3},
+	4: {Description: "Four-axle vehicle", Axles: 4},
+	5: {Description: "Five-axle vehicle", Axles: 5},
+	6: {Description: "Six-axle vehicle", Axles: 6},
+	7: {Description: "Seven or more axles", Axles: 7},
+}
+
+// SetVehicleClasses replaces VehicleClasses after checking that the set is
+// non-empty and every class has a number >= 1, a description and at least
+// one axle.
+func SetVehicleClasses(classes map[int]VehicleClass) error {
+	if len(classes) == 0 {
+		return fmt.Errorf("vehicle classes must not be empty")
+	}
+	for class, vc := range classes {
+		if class < 1 {
+			return fmt.Errorf("vehicle class must be >= 1, got %d", class)
+		}
+		if vc.Description == "" {
+			return fmt.Errorf("vehicle class %d: description is required", class)
+		}
+		if vc.Axles < 1 {
+			return fmt.Errorf("vehicle class %d: axles must be >= 1, got %d", class, vc.Axles)
+		}
+	}
+	VehicleClasses = classes
+	return nil
+}
+
+// VehicleClassRemaps lists, by tag class, the other vehicle classes a charge
+// against a tag of that class may be assessed at, such as a class 2 tag on a
+// car towing a trailer and charged as class 3. A charge's class must equal
+// its tag's class or be listed here (see Charge.ValidateTagClass). Replace it
+// with SetVehicleClassRemaps during chaincode startup.
+var VehicleClassRemaps = map[int][]int{}
+
+// SetVehicleClassRemaps replaces VehicleClassRemaps after checking that every
+// tag class and every class it remaps to is one of VehicleClasses.
+func SetVehicleClassRemaps(remaps map[int][]int) error {
+	for tagClass, classes := range remaps {
+		if err := ValidateVehicleClass("tag class", tagClass); err != nil {
+			return err
+		}
+		for _, class := range classes {
+			if err := ValidateVehicleClass("vehicle class", class); err != nil {
+				return fmt.Errorf("tag class %d: %w", tagClass, err)
+			}
+		}
+	}
+	VehicleClassRemaps = remaps
+	return nil
+}
+
+// LookupVehicleClass returns a vehicle class's description and axle count,
+// and whether the class is known. Summaries and reports use it to label
+// per-class figures.
+func LookupVehicleClass(class int) (VehicleClass, bool) {
+	vc, ok := VehicleClasses[class]
+	return vc, ok
+}
+
+// VehicleClassNumbers returns the known vehicle class numbers in ascending order.
+func VehicleClassNumbers() []int {
+	classes := make([]int, 0, len(VehicleClasses))
+	for class := range VehicleClasses {
+		classes = append(classes, class)
+	}
+	sort.Ints(classes)
+	return classes
+}
+
+// ValidateVehicleClass checks that class is one of VehicleClasses. field
+// names the class in the returned ValidationError.
+func ValidateVehicleClass(field string, class int) error {
+	if class < 1 {
+		return fieldError(field, "%s must be >= 1, got %d", field, class)
+	}
+	if _, ok := VehicleClasses[class]; !ok {
+		return fieldError(field, "unknown %s %d: must be one of %v", field, class, VehicleClassNumbers())
+	}
+	return nil
+}
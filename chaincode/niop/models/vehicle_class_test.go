@@ -0,0 +1,93 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVehicleClass(t *testing.T) {
+	for _, class := range VehicleClassNumbers() {
+		assert.NoError(t, ValidateVehicleClass("vehicleClass", class))
+	}
+
+	tests := []struct {
+		class   int
+		wantErr string
+	}{
+		{0, "vehicleClass must be >= 1, got 0"},
+		{-1, "vehicleClass must be >= 1, got -1"},
+		{8, "unknown vehicleClass 8"},
+		{99, "unknown vehicleClass 99"},
+	}
+	for _, tt := range tests {
+		err := ValidateVehicleClass("vehicleClass", tt.class)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+}
+
+func TestLookupVehicleClass(t *testing.T) {
+	vc, ok := LookupVehicleClass(5)
+	require.True(t, ok)
+	assert.Equal(t, "Five-axle vehicle", vc.Description)
+	assert.Equal(t, 5, vc.Axles)
+
+	_, ok = LookupVehicleClass(99)
+	assert.False(t, ok)
+}
+
+func TestSetVehicleClasses(t *testing.T) {
+	original := VehicleClasses
+	t.Cleanup(func() { VehicleClasses = original })
+
+	t.Run("replaces the set", func(t *testing.T) {
+		require.NoError(t, SetVehicleClasses(map[int]VehicleClass{
+			1: {Description: "Car", Axles: 2},
+			9: {Description: "Oversize", Axles: 9},
+		}))
+		assert.Equal(t, []int{1, 9}, VehicleClassNumbers())
+		assert.NoError(t, ValidateVehicleClass("vehicleClass", 9))
+		assert.Error(t, ValidateVehicleClass("vehicleClass", 2))
+	})
+
+	tests := []struct {
+		name    string
+		classes map[int]VehicleClass
+		wantErr string
+	}{
+		{"empty", map[int]VehicleClass{}, "must not be empty"},
+		{"class zero", map[int]VehicleClass{0: {Description: "None", Axles: 2}}, "must be >= 1"},
+		{"missing description", map[int]VehicleClass{1: {Axles: 2}}, "description is required"},
+		{"no axles", map[int]VehicleClass{1: {Description: "Car"}}, "axles must be >= 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := VehicleClasses
+			err := SetVehicleClasses(tt.classes)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, before, VehicleClasses, "invalid set must not be applied")
+		})
+	}
+}
+
+func TestSetVehicleClassRemaps(t *testing.T) {
+	original := VehicleClassRemaps
+	t.Cleanup(func() { VehicleClassRemaps = original })
+
+	require.NoError(t, SetVehicleClassRemaps(map[int][]int{2: {3, 4}}))
+	assert.Equal(t, []int{3, 4}, VehicleClassRemaps[2])
+
+	err := SetVehicleClassRemaps(map[int][]int{9: {2}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown tag class 9")
+
+	err = SetVehicleClassRemaps(map[int][]int{2: {9}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tag class 2: unknown vehicle class 9")
+	assert.Equal(t, []int{3, 4}, VehicleClassRemaps[2], "invalid remaps must not be applied")
+}
@@ -0,0 +1,49 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// Version is the chaincode version reported by Ping and GetChaincodeInfo.
+// cmd/main.go sets it from its build-time version.
+var Version = "dev"
+
+// PingContract reports liveness and build information for monitoring.
+// It reads and writes nothing.
+type PingContract struct {
+	contractapi.Contract
+}
+
+// Ping returns "pong" followed by the chaincode version and the transaction
+// timestamp, confirming the chaincode is installed and responding.
+func (c *PingContract) Ping(ctx contractapi.TransactionContextInterface) (string, error) {
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("pong %s %s", Version, now.Format(time.RFC3339)), nil
+}
+
+// ChaincodeInfo describes the running chaincode build.
+type ChaincodeInfo struct {
+	Version   string   `json:"version"`
+	Protocols []string `json:"protocols"`
+	Contracts []string `json:"contracts"`
+}
+
+// GetChaincodeInfo returns the chaincode version, the interoperability
+// protocols it supports, and the names of its registered contracts, so
+// operators can tell which build each peer is running.
+func (c *PingContract) GetChaincodeInfo(ctx contractapi.TransactionContextInterface) (*ChaincodeInfo, error) {
+	return &ChaincodeInfo{
+		Version:   Version,
+		Protocols: models.ValidProtocols,
+		Contracts: ContractNames(),
+	}, nil
+}
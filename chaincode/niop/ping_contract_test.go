@@ -0,0 +1,38 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	ctx := newMockContext()
+
+	result, err := (&PingContract{}).Ping(ctx)
+	require.NoError(t, err)
+
+	fields := strings.Fields(result)
+	require.Len(t, fields, 3)
+	assert.Equal(t, "pong", fields[0])
+	assert.Equal(t, Version, fields[1])
+	assert.NotEmpty(t, fields[2])
+}
+
+func TestGetChaincodeInfo(t *testing.T) {
+	ctx := newMockContext()
+
+	info, err := (&PingContract{}).GetChaincodeInfo(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.Version)
+	assert.Equal(t, Version, info.Version)
+	assert.Equal(t, models.ValidProtocols, info.Protocols)
+	assert.Contains(t, info.Contracts, "ChargeContract")
+	assert.Contains(t, info.Contracts, "PingContract")
+	assert.Len(t, info.Contracts, len(Contracts()))
+}
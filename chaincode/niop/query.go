@@ -0,0 +1,88 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// richQuery is a CouchDB Mango query. Building queries from a struct rather
+// than a format string keeps selector values properly escaped.
+type richQuery struct {
+	Selector map[string]interface{} `json:"selector"`
+	Sort     []map[string]string    `json:"sort,omitempty"`
+	Limit    int                    `json:"limit,omitempty"`
+	Fields   []string               `json:"fields,omitempty"`
+}
+
+// newRichQuery returns a query matching documents of docType whose fields
+// equal the given values.
+func newRichQuery(docType string, fields map[string]interface{}) *richQuery {
+	selector := map[string]interface{}{"docType": docType}
+	for field, value := range fields {
+		selector[field] = value
+	}
+	return &richQuery{Selector: selector}
+}
+
+// sortAscending orders results by field. CouchDB only sorts on indexed
+// fields, so callers must check field against an allowlist first. The
+// field is also added to the selector, which CouchDB requires for sorting.
+func (q *richQuery) sortAscending(field string) *richQuery {
+	if field == "" {
+		return q
+	}
+	if _, ok := q.Selector[field]; !ok {
+		q.Selector[field] = map[string]interface{}{"$exists": true}
+	}
+	q.Sort = append(q.Sort, map[string]string{field: "asc"})
+	return q
+}
+
+// limit caps the number of results. Zero means no limit.
+func (q *richQuery) limit(n int) *richQuery {
+	q.Limit = n
+	return q
+}
+
+// onlyFields restricts each result to the named fields, for queries that
+// need little or nothing of the matched documents.
+func (q *richQuery) onlyFields(fields ...string) *richQuery {
+	q.Fields = append(q.Fields, fields...)
+	return q
+}
+
+// String renders the query as JSON.
+func (q *richQuery) String() (string, error) {
+	bytes, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("failed to build query: %w", err)
+	}
+	return string(bytes), nil
+}
+
+// validateSortAndLimit checks optional sort and limit parameters. An empty
+// sortField means unsorted; a zero limit means unlimited.
+func validateSortAndLimit(sortField string, allowed []string, limit int) error {
+	if sortField != "" && !models.Contains(allowed, sortField) {
+		return fmt.Errorf("invalid sortField %q: must be one of %v", sortField, allowed)
+	}
+	if limit < 0 {
+		return fmt.Errorf("limit must be >= 0, got %d", limit)
+	}
+	return nil
+}
+
+// maxPageSize caps the pageSize of paginated queries.
+const maxPageSize = 500
+
+// validatePageSize checks the pageSize of a paginated query.
+func validatePageSize(pageSize int) error {
+	if pageSize < 1 || pageSize > maxPageSize {
+		return fmt.Errorf("pageSize must be between 1 and %d, got %d", maxPageSize, pageSize)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichQuery_String(t *testing.T) {
+	t.Run("selector only", func(t *testing.T) {
+		query, err := newRichQuery("tag", map[string]interface{}{"tagAgencyID": "ORG1"}).String()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"selector":{"docType":"tag","tagAgencyID":"ORG1"}}`, query)
+	})
+
+	t.Run("sort and limit", func(t *testing.T) {
+		query, err := newRichQuery("charge", map[string]interface{}{"status": "posted"}).
+			sortAscending("amount").
+			limit(10).
+			String()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"selector":{"docType":"charge","status":"posted","amount":{"$exists":true}},"sort":[{"amount":"asc"}],"limit":10}`, query)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		query, err := newRichQuery("charge", map[string]interface{}{"facilityID": "SR73"}).
+			onlyFields("chargeID").
+			String()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"selector":{"docType":"charge","facilityID":"SR73"},"fields":["chargeID"]}`, query)
+	})
+
+	t.Run("escapes selector values", func(t *testing.T) {
+		query, err := newRichQuery("tag", map[string]interface{}{"tagAgencyID": `ORG1","docType":"charge`}).String()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"selector":{"docType":"tag","tagAgencyID":"ORG1\",\"docType\":\"charge"}}`, query)
+	})
+}
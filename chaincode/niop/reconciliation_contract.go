@@ -11,20 +11,26 @@ import (
 )
 
 // ReconciliationContract handles Reconciliation transactions on the ledger.
-// Reconciliations are stored in world state keyed by the charge ID they reference.
+// Reconciliations are stored in world state keyed by the charge ID they
+// reference, plus the correction sequence number for reconciliations of a
+// correction.
 type ReconciliationContract struct {
 	contractapi.Contract
 }
 
-// CreateReconciliation creates a new reconciliation record for a charge.
-// Returns an error if a reconciliation for this charge already exists.
+// CreateReconciliation creates a new reconciliation record for a charge, or
+// for one of its corrections when correctionSeqNo is set. Returns an error if
+// a reconciliation for the same charge or correction already exists. When
+// batchID is set, the batch must already exist, belong to the same agencies
+// and list the reconciliation. When Config.StrictMode is set, homeAgencyID
+// must name an agency on the ledger.
 func (c *ReconciliationContract) CreateReconciliation(ctx contractapi.TransactionContextInterface, reconciliationJSON string) error {
 	var recon models.Reconciliation
 	if err := json.Unmarshal([]byte(reconciliationJSON), &recon); err != nil {
 		return fmt.Errorf("failed to parse reconciliation JSON: %w", err)
 	}
 
-	if err := recon.Validate(); err != nil {
+	if err := validateModel(&recon); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -33,9 +39,52 @@ func (c *ReconciliationContract) CreateReconciliation(ctx contractapi.Transactio
 		return fmt.Errorf("failed to read state: %w", err)
 	}
 	if existing != nil {
+		if recon.IsCorrectionLevel() {
+			return fmt.Errorf("reconciliation for charge %s correction %d already exists", recon.ChargeID, recon.CorrectionSeqNo)
+		}
 		return fmt.Errorf("reconciliation for charge %s already exists", recon.ChargeID)
 	}
 
+	if CurrentConfig().StrictMode {
+		if _, err := (&AgencyContract{}).GetAgency(ctx, recon.HomeAgencyID); err != nil {
+			return fmt.Errorf("failed to load home agency: %w", err)
+		}
+	}
+
+	if recon.BatchID != "" {
+		batch, err := c.GetReconciliationBatch(ctx, recon.BatchID)
+		if err != nil {
+			return err
+		}
+		if batch.HomeAgencyID != recon.HomeAgencyID || (recon.AwayAgencyID != "" && batch.AwayAgencyID != recon.AwayAgencyID) {
+			return fmt.Errorf("reconciliation batch %s belongs to a different agency pair", recon.BatchID)
+		}
+		if !batch.Includes(recon.ReconciliationID) {
+			return fmt.Errorf("reconciliation %s is not listed in batch %s", recon.ReconciliationID, recon.BatchID)
+		}
+	}
+
+	// The mismatch flag is derived, never taken from the caller. It can only
+	// be computed when the away agency identifies the charge's collection.
+	// A correction is an adjustment rather than an amount owed, so
+	// reconciliations of a correction are only checked for its existence.
+	recon.AmountMismatch = false
+	var charge *models.Charge
+	if recon.AwayAgencyID != "" && recon.IsCorrectionLevel() {
+		if _, err := (&CorrectionContract{}).GetCorrection(ctx, recon.ChargeID, recon.CorrectionSeqNo, recon.AwayAgencyID, recon.HomeAgencyID); err != nil {
+			return fmt.Errorf("failed to load correction for reconciliation: %w", err)
+		}
+	} else if recon.AwayAgencyID != "" {
+		charge, err = (&ChargeContract{}).GetCharge(ctx, recon.ChargeID, recon.AwayAgencyID, recon.HomeAgencyID)
+		if err != nil {
+			return fmt.Errorf("failed to load charge for reconciliation: %w", err)
+		}
+		if err := recon.ValidateAgainstCharge(charge); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		recon.AmountMismatch = recon.HasAmountMismatch(charge)
+	}
+
 	recon.SetCreatedAt()
 
 	bytes, err := json.Marshal(recon)
@@ -43,28 +92,177 @@ func (c *ReconciliationContract) CreateReconciliation(ctx contractapi.Transactio
 		return fmt.Errorf("failed to marshal reconciliation: %w", err)
 	}
 
-	return ctx.GetStub().PutState(recon.Key(), bytes)
+	if err := ctx.GetStub().PutState(recon.Key(), bytes); err != nil {
+		return err
+	}
+
+	if recon.AmountMismatch && CurrentConfig().AutoDisputeOnAmountMismatch {
+		return disputeMismatchedCharge(ctx, charge)
+	}
+	return nil
 }
 
-// GetReconciliation retrieves a reconciliation by charge ID.
+// disputeMismatchedCharge moves a charge to "disputed" after its reconciliation
+// posted a different amount. Charges whose status cannot move to disputed
+// (for example, still pending) are left as they are; the reconciliation's
+// amountMismatch flag still records the discrepancy.
+func disputeMismatchedCharge(ctx contractapi.TransactionContextInterface, charge *models.Charge) error {
+	if charge.ValidateStatusTransition("disputed") != nil {
+		return nil
+	}
+
+	previousStatus := charge.Status
+	charge.Status = "disputed"
+
+	return putCharge(ctx, charge, previousStatus)
+}
+
+// GetReconciliation retrieves the charge-level reconciliation for a charge.
 func (c *ReconciliationContract) GetReconciliation(ctx contractapi.TransactionContextInterface, chargeID string) (*models.Reconciliation, error) {
-	key := "RECON_" + chargeID
-	bytes, err := ctx.GetStub().GetState(key)
+	recon, err := readChargeReconciliation(ctx, chargeID)
+	if err != nil {
+		return nil, err
+	}
+	if recon == nil {
+		return nil, fmt.Errorf("reconciliation for charge %s not found", chargeID)
+	}
+
+	return recon, nil
+}
+
+// readChargeReconciliation reads the charge-level reconciliation of chargeID,
+// returning nil if the charge has not been reconciled.
+func readChargeReconciliation(ctx contractapi.TransactionContextInterface, chargeID string) (*models.Reconciliation, error) {
+	bytes, err := ctx.GetStub().GetState(models.ReconciliationKey(chargeID, 0))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state: %w", err)
 	}
 	if bytes == nil {
-		return nil, fmt.Errorf("reconciliation for charge %s not found", chargeID)
+		return nil, nil
+	}
+
+	var recon models.Reconciliation
+	if err := decodeDocument("reconciliation", bytes, &recon); err != nil {
+		return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
+	}
+
+	return &recon, nil
+}
+
+// ReconciliationsByChargeIDsResult is the result of GetReconciliationsByChargeIDs.
+type ReconciliationsByChargeIDsResult struct {
+	Reconciliations  []*models.Reconciliation `json:"reconciliations"`
+	MissingChargeIDs []string                 `json:"missingChargeIDs"`
+}
+
+// GetReconciliationsByChargeIDs returns the charge-level reconciliations of
+// the given charges, in the order requested, and lists the chargeIDs that
+// have not been reconciled. chargeIDsJSON is a JSON array of chargeIDs
+// holding at most MaxBatchSize entries; repeated IDs are read once.
+func (c *ReconciliationContract) GetReconciliationsByChargeIDs(ctx contractapi.TransactionContextInterface, chargeIDsJSON string) (*ReconciliationsByChargeIDsResult, error) {
+	var chargeIDs []string
+	if err := json.Unmarshal([]byte(chargeIDsJSON), &chargeIDs); err != nil {
+		return nil, fmt.Errorf("failed to parse charge IDs JSON: %w", err)
+	}
+	if err := checkBatchSize(len(chargeIDs)); err != nil {
+		return nil, err
+	}
+
+	result := &ReconciliationsByChargeIDsResult{Reconciliations: []*models.Reconciliation{}, MissingChargeIDs: []string{}}
+	seen := make(map[string]bool, len(chargeIDs))
+	for _, chargeID := range chargeIDs {
+		if seen[chargeID] {
+			continue
+		}
+		seen[chargeID] = true
+
+		recon, err := readChargeReconciliation(ctx, chargeID)
+		if err != nil {
+			return nil, err
+		}
+		if recon == nil {
+			result.MissingChargeIDs = append(result.MissingChargeIDs, chargeID)
+			continue
+		}
+		result.Reconciliations = append(result.Reconciliations, recon)
+	}
+
+	return result, nil
+}
+
+// GetCorrectionReconciliation retrieves the reconciliation of one correction
+// of a charge.
+func (c *ReconciliationContract) GetCorrectionReconciliation(ctx contractapi.TransactionContextInterface, chargeID string, correctionSeqNo int) (*models.Reconciliation, error) {
+	if correctionSeqNo < models.FirstCorrectionSeqNo {
+		return nil, fmt.Errorf("correctionSeqNo must be >= %d, got %d", models.FirstCorrectionSeqNo, correctionSeqNo)
+	}
+
+	bytes, err := ctx.GetStub().GetState(models.ReconciliationKey(chargeID, correctionSeqNo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if bytes == nil {
+		return nil, fmt.Errorf("reconciliation for charge %s correction %d not found", chargeID, correctionSeqNo)
 	}
 
 	var recon models.Reconciliation
-	if err := json.Unmarshal(bytes, &recon); err != nil {
+	if err := decodeDocument("reconciliation", bytes, &recon); err != nil {
 		return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
 	}
 
 	return &recon, nil
 }
 
+// CreateReconciliationBatch records the reconciliations a home agency
+// returned in one SRECON file, before the reconciliations themselves are
+// created with its batchID. The batch's count must equal the number of
+// reconciliationIDs it lists.
+func (c *ReconciliationContract) CreateReconciliationBatch(ctx contractapi.TransactionContextInterface, batchJSON string) error {
+	var batch models.ReconciliationBatch
+	if err := json.Unmarshal([]byte(batchJSON), &batch); err != nil {
+		return fmt.Errorf("failed to parse reconciliation batch JSON: %w", err)
+	}
+
+	if err := validateModel(&batch); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	existing, err := ctx.GetStub().GetState(batch.Key())
+	if err != nil {
+		return fmt.Errorf("failed to read state: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("reconciliation batch %s already exists", batch.BatchID)
+	}
+
+	batch.SetCreatedAt()
+
+	bytes, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reconciliation batch: %w", err)
+	}
+
+	return ctx.GetStub().PutState(batch.Key(), bytes)
+}
+
+// GetReconciliationBatch retrieves a reconciliation batch by ID.
+func (c *ReconciliationContract) GetReconciliationBatch(ctx contractapi.TransactionContextInterface, batchID string) (*models.ReconciliationBatch, error) {
+	bytes, err := ctx.GetStub().GetState(models.ReconciliationBatchKey(batchID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if bytes == nil {
+		return nil, fmt.Errorf("reconciliation batch %s not found", batchID)
+	}
+
+	var batch models.ReconciliationBatch
+	if err := decodeDocument("reconciliationbatch", bytes, &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse reconciliation batch: %w", err)
+	}
+
+	return &batch, nil
+}
+
 // GetReconciliationsByAgency returns all reconciliations for a home agency.
 // Uses a CouchDB rich query with index on (docType, homeAgencyID).
 func (c *ReconciliationContract) GetReconciliationsByAgency(ctx contractapi.TransactionContextInterface, homeAgencyID string) ([]*models.Reconciliation, error) {
@@ -83,7 +281,7 @@ func (c *ReconciliationContract) GetReconciliationsByAgency(ctx contractapi.Tran
 		}
 
 		var recon models.Reconciliation
-		if err := json.Unmarshal(queryResponse.Value, &recon); err != nil {
+		if err := decodeDocument("reconciliation", queryResponse.Value, &recon); err != nil {
 			return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
 		}
 		reconciliations = append(reconciliations, &recon)
@@ -95,7 +293,7 @@ func (c *ReconciliationContract) GetReconciliationsByAgency(ctx contractapi.Tran
 // GetReconciliationsByDisposition returns all reconciliations with a specific disposition.
 // Uses a CouchDB rich query with index on (docType, postingDisposition).
 func (c *ReconciliationContract) GetReconciliationsByDisposition(ctx contractapi.TransactionContextInterface, disposition string) ([]*models.Reconciliation, error) {
-	if !contains(models.ValidPostingDispositions, disposition) {
+	if !models.Contains(models.ValidPostingDispositions, disposition) {
 		return nil, fmt.Errorf("invalid postingDisposition %q: must be one of %v", disposition, models.ValidPostingDispositions)
 	}
 
@@ -114,7 +312,166 @@ func (c *ReconciliationContract) GetReconciliationsByDisposition(ctx contractapi
 		}
 
 		var recon models.Reconciliation
-		if err := json.Unmarshal(queryResponse.Value, &recon); err != nil {
+		if err := decodeDocument("reconciliation", queryResponse.Value, &recon); err != nil {
+			return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
+		}
+		reconciliations = append(reconciliations, &recon)
+	}
+
+	return reconciliations, nil
+}
+
+// ReconciliationSortFields lists the reconciliation fields that
+// GetReconciliationsByAgencySorted and GetReconciliationsByDispositionSorted
+// can sort on. Each has a (docType, homeAgencyID, field) and a (docType,
+// postingDisposition, field) index under META-INF/statedb/couchdb/indexes.
+var ReconciliationSortFields = []string{"chargeID", "postedAmount", "createdAt"}
+
+// GetReconciliationsByAgencySorted returns a home agency's reconciliations,
+// sorted ascending by sortField and truncated to limit results. An empty
+// sortField leaves results unsorted; a limit of 0 returns all matches.
+func (c *ReconciliationContract) GetReconciliationsByAgencySorted(ctx contractapi.TransactionContextInterface, homeAgencyID string, sortField string, limit int) ([]*models.Reconciliation, error) {
+	if err := validateSortAndLimit(sortField, ReconciliationSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("reconciliation", map[string]interface{}{"homeAgencyID": homeAgencyID}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryReconciliations(ctx, query)
+}
+
+// GetReconciliationsByDispositionSorted returns the reconciliations with a
+// specific disposition, sorted and truncated as
+// GetReconciliationsByAgencySorted does.
+func (c *ReconciliationContract) GetReconciliationsByDispositionSorted(ctx contractapi.TransactionContextInterface, disposition string, sortField string, limit int) ([]*models.Reconciliation, error) {
+	if !models.Contains(models.ValidPostingDispositions, disposition) {
+		return nil, fmt.Errorf("invalid postingDisposition %q: must be one of %v", disposition, models.ValidPostingDispositions)
+	}
+	if err := validateSortAndLimit(sortField, ReconciliationSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("reconciliation", map[string]interface{}{"postingDisposition": disposition}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryReconciliations(ctx, query)
+}
+
+// ReconciliationPage is one page of a paginated reconciliation query.
+// Bookmark is passed back to fetch the next page; a page with fewer than
+// pageSize reconciliations is the last.
+type ReconciliationPage struct {
+	Reconciliations []*models.Reconciliation `json:"reconciliations"`
+	FetchedCount    int                      `json:"fetchedCount"`
+	Bookmark        string                   `json:"bookmark"`
+}
+
+// GetReconciliationsByAgencyPaged returns one page of a home agency's
+// reconciliations, as GetReconciliationsByAgency does. pageSize is between 1
+// and maxPageSize; bookmark is empty for the first page and otherwise the
+// Bookmark of the previous page.
+func (c *ReconciliationContract) GetReconciliationsByAgencyPaged(ctx contractapi.TransactionContextInterface, homeAgencyID string, pageSize int, bookmark string) (*ReconciliationPage, error) {
+	query, err := newRichQuery("reconciliation", map[string]interface{}{
+		"homeAgencyID": homeAgencyID,
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+	return queryReconciliationPage(ctx, query, pageSize, bookmark)
+}
+
+// GetReconciliationsByDispositionPaged returns one page of the reconciliations
+// with a disposition, as GetReconciliationsByDisposition does. pageSize and
+// bookmark are as for GetReconciliationsByAgencyPaged.
+func (c *ReconciliationContract) GetReconciliationsByDispositionPaged(ctx contractapi.TransactionContextInterface, disposition string, pageSize int, bookmark string) (*ReconciliationPage, error) {
+	if !models.Contains(models.ValidPostingDispositions, disposition) {
+		return nil, fmt.Errorf("invalid postingDisposition %q: must be one of %v", disposition, models.ValidPostingDispositions)
+	}
+
+	query, err := newRichQuery("reconciliation", map[string]interface{}{
+		"postingDisposition": disposition,
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+	return queryReconciliationPage(ctx, query, pageSize, bookmark)
+}
+
+// queryReconciliationPage runs a paginated reconciliation query.
+func queryReconciliationPage(ctx contractapi.TransactionContextInterface, query string, pageSize int, bookmark string) (*ReconciliationPage, error) {
+	if err := validatePageSize(pageSize); err != nil {
+		return nil, err
+	}
+
+	resultsIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(query, int32(pageSize), bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	page := &ReconciliationPage{Reconciliations: []*models.Reconciliation{}}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var recon models.Reconciliation
+		if err := decodeDocument("reconciliation", queryResponse.Value, &recon); err != nil {
+			return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
+		}
+		page.Reconciliations = append(page.Reconciliations, &recon)
+	}
+	page.FetchedCount = int(metadata.GetFetchedRecordsCount())
+	page.Bookmark = metadata.GetBookmark()
+
+	return page, nil
+}
+
+// GetFailedReconciliations returns a home agency's reconciliations whose
+// disposition is anything other than posted ("P"), as a follow-up worklist.
+// Returns an empty list when every reconciliation posted.
+func (c *ReconciliationContract) GetFailedReconciliations(ctx contractapi.TransactionContextInterface, homeAgencyID string) ([]*models.Reconciliation, error) {
+	query, err := newRichQuery("reconciliation", map[string]interface{}{
+		"homeAgencyID":       homeAgencyID,
+		"postingDisposition": map[string]interface{}{"$ne": "P"},
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryReconciliations(ctx, query)
+}
+
+// queryReconciliations runs a rich query against world state and decodes
+// each result as a reconciliation. Returns an empty list when nothing matches.
+func queryReconciliations(ctx contractapi.TransactionContextInterface, query string) ([]*models.Reconciliation, error) {
+	resultsIterator, err := ctx.GetStub().GetQueryResult(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	reconciliations := []*models.Reconciliation{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var recon models.Reconciliation
+		if err := decodeDocument("reconciliation", queryResponse.Value, &recon); err != nil {
 			return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
 		}
 		reconciliations = append(reconciliations, &recon)
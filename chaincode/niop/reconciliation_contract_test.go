@@ -4,6 +4,7 @@ package niop
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -25,6 +26,37 @@ func validReconciliation() *models.Reconciliation {
 	}
 }
 
+// reconciliationFixture describes a reconciliation to store before a test.
+// The home agency defaults to ORG1 and the disposition to P.
+type reconciliationFixture struct {
+	chargeID    string
+	home        string
+	disposition string
+	modify      func(*models.Reconciliation)
+}
+
+// createReconciliations stores a reconciliation for each fixture, keyed
+// RECON-<chargeID>.
+func createReconciliations(t *testing.T, ctx *enhancedMockContext, fixtures ...reconciliationFixture) {
+	t.Helper()
+	for _, f := range fixtures {
+		recon := validReconciliation()
+		recon.ReconciliationID = "RECON-" + f.chargeID
+		recon.ChargeID = f.chargeID
+		if f.home != "" {
+			recon.HomeAgencyID = f.home
+		}
+		if f.disposition != "" {
+			recon.PostingDisposition = f.disposition
+		}
+		if f.modify != nil {
+			f.modify(recon)
+		}
+		reconJSON, _ := json.Marshal(recon)
+		require.NoError(t, (&ReconciliationContract{}).CreateReconciliation(ctx, string(reconJSON)))
+	}
+}
+
 func TestCreateReconciliation(t *testing.T) {
 	contract := &ReconciliationContract{}
 
@@ -96,6 +128,131 @@ func TestCreateReconciliation(t *testing.T) {
 	})
 }
 
+func TestCreateReconciliation_HomeAgency(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	t.Run("accepts a home agency on the ledger", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		agencyJSON, _ := json.Marshal(validAgency())
+		require.NoError(t, (&AgencyContract{}).CreateAgency(ctx, string(agencyJSON)))
+
+		reconJSON, _ := json.Marshal(validReconciliation())
+		assert.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+	})
+
+	t.Run("rejects an unknown home agency", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = true })
+		ctx := newMockContext()
+		agencyJSON, _ := json.Marshal(validAgency())
+		require.NoError(t, (&AgencyContract{}).CreateAgency(ctx, string(agencyJSON)))
+
+		recon := validReconciliation()
+		recon.HomeAgencyID = "ORG9"
+		reconJSON, _ := json.Marshal(recon)
+		err := contract.CreateReconciliation(ctx, string(reconJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load home agency: agency ORG9 not found")
+
+		bytes, _ := ctx.stub.GetState(recon.Key())
+		assert.Nil(t, bytes)
+	})
+
+	t.Run("skips the check outside strict mode", func(t *testing.T) {
+		withConfig(t, func(c *Config) { c.StrictMode = false })
+		ctx := newMockContext()
+
+		recon := validReconciliation()
+		recon.HomeAgencyID = "ORG9"
+		reconJSON, _ := json.Marshal(recon)
+		assert.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+	})
+}
+
+func TestCreateReconciliation_AmountMismatch(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	tests := []struct {
+		name         string
+		postedAmount float64
+		autoDispute  bool
+		wantMismatch bool
+		wantStatus   string
+	}{
+		{"matching amount is not flagged", 4.75, false, false, "posted"},
+		{"difference within a cent is not flagged", 4.74, false, false, "posted"},
+		{"mismatched amount is flagged without disputing by default", 3.50, false, true, "posted"},
+		{"mismatched amount disputes the charge when enabled", 3.50, true, true, "disputed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withConfig(t, func(c *Config) { c.AutoDisputeOnAmountMismatch = tt.autoDispute })
+			ctx := newContextWithCharges(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+			recon := validReconciliation()
+			recon.AwayAgencyID = "ORG2"
+			recon.PostedAmount = tt.postedAmount
+			recon.AmountMismatch = true // caller-supplied flag is ignored
+			reconJSON, _ := json.Marshal(recon)
+
+			require.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+
+			stored, err := contract.GetReconciliation(ctx, "CHG-TEST-001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMismatch, stored.AmountMismatch)
+			charge, err := (&ChargeContract{}).GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, charge.Status)
+		})
+	}
+
+	t.Run("rejects reconciliation for missing charge", func(t *testing.T) {
+		ctx := newMockContext()
+		recon := validReconciliation()
+		recon.AwayAgencyID = "ORG2"
+		reconJSON, _ := json.Marshal(recon)
+
+		err := contract.CreateReconciliation(ctx, string(reconJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load charge")
+	})
+}
+
+func TestCreateReconciliation_Overpost(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	create := func(t *testing.T, posted float64, allowOverpost bool) error {
+		ctx := newMockContext()
+		chargeJSON, _ := json.Marshal(validCharge())
+		require.NoError(t, (&ChargeContract{}).CreateCharge(ctx, string(chargeJSON)))
+
+		recon := validReconciliation()
+		recon.AwayAgencyID = "ORG2"
+		recon.PostedAmount = posted
+		recon.AllowOverpost = allowOverpost
+		reconJSON, _ := json.Marshal(recon)
+		return contract.CreateReconciliation(ctx, string(reconJSON))
+	}
+
+	t.Run("accepts posted amount equal to charge", func(t *testing.T) {
+		assert.NoError(t, create(t, 4.75, false))
+	})
+
+	t.Run("accepts posted amount under charge", func(t *testing.T) {
+		assert.NoError(t, create(t, 3.50, false))
+	})
+
+	t.Run("rejects posted amount over charge", func(t *testing.T) {
+		err := create(t, 5.00, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "postedAmount exceeds charge amount")
+	})
+
+	t.Run("accepts overpost when explicitly allowed", func(t *testing.T) {
+		assert.NoError(t, create(t, 5.00, true))
+	})
+}
+
 func TestGetReconciliation(t *testing.T) {
 	contract := &ReconciliationContract{}
 
@@ -122,6 +279,157 @@ func TestGetReconciliation(t *testing.T) {
 	})
 }
 
+func TestGetReconciliationsByChargeIDs(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	t.Run("returns reconciliations and unreconciled charge IDs", func(t *testing.T) {
+		ctx := newMockContext()
+		for _, chargeID := range []string{"CHG-001", "CHG-003"} {
+			recon := validReconciliation()
+			recon.ReconciliationID = "RECON-" + chargeID
+			recon.ChargeID = chargeID
+			reconJSON, _ := json.Marshal(recon)
+			require.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+		}
+
+		result, err := contract.GetReconciliationsByChargeIDs(ctx, `["CHG-003","CHG-002","CHG-001","CHG-003"]`)
+		require.NoError(t, err)
+		require.Len(t, result.Reconciliations, 2)
+		assert.Equal(t, "CHG-003", result.Reconciliations[0].ChargeID)
+		assert.Equal(t, "CHG-001", result.Reconciliations[1].ChargeID)
+		assert.Equal(t, []string{"CHG-002"}, result.MissingChargeIDs)
+	})
+
+	t.Run("accepts batch of max size", func(t *testing.T) {
+		ctx := newMockContext()
+		ids := make([]string, MaxBatchSize)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("CHG-%04d", i)
+		}
+		idsJSON, _ := json.Marshal(ids)
+
+		_, err := contract.GetReconciliationsByChargeIDs(ctx, string(idsJSON))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects oversized batch", func(t *testing.T) {
+		ctx := newMockContext()
+		ids := make([]string, MaxBatchSize+1)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("CHG-%04d", i)
+		}
+		idsJSON, _ := json.Marshal(ids)
+
+		_, err := contract.GetReconciliationsByChargeIDs(ctx, string(idsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("batch exceeds max size %d", MaxBatchSize))
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetReconciliationsByChargeIDs(ctx, `CHG-001`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse")
+	})
+}
+
+func TestCreateReconciliation_CorrectionLevel(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	t.Run("charge and correction reconciliations coexist", func(t *testing.T) {
+		ctx := newMockContext()
+		chargeRecon := validReconciliation()
+		chargeJSON, _ := json.Marshal(chargeRecon)
+		require.NoError(t, contract.CreateReconciliation(ctx, string(chargeJSON)))
+
+		corrRecon := validReconciliation()
+		corrRecon.ReconciliationID = "RECON-TEST-002"
+		corrRecon.CorrectionSeqNo = 1
+		corrJSON, _ := json.Marshal(corrRecon)
+		require.NoError(t, contract.CreateReconciliation(ctx, string(corrJSON)))
+
+		bytes, err := ctx.stub.GetState("RECONCORR_CHG-TEST-001_001")
+		require.NoError(t, err)
+		require.NotNil(t, bytes)
+
+		byCharge, err := contract.GetReconciliation(ctx, "CHG-TEST-001")
+		require.NoError(t, err)
+		assert.Equal(t, "RECON-TEST-001", byCharge.ReconciliationID)
+		assert.Zero(t, byCharge.CorrectionSeqNo)
+
+		byCorrection, err := contract.GetCorrectionReconciliation(ctx, "CHG-TEST-001", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "RECON-TEST-002", byCorrection.ReconciliationID)
+		assert.Equal(t, 1, byCorrection.CorrectionSeqNo)
+
+		all, err := contract.GetReconciliationsByAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("charge ID with underscore does not collide with a correction", func(t *testing.T) {
+		ctx := newMockContext()
+		chargeRecon := validReconciliation()
+		chargeRecon.ChargeID = "X_001"
+		chargeJSON, _ := json.Marshal(chargeRecon)
+		require.NoError(t, contract.CreateReconciliation(ctx, string(chargeJSON)))
+
+		corrRecon := validReconciliation()
+		corrRecon.ReconciliationID = "RECON-TEST-002"
+		corrRecon.ChargeID = "X"
+		corrRecon.CorrectionSeqNo = 1
+		corrJSON, _ := json.Marshal(corrRecon)
+		require.NoError(t, contract.CreateReconciliation(ctx, string(corrJSON)))
+
+		byCharge, err := contract.GetReconciliation(ctx, "X_001")
+		require.NoError(t, err)
+		assert.Equal(t, "RECON-TEST-001", byCharge.ReconciliationID)
+
+		byCorrection, err := contract.GetCorrectionReconciliation(ctx, "X", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "RECON-TEST-002", byCorrection.ReconciliationID)
+	})
+
+	t.Run("rejects duplicate correction reconciliation", func(t *testing.T) {
+		ctx := newMockContext()
+		recon := validReconciliation()
+		recon.CorrectionSeqNo = 1
+		reconJSON, _ := json.Marshal(recon)
+		require.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+
+		err := contract.CreateReconciliation(ctx, string(reconJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correction 1 already exists")
+	})
+
+	t.Run("checks the correction exists when the away agency is known", func(t *testing.T) {
+		ctx := newMockContext()
+		recon := validReconciliation()
+		recon.AwayAgencyID = "ORG2"
+		recon.CorrectionSeqNo = 1
+		reconJSON, _ := json.Marshal(recon)
+
+		err := contract.CreateReconciliation(ctx, string(reconJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load correction")
+
+		corrJSON, _ := json.Marshal(validCorrection())
+		require.NoError(t, (&CorrectionContract{}).CreateCorrection(ctx, string(corrJSON)))
+		require.NoError(t, contract.CreateReconciliation(ctx, string(reconJSON)))
+	})
+
+	t.Run("returns error for missing correction reconciliation", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetCorrectionReconciliation(ctx, "CHG-TEST-001", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correction 1 not found")
+
+		_, err = contract.GetCorrectionReconciliation(ctx, "CHG-TEST-001", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "correctionSeqNo must be >= 1")
+	})
+}
+
 func TestGetReconciliationsByAgency(t *testing.T) {
 	contract := &ReconciliationContract{}
 
@@ -159,6 +467,74 @@ func TestGetReconciliationsByAgency(t *testing.T) {
 	})
 }
 
+func TestGetReconciliationsPaged(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	fixtures := []reconciliationFixture{
+		{chargeID: "CHG-001"},
+		{chargeID: "CHG-002"},
+		{chargeID: "CHG-003"},
+		{chargeID: "CHG-004"},
+		{chargeID: "CHG-005", disposition: "D"},
+		{chargeID: "CHG-ORG2", home: "ORG2"},
+	}
+
+	t.Run("pages through an agency's reconciliations", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+
+		first, err := contract.GetReconciliationsByAgencyPaged(ctx, "ORG1", 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-001", "CHG-002"}, reconciliationChargeIDs(first.Reconciliations))
+		assert.Equal(t, 2, first.FetchedCount)
+		require.NotEmpty(t, first.Bookmark)
+
+		second, err := contract.GetReconciliationsByAgencyPaged(ctx, "ORG1", 2, first.Bookmark)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-003", "CHG-004"}, reconciliationChargeIDs(second.Reconciliations))
+
+		last, err := contract.GetReconciliationsByAgencyPaged(ctx, "ORG1", 2, second.Bookmark)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-005"}, reconciliationChargeIDs(last.Reconciliations), "short page is the last")
+
+		after, err := contract.GetReconciliationsByAgencyPaged(ctx, "ORG1", 2, last.Bookmark)
+		require.NoError(t, err)
+		assert.Empty(t, after.Reconciliations)
+		assert.Equal(t, 0, after.FetchedCount)
+	})
+
+	t.Run("pages through reconciliations by disposition", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+
+		first, err := contract.GetReconciliationsByDispositionPaged(ctx, "P", 3, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-001", "CHG-002", "CHG-003"}, reconciliationChargeIDs(first.Reconciliations))
+
+		second, err := contract.GetReconciliationsByDispositionPaged(ctx, "P", 3, first.Bookmark)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-004", "CHG-ORG2"}, reconciliationChargeIDs(second.Reconciliations))
+	})
+
+	t.Run("rejects invalid page size", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+		for _, size := range []int{0, maxPageSize + 1} {
+			_, err := contract.GetReconciliationsByAgencyPaged(ctx, "ORG1", size, "")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "pageSize must be between 1 and")
+		}
+	})
+
+	t.Run("rejects invalid disposition", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+		_, err := contract.GetReconciliationsByDispositionPaged(ctx, "X", 2, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid postingDisposition")
+	})
+}
+
 func TestGetReconciliationsByDisposition(t *testing.T) {
 	contract := &ReconciliationContract{}
 
@@ -192,3 +568,177 @@ func TestGetReconciliationsByDisposition(t *testing.T) {
 		assert.Equal(t, "P", result[0].PostingDisposition)
 	})
 }
+
+func TestGetReconciliationsSorted(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	postedAt := func(amount float64) func(*models.Reconciliation) {
+		return func(r *models.Reconciliation) { r.PostedAmount = amount }
+	}
+	fixtures := []reconciliationFixture{
+		{chargeID: "CHG-1", modify: postedAt(4.75)},
+		{chargeID: "CHG-2", modify: postedAt(1.25)},
+		{chargeID: "CHG-3", modify: postedAt(3.00)},
+		{chargeID: "CHG-4", disposition: "D", modify: postedAt(0)},
+		{chargeID: "CHG-5", home: "ORG2", modify: postedAt(2.00)},
+	}
+
+	t.Run("sorts an agency's reconciliations", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+
+		result, err := contract.GetReconciliationsByAgencySorted(ctx, "ORG1", "postedAmount", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-4", "CHG-2", "CHG-3", "CHG-1"}, reconciliationChargeIDs(result))
+
+		result, err = contract.GetReconciliationsByAgencySorted(ctx, "ORG1", "postedAmount", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-4", "CHG-2"}, reconciliationChargeIDs(result))
+	})
+
+	t.Run("sorts reconciliations by disposition", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+
+		result, err := contract.GetReconciliationsByDispositionSorted(ctx, "P", "postedAmount", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-2", "CHG-5", "CHG-3", "CHG-1"}, reconciliationChargeIDs(result))
+
+		result, err = contract.GetReconciliationsByDispositionSorted(ctx, "P", "postedAmount", 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-2", "CHG-5", "CHG-3"}, reconciliationChargeIDs(result))
+	})
+
+	t.Run("unsorted and unlimited by default", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, fixtures...)
+
+		result, err := contract.GetReconciliationsByAgencySorted(ctx, "ORG2", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CHG-5"}, reconciliationChargeIDs(result))
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetReconciliationsByAgencySorted(ctx, "ORG1", "flatFee", 0)
+		assert.ErrorContains(t, err, "invalid sortField")
+
+		_, err = contract.GetReconciliationsByDispositionSorted(ctx, "P", "postedAmount", -1)
+		assert.ErrorContains(t, err, "limit must be >= 0")
+
+		_, err = contract.GetReconciliationsByDispositionSorted(ctx, "X", "", 0)
+		assert.ErrorContains(t, err, "invalid postingDisposition")
+	})
+}
+
+func TestGetFailedReconciliations(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	t.Run("returns every non-posted disposition for the agency", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx,
+			reconciliationFixture{chargeID: "CHG-1"},
+			reconciliationFixture{chargeID: "CHG-2", disposition: "D"},
+			reconciliationFixture{chargeID: "CHG-3", disposition: "I"},
+			reconciliationFixture{chargeID: "CHG-4"},
+			reconciliationFixture{chargeID: "CHG-5", home: "ORG2", disposition: "N"},
+		)
+
+		failed, err := contract.GetFailedReconciliations(ctx, "ORG1")
+		require.NoError(t, err)
+
+		for _, r := range failed {
+			assert.False(t, r.IsPosted())
+		}
+		assert.ElementsMatch(t, []string{"CHG-2", "CHG-3"}, reconciliationChargeIDs(failed))
+	})
+
+	t.Run("returns empty slice when everything posted", func(t *testing.T) {
+		ctx := newMockContext()
+		createReconciliations(t, ctx, reconciliationFixture{chargeID: "CHG-1"})
+
+		failed, err := contract.GetFailedReconciliations(ctx, "ORG1")
+		require.NoError(t, err)
+		assert.NotNil(t, failed)
+		assert.Empty(t, failed)
+	})
+}
+
+func TestReconciliationBatch(t *testing.T) {
+	contract := &ReconciliationContract{}
+
+	validBatch := func() *models.ReconciliationBatch {
+		return &models.ReconciliationBatch{
+			BatchID:           "SRECON-001",
+			HomeAgencyID:      "ORG1",
+			AwayAgencyID:      "ORG2",
+			ReconciliationIDs: []string{"RECON-1", "RECON-2"},
+			Count:             2,
+		}
+	}
+
+	batchedRecon := func(reconID string, chargeID string) string {
+		recon := validReconciliation()
+		recon.ReconciliationID = reconID
+		recon.ChargeID = chargeID
+		recon.BatchID = "SRECON-001"
+		reconJSON, _ := json.Marshal(recon)
+		return string(reconJSON)
+	}
+
+	t.Run("creates batch and attaches reconciliations", func(t *testing.T) {
+		ctx := newMockContext()
+		batchJSON, _ := json.Marshal(validBatch())
+		require.NoError(t, contract.CreateReconciliationBatch(ctx, string(batchJSON)))
+
+		require.NoError(t, contract.CreateReconciliation(ctx, batchedRecon("RECON-1", "CHG-1")))
+		require.NoError(t, contract.CreateReconciliation(ctx, batchedRecon("RECON-2", "CHG-2")))
+
+		batch, err := contract.GetReconciliationBatch(ctx, "SRECON-001")
+		require.NoError(t, err)
+		assert.Equal(t, 2, batch.Count)
+		assert.Equal(t, "reconciliationbatch", batch.DocType)
+
+		recon, err := contract.GetReconciliation(ctx, "CHG-1")
+		require.NoError(t, err)
+		assert.Equal(t, "SRECON-001", recon.BatchID)
+	})
+
+	t.Run("rejects count mismatch", func(t *testing.T) {
+		ctx := newMockContext()
+		batch := validBatch()
+		batch.Count = 3
+		batchJSON, _ := json.Marshal(batch)
+
+		err := contract.CreateReconciliationBatch(ctx, string(batchJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "record count mismatch")
+	})
+
+	t.Run("rejects duplicate batch", func(t *testing.T) {
+		ctx := newMockContext()
+		batchJSON, _ := json.Marshal(validBatch())
+		require.NoError(t, contract.CreateReconciliationBatch(ctx, string(batchJSON)))
+
+		err := contract.CreateReconciliationBatch(ctx, string(batchJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("rejects reconciliation for unknown batch", func(t *testing.T) {
+		ctx := newMockContext()
+		err := contract.CreateReconciliation(ctx, batchedRecon("RECON-1", "CHG-1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reconciliation batch SRECON-001 not found")
+	})
+
+	t.Run("rejects reconciliation not listed in batch", func(t *testing.T) {
+		ctx := newMockContext()
+		batchJSON, _ := json.Marshal(validBatch())
+		require.NoError(t, contract.CreateReconciliationBatch(ctx, string(batchJSON)))
+
+		err := contract.CreateReconciliation(ctx, batchedRecon("RECON-9", "CHG-9"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not listed in batch")
+	})
+}
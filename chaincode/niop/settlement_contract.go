@@ -5,6 +5,9 @@ package niop
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -18,13 +21,30 @@ type SettlementContract struct {
 
 // CreateSettlement creates a new settlement on the ledger.
 // The settlement is stored in a private data collection named charges_{A}_{B}.
+// New settlements start in "draft"; an empty status defaults to it and any
+// other status is rejected, so every settlement goes through the workflow.
 func (c *SettlementContract) CreateSettlement(ctx contractapi.TransactionContextInterface, settlementJSON string) error {
 	var settlement models.Settlement
 	if err := json.Unmarshal([]byte(settlementJSON), &settlement); err != nil {
 		return fmt.Errorf("failed to parse settlement JSON: %w", err)
 	}
 
-	if err := settlement.Validate(); err != nil {
+	return c.createSettlement(ctx, &settlement)
+}
+
+// createSettlement validates and stores a new draft settlement. It holds the
+// checks shared by CreateSettlement and SettleWithNoPaymentDue.
+func (c *SettlementContract) createSettlement(ctx contractapi.TransactionContextInterface, settlement *models.Settlement) error {
+	if settlement.Status == "" {
+		settlement.Status = "draft"
+	}
+	if settlement.Status != "draft" {
+		return fmt.Errorf("new settlements must start in draft, got status %q", settlement.Status)
+	}
+	// Approvals are recorded by ApproveSettlement, never taken from the caller.
+	settlement.Approvals = nil
+
+	if err := validateModel(settlement); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -50,12 +70,7 @@ func (c *SettlementContract) CreateSettlement(ctx contractapi.TransactionContext
 // GetSettlement retrieves a settlement by ID.
 // Requires knowing both agency IDs to determine the collection name.
 func (c *SettlementContract) GetSettlement(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string) (*models.Settlement, error) {
-	// Determine collection name using alphabetical sort
-	a, b := payorAgencyID, payeeAgencyID
-	if a > b {
-		a, b = b, a
-	}
-	collection := "charges_" + a + "_" + b
+	collection := models.BilateralCollectionName(payorAgencyID, payeeAgencyID)
 	key := "SETTLEMENT_" + settlementID
 
 	bytes, err := ctx.GetStub().GetPrivateData(collection, key)
@@ -67,7 +82,7 @@ func (c *SettlementContract) GetSettlement(ctx contractapi.TransactionContextInt
 	}
 
 	var settlement models.Settlement
-	if err := json.Unmarshal(bytes, &settlement); err != nil {
+	if err := decodeDocument("settlement", bytes, &settlement); err != nil {
 		return nil, fmt.Errorf("failed to parse settlement: %w", err)
 	}
 
@@ -76,18 +91,63 @@ func (c *SettlementContract) GetSettlement(ctx contractapi.TransactionContextInt
 
 // UpdateSettlementStatus updates the status of an existing settlement.
 // Valid transitions: draft->submitted, submitted->accepted/disputed,
-// accepted->paid, disputed->submitted/accepted.
+// disputed->submitted/accepted.
+//
+// A settlement is never marked paid directly: RecordSettlementPayment moves it
+// to "paid" once payments cover its net amount. A settlement that is already
+// covered when it is accepted, such as one that nets to zero, has no payment
+// to wait for and moves on to "paid" in the same transaction.
 func (c *SettlementContract) UpdateSettlementStatus(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string, newStatus string) error {
+	if newStatus == "paid" {
+		return fmt.Errorf("settlement %s cannot be marked paid directly: record payments with RecordSettlementPayment, or use SettleWithNoPaymentDue when nothing is owed", settlementID)
+	}
+
 	settlement, err := c.GetSettlement(ctx, settlementID, payorAgencyID, payeeAgencyID)
 	if err != nil {
 		return err
 	}
 
+	if err := c.moveToStatus(ctx, settlement, newStatus); err != nil {
+		return err
+	}
+	if newStatus == "accepted" && settlement.FullyPaid() {
+		return c.moveToStatus(ctx, settlement, "paid")
+	}
+	return nil
+}
+
+// moveToStatus transitions a loaded settlement to newStatus and writes it.
+//
+// Moving to "paid" also settles the settlement's charges in the same
+// transaction. Charges that are missing or cannot move to "settled" are
+// skipped and recorded in the settlement's warnings. The update is
+// all-or-nothing.
+func (c *SettlementContract) moveToStatus(ctx contractapi.TransactionContextInterface, settlement *models.Settlement, newStatus string) error {
 	if err := settlement.ValidateStatusTransition(newStatus); err != nil {
 		return fmt.Errorf("invalid status transition: %w", err)
 	}
 
-	settlement.Status = newStatus
+	settlement.SetStatus(newStatus)
+
+	var charges []*models.Charge
+	if newStatus == "paid" {
+		var err error
+		charges, err = c.chargesToSettle(ctx, settlement)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Every eligible charge is checked before anything is written. Writes
+	// belong to one transaction, so a failure part-way leaves the ledger
+	// unchanged.
+	for _, charge := range charges {
+		previousStatus := charge.Status
+		charge.Status = "settled"
+		if err := putCharge(ctx, charge, previousStatus); err != nil {
+			return fmt.Errorf("failed to settle charge %s: %w", charge.ChargeID, err)
+		}
+	}
 
 	bytes, err := json.Marshal(settlement)
 	if err != nil {
@@ -97,14 +157,357 @@ func (c *SettlementContract) UpdateSettlementStatus(ctx contractapi.TransactionC
 	return ctx.GetStub().PutPrivateData(settlement.CollectionName(), settlement.Key(), bytes)
 }
 
+// settlementApprovers holds, by payor agency ID, the MSPs allowed to approve
+// that agency's settlements. Set it with SetSettlementApprovers during
+// chaincode startup, before any transaction is served.
+var settlementApprovers = map[string][]string{}
+
+// SetSettlementApprovers sets the MSPs allowed to approve each payor
+// agency's settlements, for payors whose approvers belong to more than one
+// MSP. Agencies not listed may only be approved by their own MSP. It
+// replaces any approvers set before.
+func SetSettlementApprovers(approvers map[string][]string) error {
+	for agencyID, mspIDs := range approvers {
+		if err := models.ValidateAgencyID("agencyID", agencyID); err != nil {
+			return err
+		}
+		if len(mspIDs) == 0 {
+			return fmt.Errorf("agency %s must list at least one approver MSP", agencyID)
+		}
+		for _, mspID := range mspIDs {
+			if mspID == "" {
+				return fmt.Errorf("agency %s lists an empty approver MSP", agencyID)
+			}
+		}
+	}
+	settlementApprovers = approvers
+	return nil
+}
+
+// settlementApproverMSPs returns the MSPs allowed to approve a payor
+// agency's settlements: those set by SetSettlementApprovers, or the agency's
+// own MSP.
+func settlementApproverMSPs(payorAgencyID string) []string {
+	if mspIDs, ok := settlementApprovers[payorAgencyID]; ok {
+		return mspIDs
+	}
+	return []string{agencyMSPID(payorAgencyID)}
+}
+
+// ApproveSettlement records the submitting client's MSP as an approver of a
+// draft settlement. Only the payor's MSPs (see SetSettlementApprovers) may
+// approve; the payee and other agencies are rejected. A settlement with
+// approvalsRequired set cannot move to "submitted" until that many distinct
+// MSPs have approved it; an MSP that has already approved is rejected.
+func (c *SettlementContract) ApproveSettlement(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string) error {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to read client MSP ID: %w", err)
+	}
+
+	settlement, err := c.GetSettlement(ctx, settlementID, payorAgencyID, payeeAgencyID)
+	if err != nil {
+		return err
+	}
+	allowed := settlementApproverMSPs(settlement.PayorAgencyID)
+	if !models.Contains(allowed, mspID) {
+		return fmt.Errorf("%s cannot approve settlement %s: approvals must come from the payor %s (%v)",
+			mspID, settlementID, settlement.PayorAgencyID, allowed)
+	}
+	if err := settlement.Approve(mspID); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(settlement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settlement: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(settlement.CollectionName(), settlement.Key(), bytes)
+}
+
+// RecordSettlementPayment records an installment paid against an accepted
+// settlement. Payments accumulate in PaidAmount and may not exceed the net
+// amount. The settlement stays "accepted" until the payments cover the net
+// amount, then moves to "paid" and settles its charges. A charge that is
+// missing or cannot move to "settled" is skipped and recorded in the
+// settlement's warnings.
+func (c *SettlementContract) RecordSettlementPayment(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string, amount float64) error {
+	settlement, err := c.GetSettlement(ctx, settlementID, payorAgencyID, payeeAgencyID)
+	if err != nil {
+		return err
+	}
+
+	if settlement.Status != "accepted" {
+		return fmt.Errorf("payments can only be recorded against accepted settlements, settlement %s is %q", settlementID, settlement.Status)
+	}
+	if err := settlement.RecordPayment(amount); err != nil {
+		return fmt.Errorf("invalid payment: %w", err)
+	}
+
+	if settlement.FullyPaid() {
+		return c.moveToStatus(ctx, settlement, "paid")
+	}
+
+	bytes, err := json.Marshal(settlement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settlement: %w", err)
+	}
+
+	return ctx.GetStub().PutPrivateData(settlement.CollectionName(), settlement.Key(), bytes)
+}
+
+// SettleWithNoPaymentDue records a period in which two agencies net to zero.
+// The settlement must have a netAmount of 0; its gross amount and fees may be
+// nonzero. It is created and taken through submitted and accepted to paid in
+// one transaction, settling its charges as RecordSettlementPayment does, so
+// the period is reconciled even though no money moves.
+func (c *SettlementContract) SettleWithNoPaymentDue(ctx contractapi.TransactionContextInterface, settlementJSON string) error {
+	var settlement models.Settlement
+	if err := json.Unmarshal([]byte(settlementJSON), &settlement); err != nil {
+		return fmt.Errorf("failed to parse settlement JSON: %w", err)
+	}
+
+	if !settlement.IsNetZero() {
+		return fmt.Errorf("settlement %s has netAmount %.2f: only net-zero settlements can be settled with no payment due", settlement.SettlementID, settlement.NetAmount)
+	}
+
+	if err := c.createSettlement(ctx, &settlement); err != nil {
+		return err
+	}
+
+	// Fabric does not return a transaction's own writes to later reads, so
+	// the settlement is carried forward in memory rather than reloaded.
+	for _, status := range []string{"submitted", "accepted", "paid"} {
+		if err := c.moveToStatus(ctx, &settlement, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateSettlement builds a draft settlement of what payorAgencyID owes
+// payeeAgencyID for the period periodStart to periodEnd, inclusive YYYY-MM-DD
+// dates, from the charges settlementTotals selects.
+//
+// Generation is idempotent. If the payor already has a draft to the payee for
+// the period, it is returned unchanged and nothing is written. With
+// regenerate set, the existing drafts are deleted and replaced by the new
+// settlement, which lists them in Supersedes and must have a new
+// settlementID. A settlement in the same direction for the period that has
+// left draft blocks generation either way; the payee's settlements to the
+// payor are separate and never interfere.
+func (c *SettlementContract) GenerateSettlement(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string, periodStart string, periodEnd string, regenerate bool) (*models.Settlement, error) {
+	if _, err := time.Parse(time.DateOnly, periodStart); err != nil {
+		return nil, fmt.Errorf("invalid periodStart %q: must be a YYYY-MM-DD date", periodStart)
+	}
+	if _, err := time.Parse(time.DateOnly, periodEnd); err != nil {
+		return nil, fmt.Errorf("invalid periodEnd %q: must be a YYYY-MM-DD date", periodEnd)
+	}
+
+	existing, err := settlementsOwedForPeriod(ctx, payorAgencyID, payeeAgencyID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []*models.Settlement
+	for _, s := range existing {
+		if s.Status != "draft" {
+			return nil, fmt.Errorf("settlement %s for period %s to %s is %q: a settlement that has left draft cannot be regenerated", s.SettlementID, periodStart, periodEnd, s.Status)
+		}
+		drafts = append(drafts, s)
+	}
+	if len(drafts) > 0 && !regenerate {
+		return drafts[0], nil
+	}
+
+	settlement := &models.Settlement{
+		SettlementID:  settlementID,
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		PayorAgencyID: payorAgencyID,
+		PayeeAgencyID: payeeAgencyID,
+	}
+	for _, draft := range drafts {
+		if draft.SettlementID == settlementID {
+			return nil, fmt.Errorf("settlement %s is the draft being superseded: regenerate needs a new settlementID", settlementID)
+		}
+		settlement.Supersedes = append(settlement.Supersedes, draft.SettlementID)
+	}
+
+	if err := settlementTotals(ctx, settlement); err != nil {
+		return nil, err
+	}
+	if err := c.createSettlement(ctx, settlement); err != nil {
+		return nil, err
+	}
+
+	for _, draft := range drafts {
+		if err := ctx.GetStub().DelPrivateData(draft.CollectionName(), draft.Key()); err != nil {
+			return nil, fmt.Errorf("failed to delete superseded settlement %s: %w", draft.SettlementID, err)
+		}
+	}
+	return settlement, nil
+}
+
+// RecomputeSettlement refreshes a draft settlement's amounts, counts and
+// linked IDs from the charges and corrections now on the ledger, as
+// GenerateSettlement computes them, and returns the updated settlement.
+// Totals are fixed once a settlement is submitted, so only drafts can be
+// recomputed. Approvals are cleared when the totals or the linked charges
+// change.
+func (c *SettlementContract) RecomputeSettlement(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string) (*models.Settlement, error) {
+	settlement, err := c.GetSettlement(ctx, settlementID, payorAgencyID, payeeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.Status != "draft" {
+		return nil, fmt.Errorf("only draft settlements can be recomputed, settlement %s is %q", settlementID, settlement.Status)
+	}
+
+	before := *settlement
+	if err := settlementTotals(ctx, settlement); err != nil {
+		return nil, err
+	}
+	// Approvals were given for the old totals and charges, so changing
+	// either needs approving again. A charge swapped for another of the same
+	// amount leaves the totals alone but still changes what was approved.
+	if settlement.GrossAmount != before.GrossAmount || settlement.TotalFees != before.TotalFees ||
+		settlement.ChargeCount != before.ChargeCount || settlement.CorrectionCount != before.CorrectionCount ||
+		!sameIDs(settlement.ChargeIDs, before.ChargeIDs) {
+		settlement.Approvals = nil
+	}
+	if err := validateModel(settlement); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	bytes, err := json.Marshal(settlement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settlement: %w", err)
+	}
+	if err := ctx.GetStub().PutPrivateData(settlement.CollectionName(), settlement.Key(), bytes); err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// sameIDs reports whether two ID lists hold the same IDs, in any order.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// settlementTotals fills in a settlement's amounts, counts and linked IDs from
+// the posted charges its payor, as home agency, owes its payee for charges
+// exiting within the settlement period. Each charge counts at its amount with
+// its corrections applied. Deleted charges are left out.
+func settlementTotals(ctx contractapi.TransactionContextInterface, settlement *models.Settlement) error {
+	end, err := time.Parse(time.DateOnly, settlement.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("invalid periodEnd %q: must be a YYYY-MM-DD date", settlement.PeriodEnd)
+	}
+
+	// exitDateTime is an RFC3339 timestamp, so the day after the period is an
+	// exclusive upper bound for every time on its last day.
+	query, err := newRichQuery("charge", map[string]interface{}{
+		"homeAgencyID": settlement.PayorAgencyID,
+		"awayAgencyID": settlement.PayeeAgencyID,
+		"status":       "posted",
+		"exitDateTime": map[string]interface{}{
+			"$gte": settlement.PeriodStart,
+			"$lt":  end.AddDate(0, 0, 1).Format(time.DateOnly),
+		},
+		"deleted": map[string]interface{}{"$exists": false},
+	}).String()
+	if err != nil {
+		return err
+	}
+
+	charges, err := queryCharges(ctx, settlement.CollectionName(), query)
+	if err != nil {
+		return err
+	}
+
+	corrections := &CorrectionContract{}
+	settlement.GrossAmount = 0
+	settlement.TotalFees = 0
+	settlement.ChargeIDs = nil
+	settlement.CorrectionIDs = nil
+	for _, charge := range charges {
+		chargeCorrections, err := corrections.GetCorrectionsForCharge(ctx, charge.ChargeID, charge.AwayAgencyID, charge.HomeAgencyID)
+		if err != nil {
+			return err
+		}
+		settlement.GrossAmount += models.AdjustedAmount(charge.Amount, chargeCorrections)
+		settlement.TotalFees += charge.Fee
+		for _, correction := range chargeCorrections {
+			settlement.CorrectionIDs = append(settlement.CorrectionIDs, correction.CorrectionID)
+		}
+		settlement.ChargeIDs = append(settlement.ChargeIDs, charge.ChargeID)
+	}
+	settlement.ChargeCount = len(settlement.ChargeIDs)
+	settlement.CorrectionCount = len(settlement.CorrectionIDs)
+	settlement.GrossAmount = math.Round(settlement.GrossAmount*100) / 100
+	settlement.TotalFees = math.Round(settlement.TotalFees*100) / 100
+	settlement.NetAmount = math.Round((settlement.GrossAmount-settlement.TotalFees)*100) / 100
+	return nil
+}
+
+// GetSettlementHistory returns the statuses a settlement has held, oldest
+// first, with the time it entered each one.
+//
+// Settlements are private data, for which Fabric keeps no key history, so
+// the history is read from the settlement's own StatusHistory. A settlement
+// written before StatusHistory existed reports only its current status,
+// without a timestamp.
+func (c *SettlementContract) GetSettlementHistory(ctx contractapi.TransactionContextInterface, settlementID string, payorAgencyID string, payeeAgencyID string) ([]models.StatusChange, error) {
+	settlement, err := c.GetSettlement(ctx, settlementID, payorAgencyID, payeeAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(settlement.StatusHistory) == 0 {
+		return []models.StatusChange{{Status: settlement.Status}}, nil
+	}
+	return settlement.StatusHistory, nil
+}
+
+// chargesToSettle loads the settlement's charges and returns those that can
+// move to "settled". Ineligible charges are left out and a warning is appended
+// to the settlement. Nothing is written.
+func (c *SettlementContract) chargesToSettle(ctx contractapi.TransactionContextInterface, settlement *models.Settlement) ([]*models.Charge, error) {
+	var charges []*models.Charge
+	for _, chargeID := range settlement.ChargeIDs {
+		charge, err := findCharge(ctx, chargeID, settlement.PayorAgencyID, settlement.PayeeAgencyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load charge %s: %w", chargeID, err)
+		}
+		if charge == nil {
+			settlement.Warnings = append(settlement.Warnings, fmt.Sprintf("charge %s not found", chargeID))
+			continue
+		}
+		if err := charge.ValidateStatusTransition("settled"); err != nil {
+			settlement.Warnings = append(settlement.Warnings, fmt.Sprintf("charge %s not settled: %v", chargeID, err))
+			continue
+		}
+		charges = append(charges, charge)
+	}
+	return charges, nil
+}
+
 // GetSettlementsByAgencyPair returns all settlements between two agencies.
 func (c *SettlementContract) GetSettlementsByAgencyPair(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string) ([]*models.Settlement, error) {
-	// Determine collection name using alphabetical sort
-	a, b := agencyA, agencyB
-	if a > b {
-		a, b = b, a
-	}
-	collection := "charges_" + a + "_" + b
+	collection := models.BilateralCollectionName(agencyA, agencyB)
 
 	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collection, "SETTLEMENT_", "SETTLEMENT_~")
 	if err != nil {
@@ -120,7 +523,101 @@ func (c *SettlementContract) GetSettlementsByAgencyPair(ctx contractapi.Transact
 		}
 
 		var settlement models.Settlement
-		if err := json.Unmarshal(queryResponse.Value, &settlement); err != nil {
+		if err := decodeDocument("settlement", queryResponse.Value, &settlement); err != nil {
+			return nil, fmt.Errorf("failed to parse settlement: %w", err)
+		}
+		settlements = append(settlements, &settlement)
+	}
+
+	return settlements, nil
+}
+
+// GetSettlementForPeriod returns the settlement between two agencies for
+// exactly the period periodStart to periodEnd. Draft settlements are ignored.
+// A pair has at most one settlement per period beyond draft, so finding none
+// or more than one is an error.
+func (c *SettlementContract) GetSettlementForPeriod(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, periodStart string, periodEnd string) (*models.Settlement, error) {
+	settlements, err := settlementsForPeriod(ctx, agencyA, agencyB, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*models.Settlement
+	for _, s := range settlements {
+		if s.Status != "draft" {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no settlement between %s and %s for period %s to %s", agencyA, agencyB, periodStart, periodEnd)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, s := range matches {
+			ids[i] = s.SettlementID
+		}
+		return nil, fmt.Errorf("found %d settlements between %s and %s for period %s to %s, expected one: %v", len(matches), agencyA, agencyB, periodStart, periodEnd, ids)
+	}
+}
+
+// settlementsForPeriod returns every settlement, in any status, between two
+// agencies for exactly the period periodStart to periodEnd.
+func settlementsForPeriod(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, periodStart string, periodEnd string) ([]*models.Settlement, error) {
+	if periodStart == "" || periodEnd == "" {
+		return nil, fmt.Errorf("periodStart and periodEnd are required")
+	}
+
+	query, err := newRichQuery("settlement", map[string]interface{}{
+		"periodStart": periodStart,
+		"periodEnd":   periodEnd,
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return querySettlements(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// settlementsOwedForPeriod returns every settlement, in any status, of what
+// payorAgencyID owes payeeAgencyID for exactly the period periodStart to
+// periodEnd. Settlements in the other direction share the collection and are
+// left out.
+func settlementsOwedForPeriod(ctx contractapi.TransactionContextInterface, payorAgencyID string, payeeAgencyID string, periodStart string, periodEnd string) ([]*models.Settlement, error) {
+	query, err := newRichQuery("settlement", map[string]interface{}{
+		"payorAgencyID": payorAgencyID,
+		"payeeAgencyID": payeeAgencyID,
+		"periodStart":   periodStart,
+		"periodEnd":     periodEnd,
+	}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return querySettlements(ctx, models.BilateralCollectionName(payorAgencyID, payeeAgencyID), query)
+}
+
+// querySettlements runs a rich query against a bilateral collection and
+// decodes each result as a settlement. Returns an empty list when nothing
+// matches.
+func querySettlements(ctx contractapi.TransactionContextInterface, collection string, query string) ([]*models.Settlement, error) {
+	resultsIterator, err := ctx.GetStub().GetPrivateDataQueryResult(collection, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	settlements := []*models.Settlement{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		var settlement models.Settlement
+		if err := decodeDocument("settlement", queryResponse.Value, &settlement); err != nil {
 			return nil, fmt.Errorf("failed to parse settlement: %w", err)
 		}
 		settlements = append(settlements, &settlement)
@@ -131,7 +628,7 @@ func (c *SettlementContract) GetSettlementsByAgencyPair(ctx contractapi.Transact
 
 // GetSettlementsByStatus returns all settlements with a specific status for an agency pair.
 func (c *SettlementContract) GetSettlementsByStatus(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, status string) ([]*models.Settlement, error) {
-	if !contains(models.ValidSettlementStatuses, status) {
+	if !models.Contains(models.ValidSettlementStatuses, status) {
 		return nil, fmt.Errorf("invalid status %q: must be one of %v", status, models.ValidSettlementStatuses)
 	}
 
@@ -149,3 +646,199 @@ func (c *SettlementContract) GetSettlementsByStatus(ctx contractapi.TransactionC
 
 	return filtered, nil
 }
+
+// SettlementSortFields lists the settlement fields that
+// GetSettlementsByStatusSorted can sort on. Each has a (docType, status, field)
+// index in every bilateral collection under
+// META-INF/statedb/couchdb/collections.
+var SettlementSortFields = []string{"periodStart", "netAmount", "createdAt"}
+
+// GetSettlementsByStatusSorted returns settlements with a specific status for
+// an agency pair, sorted ascending by sortField and truncated to limit results.
+// An empty sortField leaves results unsorted; a limit of 0 returns all matches.
+func (c *SettlementContract) GetSettlementsByStatusSorted(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, status string, sortField string, limit int) ([]*models.Settlement, error) {
+	if !models.Contains(models.ValidSettlementStatuses, status) {
+		return nil, fmt.Errorf("invalid status %q: must be one of %v", status, models.ValidSettlementStatuses)
+	}
+	if err := validateSortAndLimit(sortField, SettlementSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("settlement", map[string]interface{}{"status": status}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return querySettlements(ctx, models.BilateralCollectionName(agencyA, agencyB), query)
+}
+
+// GetSettlementsInvolvingAgency returns the settlements between agencyID and
+// partnerID, whichever of the two is the payor. An empty statusFilter returns
+// settlements in every status; otherwise only those with that status are
+// returned.
+func (c *SettlementContract) GetSettlementsInvolvingAgency(ctx contractapi.TransactionContextInterface, agencyID string, partnerID string, statusFilter string) ([]*models.Settlement, error) {
+	if err := models.ValidateAgencyID("agencyID", agencyID); err != nil {
+		return nil, err
+	}
+	if err := models.ValidateAgencyID("partnerID", partnerID); err != nil {
+		return nil, err
+	}
+	if agencyID == partnerID {
+		return nil, fmt.Errorf("agency %s cannot be its own partner", agencyID)
+	}
+	if statusFilter != "" && !models.Contains(models.ValidSettlementStatuses, statusFilter) {
+		return nil, fmt.Errorf("invalid status %q: must be one of %v", statusFilter, models.ValidSettlementStatuses)
+	}
+
+	settlements, err := c.GetSettlementsByAgencyPair(ctx, agencyID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []*models.Settlement{}
+	for _, s := range settlements {
+		if statusFilter != "" && s.Status != statusFilter {
+			continue
+		}
+		matches = append(matches, s)
+	}
+
+	return matches, nil
+}
+
+// Issues flagged on a SettlementReconciliationReport line.
+const (
+	ReportIssueMissingReconciliation = "missing_reconciliation"
+	ReportIssueNotPosted             = "not_posted"
+	ReportIssueAmountMismatch        = "amount_mismatch"
+)
+
+// SettlementReconciliationLine compares one charge with the home agency's
+// reconciliation of it. Issue is empty when the charge was posted at its
+// charged amount. ClassDescription labels the charge's vehicle class from the
+// vehicle class table.
+type SettlementReconciliationLine struct {
+	ChargeID           string  `json:"chargeID"`
+	AwayAgencyID       string  `json:"awayAgencyID"`
+	HomeAgencyID       string  `json:"homeAgencyID"`
+	ExitDateTime       string  `json:"exitDateTime"`
+	VehicleClass       int     `json:"vehicleClass"`
+	ClassDescription   string  `json:"classDescription,omitempty"`
+	ChargedAmount      float64 `json:"chargedAmount"`
+	PostingDisposition string  `json:"postingDisposition,omitempty"`
+	PostedAmount       float64 `json:"postedAmount"`
+	Issue              string  `json:"issue,omitempty"`
+}
+
+// SettlementReconciliationReport is the result of
+// GetSettlementReconciliationReport.
+type SettlementReconciliationReport struct {
+	AgencyA      string                          `json:"agencyA"`
+	AgencyB      string                          `json:"agencyB"`
+	PeriodStart  string                          `json:"periodStart"`
+	PeriodEnd    string                          `json:"periodEnd"`
+	Lines        []*SettlementReconciliationLine `json:"lines"`
+	MatchedCount int                             `json:"matchedCount"`
+	IssueCount   int                             `json:"issueCount"`
+}
+
+// GetSettlementReconciliationReport reconciles, in both directions, what each
+// agency of a pair charged the other against what the other posted, before
+// the period is settled. periodStart and periodEnd are inclusive YYYY-MM-DD
+// dates matched against each charge's exit date. Every charge in the period
+// gets a line, flagged when its reconciliation is missing, did not post, or
+// posted a different amount. Deleted charges are left out.
+//
+// Charges are private data, so the report must be evaluated on a peer of
+// agencyA or agencyB that holds their collection.
+func (c *SettlementContract) GetSettlementReconciliationReport(ctx contractapi.TransactionContextInterface, agencyA string, agencyB string, periodStart string, periodEnd string) (*SettlementReconciliationReport, error) {
+	start, err := time.Parse(time.DateOnly, periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid periodStart %q: must be a YYYY-MM-DD date", periodStart)
+	}
+	end, err := time.Parse(time.DateOnly, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid periodEnd %q: must be a YYYY-MM-DD date", periodEnd)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("periodEnd %q must not be before periodStart %q", periodEnd, periodStart)
+	}
+
+	charges, err := (&ChargeContract{}).GetChargesByAgencyPair(ctx, agencyA, agencyB)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SettlementReconciliationReport{
+		AgencyA:     agencyA,
+		AgencyB:     agencyB,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Lines:       []*SettlementReconciliationLine{},
+	}
+	for _, charge := range charges {
+		exitDate := charge.ExitDateTime
+		if len(exitDate) > len(time.DateOnly) {
+			exitDate = exitDate[:len(time.DateOnly)]
+		}
+		if exitDate < periodStart || exitDate > periodEnd {
+			continue
+		}
+
+		line, err := reconciliationReportLine(ctx, charge)
+		if err != nil {
+			return nil, err
+		}
+		if line.Issue == "" {
+			report.MatchedCount++
+		} else {
+			report.IssueCount++
+		}
+		report.Lines = append(report.Lines, line)
+	}
+
+	return report, nil
+}
+
+// reconciliationReportLine builds the report line for a charge from its
+// charge-level reconciliation.
+func reconciliationReportLine(ctx contractapi.TransactionContextInterface, charge *models.Charge) (*SettlementReconciliationLine, error) {
+	line := &SettlementReconciliationLine{
+		ChargeID:      charge.ChargeID,
+		AwayAgencyID:  charge.AwayAgencyID,
+		HomeAgencyID:  charge.HomeAgencyID,
+		ExitDateTime:  charge.ExitDateTime,
+		VehicleClass:  charge.VehicleClass,
+		ChargedAmount: charge.Amount,
+	}
+	if vc, ok := models.LookupVehicleClass(charge.VehicleClass); ok {
+		line.ClassDescription = vc.Description
+	}
+
+	bytes, err := ctx.GetStub().GetState(models.ReconciliationKey(charge.ChargeID, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if bytes == nil {
+		line.Issue = ReportIssueMissingReconciliation
+		return line, nil
+	}
+
+	var recon models.Reconciliation
+	if err := decodeDocument("reconciliation", bytes, &recon); err != nil {
+		return nil, fmt.Errorf("failed to parse reconciliation: %w", err)
+	}
+	line.PostingDisposition = recon.PostingDisposition
+	line.PostedAmount = recon.PostedAmount
+
+	switch {
+	case !recon.IsPosted():
+		line.Issue = ReportIssueNotPosted
+	case recon.HasAmountMismatch(charge):
+		line.Issue = ReportIssueAmountMismatch
+	}
+	return line, nil
+}
@@ -93,8 +93,99 @@ func TestCreateSettlement(t *testing.T) {
 
 		err := contract.CreateSettlement(ctx, string(settlementJSON))
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid status")
+		assert.Contains(t, err.Error(), "new settlements must start in draft")
 	})
+
+	t.Run("rejects submitted status", func(t *testing.T) {
+		ctx := newMockContext()
+		settlement := validSettlement()
+		settlement.Status = "submitted"
+		settlementJSON, _ := json.Marshal(settlement)
+
+		err := contract.CreateSettlement(ctx, string(settlementJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "new settlements must start in draft")
+	})
+
+	t.Run("defaults empty status to draft", func(t *testing.T) {
+		ctx := newMockContext()
+		settlement := validSettlement()
+		settlement.Status = ""
+		settlementJSON, _ := json.Marshal(settlement)
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "draft", stored.Status)
+	})
+}
+
+// settlementFixture describes a settlement to store before a test. The status
+// defaults to draft.
+type settlementFixture struct {
+	id     string
+	status string
+	modify func(*models.Settlement)
+}
+
+// newContextWithSettlements returns a context holding a settlement for each
+// fixture.
+func newContextWithSettlements(t *testing.T, fixtures ...settlementFixture) *enhancedMockContext {
+	t.Helper()
+	ctx := newMockContext()
+	for _, f := range fixtures {
+		settlement := validSettlement()
+		settlement.SettlementID = f.id
+		if f.modify != nil {
+			f.modify(settlement)
+		}
+		status := f.status
+		if status == "" {
+			status = "draft"
+		}
+		createSettlementWithStatus(t, ctx, settlement, status)
+	}
+	return ctx
+}
+
+// newContextWithAcceptedSettlement returns a context holding the given charges
+// and an accepted SETTLE-TEST-001 covering them.
+func newContextWithAcceptedSettlement(t *testing.T, charges ...chargeFixture) *enhancedMockContext {
+	t.Helper()
+	ctx := newContextWithCharges(t, charges...)
+	settlement := validSettlement()
+	for _, c := range charges {
+		settlement.ChargeIDs = append(settlement.ChargeIDs, c.id)
+	}
+	settlement.ChargeCount = len(settlement.ChargeIDs)
+	createSettlementWithStatus(t, ctx, settlement, "accepted")
+	return ctx
+}
+
+// createSettlementWithStatus creates settlement in draft and moves it through
+// the workflow to status. A paid settlement is paid in full with
+// RecordSettlementPayment.
+func createSettlementWithStatus(t *testing.T, ctx *enhancedMockContext, settlement *models.Settlement, status string) {
+	t.Helper()
+	contract := &SettlementContract{}
+	settlement.Status = "draft"
+	settlementJSON, _ := json.Marshal(settlement)
+	require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+
+	path := map[string][]string{
+		"draft":     nil,
+		"submitted": {"submitted"},
+		"accepted":  {"submitted", "accepted"},
+		"paid":      {"submitted", "accepted"},
+	}
+	steps, ok := path[status]
+	require.True(t, ok, "no workflow path to %q", status)
+	for _, step := range steps {
+		require.NoError(t, contract.UpdateSettlementStatus(ctx, settlement.SettlementID, settlement.PayorAgencyID, settlement.PayeeAgencyID, step))
+	}
+	if status == "paid" {
+		require.NoError(t, contract.RecordSettlementPayment(ctx, settlement.SettlementID, settlement.PayorAgencyID, settlement.PayeeAgencyID, settlement.NetAmount))
+	}
 }
 
 func TestGetSettlement(t *testing.T) {
@@ -159,12 +250,27 @@ func TestUpdateSettlementStatus(t *testing.T) {
 		settlementJSON, _ := json.Marshal(settlement)
 		_ = contract.CreateSettlement(ctx, string(settlementJSON))
 
-		// draft -> paid is NOT allowed (must go through submitted, accepted)
-		err := contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "paid")
+		// draft -> accepted is NOT allowed (must go through submitted)
+		err := contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "accepted")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot transition")
 	})
 
+	t.Run("rejects paid", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, validSettlement(), "accepted")
+
+		err := contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "paid")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be marked paid directly")
+		assert.Contains(t, err.Error(), "RecordSettlementPayment")
+		assert.Contains(t, err.Error(), "SettleWithNoPaymentDue")
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", result.Status)
+	})
+
 	t.Run("full lifecycle: draft -> submitted -> accepted -> paid", func(t *testing.T) {
 		ctx := newMockContext()
 		settlement := validSettlement()
@@ -177,12 +283,649 @@ func TestUpdateSettlementStatus(t *testing.T) {
 		err = contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "accepted")
 		require.NoError(t, err)
 
-		err = contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "paid")
+		err = contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 14850.00)
+		require.NoError(t, err)
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "paid", result.Status)
+	})
+
+	t.Run("accepting a net-zero generated settlement marks it paid", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		charge.Fee = 0
+		charge.NetAmount = charge.Amount
+		createChargeWithStatus(t, ctx, charge, "posted")
+		correction := validCorrection()
+		correction.Amount = -charge.Amount
+		correctionJSON, _ := json.Marshal(correction)
+		require.NoError(t, (&CorrectionContract{}).CreateCorrection(ctx, string(correctionJSON)))
+
+		settlement, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		require.True(t, settlement.IsNetZero())
+
+		require.NoError(t, contract.UpdateSettlementStatus(ctx, "SETTLE-JAN", "ORG1", "ORG2", "submitted"))
+		require.NoError(t, contract.UpdateSettlementStatus(ctx, "SETTLE-JAN", "ORG1", "ORG2", "accepted"))
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "paid", result.Status)
+		require.Len(t, result.StatusHistory, 4)
+		assert.Equal(t, "accepted", result.StatusHistory[2].Status)
+
+		settled, err := (&ChargeContract{}).GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "settled", settled.Status)
+	})
+}
+
+func TestGetSettlementHistory(t *testing.T) {
+	contract := &SettlementContract{}
+
+	t.Run("records each status through the lifecycle", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, validSettlement(), "paid")
+
+		history, err := contract.GetSettlementHistory(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		require.Len(t, history, 4)
+		for i, status := range []string{"draft", "submitted", "accepted", "paid"} {
+			assert.Equal(t, status, history[i].Status)
+			assert.NotEmpty(t, history[i].ChangedAt)
+		}
+	})
+
+	t.Run("rejected transition is not recorded", func(t *testing.T) {
+		ctx := newMockContext()
+		settlementJSON, _ := json.Marshal(validSettlement())
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+		require.Error(t, contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "accepted"))
+
+		history, err := contract.GetSettlementHistory(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("settlement without recorded history", func(t *testing.T) {
+		ctx := newMockContext()
+		legacy := validSettlement()
+		legacy.Status = "accepted"
+		legacyJSON, _ := json.Marshal(legacy)
+		require.NoError(t, ctx.stub.PutPrivateData("charges_ORG1_ORG2", "SETTLEMENT_SETTLE-TEST-001", legacyJSON))
+
+		history, err := contract.GetSettlementHistory(ctx, "SETTLE-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, []models.StatusChange{{Status: "accepted"}}, history)
+	})
+
+	t.Run("missing settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetSettlementHistory(ctx, "SETTLE-404", "ORG1", "ORG2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestRecordSettlementPayment_SettlesCharges(t *testing.T) {
+	contract := &SettlementContract{}
+
+	t.Run("marks posted charges settled", func(t *testing.T) {
+		ctx := newContextWithAcceptedSettlement(t,
+			chargeFixture{id: "CHG-A", status: "posted"},
+			chargeFixture{id: "CHG-B", status: "disputed"},
+		)
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 14850.00))
+
+		for _, id := range []string{"CHG-A", "CHG-B"} {
+			charge, err := (&ChargeContract{}).GetCharge(ctx, id, "ORG2", "ORG1")
+			require.NoError(t, err)
+			assert.Equal(t, "settled", charge.Status, id)
+		}
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "paid", result.Status)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("reports ineligible charges", func(t *testing.T) {
+		ctx := newContextWithAcceptedSettlement(t,
+			chargeFixture{id: "CHG-A", status: "posted"},
+			chargeFixture{id: "CHG-P", status: "pending"},
+		)
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 14850.00))
+
+		pending, err := (&ChargeContract{}).GetCharge(ctx, "CHG-P", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "pending", pending.Status)
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "charge CHG-P not settled")
+	})
+
+	t.Run("reports missing charges", func(t *testing.T) {
+		ctx := newMockContext()
+		settlement := validSettlement()
+		settlement.ChargeIDs = []string{"CHG-MISSING"}
+		settlement.ChargeCount = 1
+		createSettlementWithStatus(t, ctx, settlement, "accepted")
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 14850.00))
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"charge CHG-MISSING not found"}, result.Warnings)
+	})
+
+	t.Run("leaves charges alone for other transitions", func(t *testing.T) {
+		ctx := newContextWithCharges(t, chargeFixture{id: "CHG-A", status: "posted"})
+		settlement := validSettlement()
+		settlement.ChargeIDs = []string{"CHG-A"}
+		settlement.ChargeCount = 1
+		settlementJSON, _ := json.Marshal(settlement)
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+
+		require.NoError(t, contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "submitted"))
+
+		charge, err := (&ChargeContract{}).GetCharge(ctx, "CHG-A", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "posted", charge.Status)
+	})
+}
+
+func TestRecordSettlementPayment(t *testing.T) {
+	contract := &SettlementContract{}
+
+	t.Run("single full payment marks settlement paid", func(t *testing.T) {
+		ctx := newContextWithAcceptedSettlement(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 14850.00))
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "paid", result.Status)
+		assert.InDelta(t, 14850.00, result.PaidAmount, 0.001)
+
+		charge, err := (&ChargeContract{}).GetCharge(ctx, "CHG-TEST-001", "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "settled", charge.Status)
+	})
+
+	t.Run("partial payments stay accepted until the net is covered", func(t *testing.T) {
+		ctx := newContextWithAcceptedSettlement(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 10000.00))
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", result.Status)
+		assert.InDelta(t, 10000.00, result.PaidAmount, 0.001)
+
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 4850.00))
+		result, err = contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
 		require.NoError(t, err)
+		assert.Equal(t, "paid", result.Status)
+		assert.InDelta(t, 14850.00, result.PaidAmount, 0.001)
+	})
+
+	t.Run("rejects overpayment", func(t *testing.T) {
+		ctx := newContextWithAcceptedSettlement(t, chargeFixture{id: "CHG-TEST-001", status: "posted"})
+		require.NoError(t, contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 10000.00))
+
+		err := contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 5000.00)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "would exceed netAmount")
+
+		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.InDelta(t, 10000.00, result.PaidAmount, 0.001)
+	})
+
+	t.Run("rejects payment before acceptance", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, validSettlement(), "submitted")
+
+		err := contract.RecordSettlementPayment(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", 100.00)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only be recorded against accepted settlements")
+	})
+
+	t.Run("returns error for nonexistent settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		err := contract.RecordSettlementPayment(ctx, "NONEXISTENT", "ORG1", "ORG2", 100.00)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestSettleWithNoPaymentDue(t *testing.T) {
+	contract := &SettlementContract{}
+
+	netZeroSettlement := func() *models.Settlement {
+		settlement := validSettlement()
+		settlement.GrossAmount = 150.00
+		settlement.TotalFees = 150.00
+		settlement.NetAmount = 0
+		return settlement
+	}
+
+	t.Run("records a paid zero settlement and settles its charges", func(t *testing.T) {
+		ctx := newMockContext()
+		charge := validCharge()
+		createChargeWithStatus(t, ctx, charge, "posted")
+
+		settlement := netZeroSettlement()
+		settlement.ChargeIDs = []string{charge.ChargeID}
+		settlement.ChargeCount = 1
+		settlementJSON, _ := json.Marshal(settlement)
+
+		require.NoError(t, contract.SettleWithNoPaymentDue(ctx, string(settlementJSON)))
 
 		result, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
 		require.NoError(t, err)
 		assert.Equal(t, "paid", result.Status)
+		assert.InDelta(t, 0, result.PaidAmount, 0.0001)
+		var statuses []string
+		for _, change := range result.StatusHistory {
+			statuses = append(statuses, change.Status)
+		}
+		assert.Equal(t, []string{"draft", "submitted", "accepted", "paid"}, statuses)
+
+		stored, err := (&ChargeContract{}).GetCharge(ctx, charge.ChargeID, "ORG2", "ORG1")
+		require.NoError(t, err)
+		assert.Equal(t, "settled", stored.Status)
+	})
+
+	t.Run("rejects a settlement with money owed", func(t *testing.T) {
+		ctx := newMockContext()
+		settlementJSON, _ := json.Marshal(validSettlement())
+
+		err := contract.SettleWithNoPaymentDue(ctx, string(settlementJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only net-zero settlements")
+
+		_, err = contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		assert.Error(t, err, "nothing should be written")
+	})
+
+	t.Run("rejects an existing settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		settlementJSON, _ := json.Marshal(netZeroSettlement())
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+
+		err := contract.SettleWithNoPaymentDue(ctx, string(settlementJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+}
+
+func TestGenerateSettlement(t *testing.T) {
+	contract := &SettlementContract{}
+
+	chargeFor := func(id string, amount float64, fee float64, exit string) *models.Charge {
+		charge := validCharge()
+		charge.ChargeID = id
+		charge.Amount = amount
+		charge.Fee = fee
+		charge.NetAmount = amount - fee
+		charge.ExitDateTime = exit
+		return charge
+	}
+
+	t.Run("first run totals the period's posted charges", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-1", 4.75, 0.05, "2026-01-01T00:00:00Z"), "posted")
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-2", 2.50, 0.05, "2026-01-31T23:59:59Z"), "posted")
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-PENDING", 9.00, 0.05, "2026-01-10T12:00:00Z"), "pending")
+		createChargeWithStatus(t, ctx, chargeFor("CHG-FEB", 9.00, 0.05, "2026-02-01T00:00:00Z"), "posted")
+
+		settlement, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		assert.Equal(t, "draft", settlement.Status)
+		assert.ElementsMatch(t, []string{"CHG-JAN-1", "CHG-JAN-2"}, settlement.ChargeIDs)
+		assert.Equal(t, 2, settlement.ChargeCount)
+		assert.InDelta(t, 7.25, settlement.GrossAmount, 0.0001)
+		assert.InDelta(t, 0.10, settlement.TotalFees, 0.0001)
+		assert.InDelta(t, 7.15, settlement.NetAmount, 0.0001)
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, settlement.ChargeIDs, stored.ChargeIDs)
+	})
+
+	t.Run("applies corrections and links them", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, chargeFor("CHG-TEST-001", 4.75, 0.05, "2026-01-15T08:30:00Z"), "posted")
+		correction := validCorrection()
+		correction.Amount = -1.00
+		correctionJSON, _ := json.Marshal(correction)
+		require.NoError(t, (&CorrectionContract{}).CreateCorrection(ctx, string(correctionJSON)))
+
+		settlement, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CORR-TEST-001"}, settlement.CorrectionIDs)
+		assert.Equal(t, 1, settlement.CorrectionCount)
+		assert.InDelta(t, 3.75, settlement.GrossAmount, 0.0001)
+		assert.InDelta(t, 3.70, settlement.NetAmount, 0.0001)
+	})
+
+	t.Run("re-run returns the existing draft", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-1", 4.75, 0.05, "2026-01-15T08:30:00Z"), "posted")
+
+		first, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+
+		second, err := contract.GenerateSettlement(ctx, "SETTLE-JAN-RERUN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		assert.Equal(t, first.SettlementID, second.SettlementID)
+
+		settlements, err := contract.GetSettlementsByAgencyPair(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Len(t, settlements, 1)
+	})
+
+	t.Run("regenerate supersedes the prior draft", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-1", 4.75, 0.05, "2026-01-15T08:30:00Z"), "posted")
+		_, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-2", 2.50, 0.05, "2026-01-20T08:30:00Z"), "posted")
+		settlement, err := contract.GenerateSettlement(ctx, "SETTLE-JAN-V2", "ORG1", "ORG2", "2026-01-01", "2026-01-31", true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SETTLE-JAN"}, settlement.Supersedes)
+		assert.Equal(t, 2, settlement.ChargeCount)
+		assert.InDelta(t, 7.25, settlement.GrossAmount, 0.0001)
+
+		_, err = contract.GetSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2")
+		assert.Error(t, err, "superseded draft should be deleted")
+		settlements, err := contract.GetSettlementsByAgencyPair(ctx, "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Len(t, settlements, 1)
+	})
+
+	t.Run("regenerate requires a new settlementID", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+
+		_, err = contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs a new settlementID")
+	})
+
+	t.Run("non-draft settlement blocks generation", func(t *testing.T) {
+		ctx := newMockContext()
+		settlement := validSettlement()
+		settlement.SettlementID = "SETTLE-JAN"
+		createSettlementWithStatus(t, ctx, settlement, "submitted")
+
+		for _, regenerate := range []bool{false, true} {
+			_, err := contract.GenerateSettlement(ctx, "SETTLE-JAN-V2", "ORG1", "ORG2", "2026-01-01", "2026-01-31", regenerate)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cannot be regenerated")
+		}
+	})
+
+	t.Run("ignores settlements in the other direction", func(t *testing.T) {
+		ctx := newMockContext()
+		submitted := validSettlement()
+		submitted.SettlementID = "SETTLE-JAN-REV"
+		submitted.PayorAgencyID, submitted.PayeeAgencyID = "ORG2", "ORG1"
+		createSettlementWithStatus(t, ctx, submitted, "submitted")
+		_, err := contract.GenerateSettlement(ctx, "SETTLE-JAN-REV-V2", "ORG2", "ORG1", "2026-01-01", "2026-01-31", false)
+		require.Error(t, err, "the reverse direction's own settlement should still block it")
+
+		settlement, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		assert.Equal(t, "SETTLE-JAN", settlement.SettlementID)
+		assert.Equal(t, "ORG1", settlement.PayorAgencyID)
+
+		draft := validSettlement()
+		draft.SettlementID = "SETTLE-FEB-REV"
+		draft.PayorAgencyID, draft.PayeeAgencyID = "ORG2", "ORG1"
+		draft.PeriodStart, draft.PeriodEnd = "2026-02-01", "2026-02-28"
+		createSettlementWithStatus(t, ctx, draft, "draft")
+
+		settlement, err = contract.GenerateSettlement(ctx, "SETTLE-FEB", "ORG1", "ORG2", "2026-02-01", "2026-02-28", false)
+		require.NoError(t, err)
+		assert.Equal(t, "SETTLE-FEB", settlement.SettlementID, "the reverse draft is not this direction's draft")
+		assert.Equal(t, "ORG1", settlement.PayorAgencyID)
+	})
+}
+
+func TestRecomputeSettlement(t *testing.T) {
+	contract := &SettlementContract{}
+
+	chargeFor := func(id string, amount float64, exit string) *models.Charge {
+		charge := validCharge()
+		charge.ChargeID = id
+		charge.Amount = amount
+		charge.NetAmount = amount - charge.Fee
+		charge.ExitDateTime = exit
+		return charge
+	}
+
+	t.Run("picks up a charge added after generation", func(t *testing.T) {
+		ctx := newMockContext()
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-1", 4.75, "2026-01-15T08:30:00Z"), "posted")
+		generated, err := contract.GenerateSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2", "2026-01-01", "2026-01-31", false)
+		require.NoError(t, err)
+		assert.InDelta(t, 4.75, generated.GrossAmount, 0.0001)
+
+		createChargeWithStatus(t, ctx, chargeFor("CHG-JAN-2", 2.50, "2026-01-20T08:30:00Z"), "posted")
+		settlement, err := contract.RecomputeSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, settlement.ChargeCount)
+		assert.InDelta(t, 7.25, settlement.GrossAmount, 0.0001)
+		assert.InDelta(t, 0.10, settlement.TotalFees, 0.0001)
+		assert.InDelta(t, 7.15, settlement.NetAmount, 0.0001)
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-JAN", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-JAN-1", "CHG-JAN-2"}, stored.ChargeIDs)
+		assert.Equal(t, "draft", stored.Status)
+	})
+
+	t.Run("rejects non-draft settlements", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, validSettlement(), "submitted")
+
+		_, err := contract.RecomputeSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only draft settlements can be recomputed")
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, 3000, stored.ChargeCount)
+	})
+
+	t.Run("errors when the settlement does not exist", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.RecomputeSettlement(ctx, "SETTLE-MISSING", "ORG1", "ORG2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+// withSettlementApprovers sets the settlement approvers for the duration of
+// a test.
+func withSettlementApprovers(t *testing.T, approvers map[string][]string) {
+	t.Helper()
+	original := settlementApprovers
+	require.NoError(t, SetSettlementApprovers(approvers))
+	t.Cleanup(func() { settlementApprovers = original })
+}
+
+func TestApproveSettlement(t *testing.T) {
+	contract := &SettlementContract{}
+
+	createRequiring := func(t *testing.T, ctx *enhancedMockContext, approvals int) {
+		settlement := validSettlement()
+		settlement.ApprovalsRequired = approvals
+		settlementJSON, _ := json.Marshal(settlement)
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+	}
+	approveAs := func(ctx *enhancedMockContext, mspID string) error {
+		ctx.clientMSPID = mspID
+		return contract.ApproveSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+	}
+	submit := func(ctx *enhancedMockContext) error {
+		return contract.UpdateSettlementStatus(ctx, "SETTLE-TEST-001", "ORG1", "ORG2", "submitted")
+	}
+
+	t.Run("single approval", func(t *testing.T) {
+		ctx := newMockContext()
+		createRequiring(t, ctx, 1)
+
+		err := submit(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs 1 approvals before submission, has 0")
+
+		require.NoError(t, approveAs(ctx, "ORG1MSP"))
+		require.NoError(t, submit(ctx))
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Equal(t, "submitted", stored.Status)
+		require.Len(t, stored.Approvals, 1)
+		assert.Equal(t, "ORG1MSP", stored.Approvals[0].MSPID)
+	})
+
+	t.Run("dual control", func(t *testing.T) {
+		withSettlementApprovers(t, map[string][]string{"ORG1": {"ORG1MSP", "ORG1-TREASURYMSP"}})
+		ctx := newMockContext()
+		createRequiring(t, ctx, 2)
+
+		require.NoError(t, approveAs(ctx, "ORG1MSP"))
+		err := approveAs(ctx, "ORG1MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already been approved by ORG1MSP")
+
+		err = submit(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs 2 approvals before submission, has 1")
+
+		require.NoError(t, approveAs(ctx, "ORG1-TREASURYMSP"))
+		require.NoError(t, submit(ctx))
+	})
+
+	t.Run("rejects approvals from outside the payor", func(t *testing.T) {
+		ctx := newMockContext()
+		createRequiring(t, ctx, 1)
+
+		for _, mspID := range []string{"ORG2MSP", "ORG3MSP", "ORG1-TREASURYMSP"} {
+			err := approveAs(ctx, mspID)
+			require.Error(t, err, mspID)
+			assert.Contains(t, err.Error(), "approvals must come from the payor ORG1")
+		}
+
+		stored, err := contract.GetSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Empty(t, stored.Approvals)
+	})
+
+	t.Run("configured approvers replace the payor's own MSP", func(t *testing.T) {
+		withSettlementApprovers(t, map[string][]string{"ORG1": {"ORG1-TREASURYMSP"}})
+		ctx := newMockContext()
+		createRequiring(t, ctx, 1)
+
+		assert.Error(t, approveAs(ctx, "ORG1MSP"))
+		assert.NoError(t, approveAs(ctx, "ORG1-TREASURYMSP"))
+	})
+
+	t.Run("settlements without a requirement submit as before", func(t *testing.T) {
+		ctx := newMockContext()
+		createRequiring(t, ctx, 0)
+
+		assert.NoError(t, submit(ctx))
+	})
+
+	t.Run("rejects approving a submitted settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		createRequiring(t, ctx, 0)
+		require.NoError(t, submit(ctx))
+
+		err := approveAs(ctx, "ORG1MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only draft settlements can be approved")
+	})
+
+	t.Run("ignores approvals supplied at creation", func(t *testing.T) {
+		ctx := newMockContext()
+		settlement := validSettlement()
+		settlement.ApprovalsRequired = 1
+		settlement.Approvals = []models.Approval{{MSPID: "ORG1MSP", ApprovedAt: "2026-01-01T00:00:00Z"}}
+		settlementJSON, _ := json.Marshal(settlement)
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+
+		assert.Error(t, submit(ctx))
+	})
+
+	t.Run("recomputing with changed totals clears approvals", func(t *testing.T) {
+		ctx := newMockContext()
+		createRequiring(t, ctx, 1)
+		require.NoError(t, approveAs(ctx, "ORG1MSP"))
+
+		settlement, err := contract.RecomputeSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.Empty(t, settlement.Approvals)
+		assert.Error(t, submit(ctx))
+	})
+
+	// createCovering creates a settlement covering chargeIDs, with totals as
+	// if each were a posted charge of validCharge's amounts.
+	createCovering := func(t *testing.T, ctx *enhancedMockContext, chargeIDs ...string) {
+		settlement := validSettlement()
+		settlement.ApprovalsRequired = 1
+		settlement.GrossAmount = 4.75 * float64(len(chargeIDs))
+		settlement.TotalFees = 0.05 * float64(len(chargeIDs))
+		settlement.NetAmount = settlement.GrossAmount - settlement.TotalFees
+		settlement.ChargeCount = len(chargeIDs)
+		settlement.CorrectionCount = 0
+		settlement.ChargeIDs = chargeIDs
+		settlementJSON, _ := json.Marshal(settlement)
+		require.NoError(t, contract.CreateSettlement(ctx, string(settlementJSON)))
+	}
+	postCharge := func(t *testing.T, ctx *enhancedMockContext, chargeID string) {
+		charge := validCharge()
+		charge.ChargeID = chargeID
+		charge.ExitDateTime = "2026-01-15T08:30:00Z"
+		createChargeWithStatus(t, ctx, charge, "posted")
+	}
+
+	t.Run("recomputing with a swapped charge clears approvals", func(t *testing.T) {
+		ctx := newMockContext()
+		createCovering(t, ctx, "CHG-OLD")
+		postCharge(t, ctx, "CHG-NEW")
+		require.NoError(t, approveAs(ctx, "ORG1MSP"))
+
+		settlement, err := contract.RecomputeSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.InDelta(t, 4.75, settlement.GrossAmount, 0.0001)
+		assert.Equal(t, []string{"CHG-NEW"}, settlement.ChargeIDs)
+		assert.Empty(t, settlement.Approvals)
+		assert.Error(t, submit(ctx))
+	})
+
+	t.Run("recomputing the same charges in another order keeps approvals", func(t *testing.T) {
+		ctx := newMockContext()
+		postCharge(t, ctx, "CHG-A")
+		postCharge(t, ctx, "CHG-B")
+		createCovering(t, ctx, "CHG-B", "CHG-A")
+		require.NoError(t, approveAs(ctx, "ORG1MSP"))
+
+		settlement, err := contract.RecomputeSettlement(ctx, "SETTLE-TEST-001", "ORG1", "ORG2")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CHG-A", "CHG-B"}, settlement.ChargeIDs)
+		assert.Len(t, settlement.Approvals, 1)
+		assert.NoError(t, submit(ctx))
 	})
 }
 
@@ -230,6 +973,48 @@ func TestGetSettlementsByAgencyPair(t *testing.T) {
 	})
 }
 
+func TestGetSettlementForPeriod(t *testing.T) {
+	contract := &SettlementContract{}
+
+	settlementFor := func(id string, periodStart string, periodEnd string) *models.Settlement {
+		settlement := validSettlement()
+		settlement.SettlementID = id
+		settlement.PeriodStart = periodStart
+		settlement.PeriodEnd = periodEnd
+		return settlement
+	}
+
+	t.Run("returns the unique non-draft settlement", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-JAN", "2026-01-01", "2026-01-31"), "submitted")
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-JAN-DRAFT", "2026-01-01", "2026-01-31"), "draft")
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-FEB", "2026-02-01", "2026-02-28"), "accepted")
+
+		settlement, err := contract.GetSettlementForPeriod(ctx, "ORG2", "ORG1", "2026-01-01", "2026-01-31")
+		require.NoError(t, err)
+		assert.Equal(t, "SETTLE-JAN", settlement.SettlementID)
+	})
+
+	t.Run("errors when there is none", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-JAN-DRAFT", "2026-01-01", "2026-01-31"), "draft")
+
+		_, err := contract.GetSettlementForPeriod(ctx, "ORG1", "ORG2", "2026-01-01", "2026-01-31")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no settlement between ORG1 and ORG2")
+	})
+
+	t.Run("errors when the period is ambiguous", func(t *testing.T) {
+		ctx := newMockContext()
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-JAN-1", "2026-01-01", "2026-01-31"), "submitted")
+		createSettlementWithStatus(t, ctx, settlementFor("SETTLE-JAN-2", "2026-01-01", "2026-01-31"), "paid")
+
+		_, err := contract.GetSettlementForPeriod(ctx, "ORG1", "ORG2", "2026-01-01", "2026-01-31")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "found 2 settlements")
+	})
+}
+
 func TestGetSettlementsByStatus(t *testing.T) {
 	contract := &SettlementContract{}
 
@@ -276,6 +1061,117 @@ func TestGetSettlementsByStatus(t *testing.T) {
 	})
 }
 
+func TestGetSettlementsByStatusSorted(t *testing.T) {
+	contract := &SettlementContract{}
+
+	period := func(start, end string) func(*models.Settlement) {
+		return func(s *models.Settlement) {
+			s.PeriodStart = start
+			s.PeriodEnd = end
+		}
+	}
+	fixtures := []settlementFixture{
+		{id: "SETTLE-MAR", status: "submitted", modify: period("2026-03-01", "2026-03-31")},
+		{id: "SETTLE-JAN", status: "submitted", modify: period("2026-01-01", "2026-01-31")},
+		{id: "SETTLE-FEB", status: "submitted", modify: period("2026-02-01", "2026-02-28")},
+		{id: "SETTLE-APR", modify: period("2026-04-01", "2026-04-30")},
+	}
+
+	t.Run("sorts by period start", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+		result, err := contract.GetSettlementsByStatusSorted(ctx, "ORG1", "ORG2", "submitted", "periodStart", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SETTLE-JAN", "SETTLE-FEB", "SETTLE-MAR"}, settlementIDs(result))
+	})
+
+	t.Run("sorts and truncates", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+		result, err := contract.GetSettlementsByStatusSorted(ctx, "ORG2", "ORG1", "submitted", "periodStart", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SETTLE-JAN", "SETTLE-FEB"}, settlementIDs(result))
+	})
+
+	t.Run("unsorted and unlimited by default", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+		result, err := contract.GetSettlementsByStatusSorted(ctx, "ORG1", "ORG2", "draft", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SETTLE-APR"}, settlementIDs(result))
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetSettlementsByStatusSorted(ctx, "ORG1", "ORG2", "submitted", "payorAgencyID", 0)
+		assert.ErrorContains(t, err, "invalid sortField")
+
+		_, err = contract.GetSettlementsByStatusSorted(ctx, "ORG1", "ORG2", "submitted", "netAmount", -1)
+		assert.ErrorContains(t, err, "limit must be >= 0")
+
+		_, err = contract.GetSettlementsByStatusSorted(ctx, "ORG1", "ORG2", "bogus", "", 0)
+		assert.ErrorContains(t, err, "invalid status")
+	})
+}
+
+func TestGetSettlementsInvolvingAgency(t *testing.T) {
+	contract := &SettlementContract{}
+
+	between := func(payor, payee, periodStart, periodEnd string) func(*models.Settlement) {
+		return func(s *models.Settlement) {
+			s.PayorAgencyID = payor
+			s.PayeeAgencyID = payee
+			s.PeriodStart = periodStart
+			s.PeriodEnd = periodEnd
+		}
+	}
+	fixtures := []settlementFixture{
+		{id: "SETTLE-JAN", status: "paid", modify: between("ORG1", "ORG2", "2026-01-01", "2026-01-31")},
+		{id: "SETTLE-FEB", status: "submitted", modify: between("ORG2", "ORG1", "2026-02-01", "2026-02-28")},
+		{id: "SETTLE-MAR", modify: between("ORG1", "ORG2", "2026-03-01", "2026-03-31")},
+	}
+
+	t.Run("returns settlements where the agency is payor or payee", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+
+		result, err := contract.GetSettlementsInvolvingAgency(ctx, "ORG1", "ORG2", "")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"SETTLE-JAN", "SETTLE-FEB", "SETTLE-MAR"}, settlementIDs(result))
+	})
+
+	t.Run("includes the payee side", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+
+		result, err := contract.GetSettlementsInvolvingAgency(ctx, "ORG2", "ORG1", "")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"SETTLE-JAN", "SETTLE-FEB", "SETTLE-MAR"}, settlementIDs(result))
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+
+		result, err := contract.GetSettlementsInvolvingAgency(ctx, "ORG1", "ORG2", "submitted")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SETTLE-FEB"}, settlementIDs(result))
+		assert.Equal(t, "ORG2", result[0].PayorAgencyID)
+
+		result, err = contract.GetSettlementsInvolvingAgency(ctx, "ORG1", "ORG2", "accepted")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		ctx := newContextWithSettlements(t, fixtures...)
+
+		_, err := contract.GetSettlementsInvolvingAgency(ctx, "ORG1", "ORG2", "bogus")
+		assert.ErrorContains(t, err, `invalid status "bogus"`)
+
+		_, err = contract.GetSettlementsInvolvingAgency(ctx, "ORG1", "ORG1", "")
+		assert.ErrorContains(t, err, "cannot be its own partner")
+
+		_, err = contract.GetSettlementsInvolvingAgency(ctx, "", "ORG2", "")
+		assert.Error(t, err)
+	})
+}
+
 func TestSettlementCollectionNameSymmetry(t *testing.T) {
 	// Settlement collection names must be symmetric like charges
 	s1 := &models.Settlement{
@@ -291,3 +1187,77 @@ func TestSettlementCollectionNameSymmetry(t *testing.T) {
 	assert.Equal(t, s1.CollectionName(), s2.CollectionName())
 	assert.Equal(t, "charges_ORG1_ORG2", s1.CollectionName())
 }
+
+func TestGetSettlementReconciliationReport(t *testing.T) {
+	contract := &SettlementContract{}
+
+	charged := func(away, home, exit string) func(*models.Charge) {
+		return func(c *models.Charge) {
+			c.AwayAgencyID = away
+			c.HomeAgencyID = home
+			c.ExitDateTime = exit
+		}
+	}
+	charges := []chargeFixture{
+		{id: "CHG-1", modify: charged("ORG2", "ORG1", "2026-01-05T08:00:00Z")},
+		{id: "CHG-2", modify: charged("ORG2", "ORG1", "2026-01-10T08:00:00Z")},
+		{id: "CHG-3", modify: charged("ORG1", "ORG2", "2026-01-15T08:00:00Z")},
+		{id: "CHG-4", modify: charged("ORG1", "ORG2", "2026-01-20T08:00:00Z")},
+		{id: "CHG-5", modify: charged("ORG1", "ORG2", "2026-02-03T08:00:00Z")},
+	}
+	postedAt := func(amount float64) func(*models.Reconciliation) {
+		return func(r *models.Reconciliation) { r.PostedAmount = amount }
+	}
+	recons := []reconciliationFixture{
+		{chargeID: "CHG-1", modify: postedAt(4.75)},
+		{chargeID: "CHG-2", modify: postedAt(3.00)},
+		{chargeID: "CHG-3", home: "ORG2", disposition: "I", modify: postedAt(0)},
+		{chargeID: "CHG-5", home: "ORG2", modify: postedAt(4.75)},
+	}
+
+	t.Run("flags mismatched lines in both directions", func(t *testing.T) {
+		ctx := newContextWithCharges(t, charges...)
+		createReconciliations(t, ctx, recons...)
+		report, err := contract.GetSettlementReconciliationReport(ctx, "ORG1", "ORG2", "2026-01-01", "2026-01-31")
+		require.NoError(t, err)
+
+		issues := map[string]string{}
+		for _, line := range report.Lines {
+			issues[line.ChargeID] = line.Issue
+		}
+		assert.Equal(t, map[string]string{
+			"CHG-1": "",
+			"CHG-2": ReportIssueAmountMismatch,
+			"CHG-3": ReportIssueNotPosted,
+			"CHG-4": ReportIssueMissingReconciliation,
+		}, issues)
+		assert.Equal(t, 1, report.MatchedCount)
+		assert.Equal(t, 3, report.IssueCount)
+		assert.InDelta(t, 3.00, report.Lines[1].PostedAmount, 0.0001)
+		for _, line := range report.Lines {
+			assert.Equal(t, 2, line.VehicleClass)
+			assert.Equal(t, "Two-axle passenger vehicle", line.ClassDescription)
+		}
+	})
+
+	t.Run("returns empty report for a period without charges", func(t *testing.T) {
+		ctx := newContextWithCharges(t, charges...)
+		createReconciliations(t, ctx, recons...)
+		report, err := contract.GetSettlementReconciliationReport(ctx, "ORG1", "ORG2", "2026-03-01", "2026-03-31")
+		require.NoError(t, err)
+		assert.NotNil(t, report.Lines)
+		assert.Empty(t, report.Lines)
+	})
+
+	t.Run("rejects invalid period", func(t *testing.T) {
+		ctx := newContextWithCharges(t, charges...)
+		createReconciliations(t, ctx, recons...)
+		_, err := contract.GetSettlementReconciliationReport(ctx, "ORG1", "ORG2", "2026-01-31", "2026-01-01")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not be before")
+
+		_, err = contract.GetSettlementReconciliationReport(ctx, "ORG1", "ORG2", "January", "2026-01-31")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid periodStart")
+	})
+}
@@ -5,6 +5,7 @@ package niop
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -24,7 +25,7 @@ func (c *TagContract) CreateTag(ctx contractapi.TransactionContextInterface, tag
 		return fmt.Errorf("failed to parse tag JSON: %w", err)
 	}
 
-	if err := tag.Validate(); err != nil {
+	if err := validateModel(&tag); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -36,6 +37,57 @@ func (c *TagContract) CreateTag(ctx contractapi.TransactionContextInterface, tag
 		return fmt.Errorf("tag %s already exists", tag.TagSerialNumber)
 	}
 
+	return putNewTag(ctx, &tag)
+}
+
+// CreateTags creates a batch of tags from a JSON array, such as the entries
+// of a tag validation list. The batch is all-or-nothing: every tag is
+// validated and checked for duplicates, both within the batch and on the
+// ledger, before any is written. Errors identify the failing tag by its
+// index in the array. At most MaxBatchSize tags may be sent at once. Returns
+// the number of tags created.
+func (c *TagContract) CreateTags(ctx contractapi.TransactionContextInterface, tagsJSON string) (int, error) {
+	var tags []models.Tag
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return 0, fmt.Errorf("failed to parse tags JSON: %w", err)
+	}
+	if err := checkBatchSize(len(tags)); err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]int, len(tags))
+	for i := range tags {
+		tag := &tags[i]
+		if err := validateModel(tag); err != nil {
+			return 0, fmt.Errorf("tags[%d]: validation failed: %w", i, err)
+		}
+
+		if first, ok := seen[tag.TagSerialNumber]; ok {
+			return 0, fmt.Errorf("tags[%d]: duplicate tagSerialNumber %s (also at index %d)", i, tag.TagSerialNumber, first)
+		}
+		seen[tag.TagSerialNumber] = i
+
+		existing, err := ctx.GetStub().GetState(tag.Key())
+		if err != nil {
+			return 0, fmt.Errorf("failed to read state: %w", err)
+		}
+		if existing != nil {
+			return 0, fmt.Errorf("tags[%d]: tag %s already exists", i, tag.TagSerialNumber)
+		}
+	}
+
+	for i := range tags {
+		if err := putNewTag(ctx, &tags[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(tags), nil
+}
+
+// putNewTag stamps and writes a validated tag along with its agency and
+// account index entries.
+func putNewTag(ctx contractapi.TransactionContextInterface, tag *models.Tag) error {
 	tag.TouchUpdatedAt()
 
 	bytes, err := json.Marshal(tag)
@@ -43,7 +95,14 @@ func (c *TagContract) CreateTag(ctx contractapi.TransactionContextInterface, tag
 		return fmt.Errorf("failed to marshal tag: %w", err)
 	}
 
-	return ctx.GetStub().PutState(tag.Key(), bytes)
+	if err := ctx.GetStub().PutState(tag.Key(), bytes); err != nil {
+		return err
+	}
+
+	if err := putTagAgencyIndex(ctx, tag); err != nil {
+		return err
+	}
+	return putTagAccountIndex(ctx, tag)
 }
 
 // GetTag retrieves a tag by serial number.
@@ -59,7 +118,7 @@ func (c *TagContract) GetTag(ctx contractapi.TransactionContextInterface, tagSer
 	}
 
 	var tag models.Tag
-	if err := json.Unmarshal(bytes, &tag); err != nil {
+	if err := decodeDocument("tag", bytes, &tag); err != nil {
 		return nil, fmt.Errorf("failed to parse tag: %w", err)
 	}
 
@@ -90,17 +149,303 @@ func (c *TagContract) UpdateTagStatus(ctx contractapi.TransactionContextInterfac
 	return ctx.GetStub().PutState(tag.Key(), bytes)
 }
 
+// SkippedTag names a tag a bulk update left unchanged and why.
+type SkippedTag struct {
+	TagSerialNumber string `json:"tagSerialNumber"`
+	Reason          string `json:"reason"`
+}
+
+// AccountTagsStatusUpdate is the result of UpdateTagsStatusByAccount and the
+// payload of its "AccountTagsStatusUpdated" chaincode event.
+type AccountTagsStatusUpdate struct {
+	AccountID   string       `json:"accountID"`
+	TagStatus   string       `json:"tagStatus"`
+	UpdatedTags []string     `json:"updatedTags"`
+	SkippedTags []SkippedTag `json:"skippedTags"`
+}
+
+// UpdateTagsStatusByAccount moves every tag on an account to newStatus in
+// one transaction, for example to invalidate them all when the account is
+// suspended. Tags whose transition to newStatus is not allowed, including
+// tags already in it, are skipped and reported rather than failing the
+// update. Emits an "AccountTagsStatusUpdated" event summarizing the result.
+//
+// The tags are found through the tagByAccount index rather than a rich
+// query. Fabric re-checks a composite key range at validation, so a tag
+// added to the account by a concurrent transaction invalidates this one
+// instead of being silently left out.
+func (c *TagContract) UpdateTagsStatusByAccount(ctx contractapi.TransactionContextInterface, accountID string, newStatus string) (*AccountTagsStatusUpdate, error) {
+	if !models.Contains(models.ValidTagStatuses, newStatus) {
+		return nil, fmt.Errorf("invalid status %q: must be one of %v", newStatus, models.ValidTagStatuses)
+	}
+
+	if accountID == "" {
+		return nil, fmt.Errorf("accountID is required")
+	}
+	tags, err := c.tagsByIndex(ctx, tagByAccountIndex, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountTagsStatusUpdate{
+		AccountID:   accountID,
+		TagStatus:   newStatus,
+		UpdatedTags: []string{},
+		SkippedTags: []SkippedTag{},
+	}
+	for _, tag := range tags {
+		if err := tag.ValidateStatusTransition(newStatus); err != nil {
+			result.SkippedTags = append(result.SkippedTags, SkippedTag{TagSerialNumber: tag.TagSerialNumber, Reason: err.Error()})
+			continue
+		}
+
+		tag.TagStatus = newStatus
+		tag.TouchUpdatedAt()
+
+		bytes, err := json.Marshal(tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tag: %w", err)
+		}
+		if err := ctx.GetStub().PutState(tag.Key(), bytes); err != nil {
+			return nil, err
+		}
+		result.UpdatedTags = append(result.UpdatedTags, tag.TagSerialNumber)
+	}
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := ctx.GetStub().SetEvent("AccountTagsStatusUpdated", payload); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TagHistoryEntry is one version of a tag in its ledger history.
+// Tag is nil for an entry that deleted the tag.
+type TagHistoryEntry struct {
+	TxID      string      `json:"txID"`
+	Timestamp string      `json:"timestamp"`
+	IsDelete  bool        `json:"isDelete"`
+	Tag       *models.Tag `json:"tag,omitempty"`
+}
+
+// GetTagHistory returns every version of a tag written to the ledger, oldest
+// first, so operators can follow its status lifecycle (for example
+// valid, then stolen, then valid again after recovery).
+func (c *TagContract) GetTagHistory(ctx contractapi.TransactionContextInterface, tagSerialNumber string) ([]*TagHistoryEntry, error) {
+	resultsIterator, err := ctx.GetStub().GetHistoryForKey("TAG_" + tagSerialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	history := []*TagHistoryEntry{}
+	for resultsIterator.HasNext() {
+		mod, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		entry := &TagHistoryEntry{TxID: mod.TxId, IsDelete: mod.IsDelete}
+		if ts := mod.GetTimestamp(); ts != nil {
+			entry.Timestamp = time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC().Format(time.RFC3339Nano)
+		}
+		if !mod.IsDelete {
+			var tag models.Tag
+			if err := decodeDocument("tag", mod.Value, &tag); err != nil {
+				return nil, fmt.Errorf("failed to parse tag in transaction %s: %w", mod.TxId, err)
+			}
+			entry.Tag = &tag
+		}
+		history = append(history, entry)
+	}
+
+	// Fabric returns history newest first.
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+
+	return history, nil
+}
+
+// TagCompromisedEvent is the payload of the "TagCompromised" chaincode event.
+// Home agencies listen for it to flag the tag's account for review.
+type TagCompromisedEvent struct {
+	TagSerialNumber string `json:"tagSerialNumber"`
+	TagAgencyID     string `json:"tagAgencyID"`
+	HomeAgencyID    string `json:"homeAgencyID"`
+	AccountID       string `json:"accountID"`
+	TagStatus       string `json:"tagStatus"`
+	Note            string `json:"note,omitempty"`
+}
+
+// ReportTagLostOrStolen marks a tag as lost or stolen, records the operator's
+// note on the tag, and emits a "TagCompromised" event so the home agency can
+// follow up on the linked account.
+func (c *TagContract) ReportTagLostOrStolen(ctx contractapi.TransactionContextInterface, tagSerialNumber string, status string, note string) error {
+	if !models.Contains(models.CompromisedTagStatuses, status) {
+		return fmt.Errorf("invalid status %q: must be one of %v", status, models.CompromisedTagStatuses)
+	}
+
+	tag, err := c.GetTag(ctx, tagSerialNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := tag.ValidateStatusTransition(status); err != nil {
+		return fmt.Errorf("invalid status transition: %w", err)
+	}
+
+	tag.TagStatus = status
+	tag.StatusNote = note
+	tag.TouchUpdatedAt()
+
+	bytes, err := json.Marshal(tag)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag: %w", err)
+	}
+
+	if err := ctx.GetStub().PutState(tag.Key(), bytes); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(TagCompromisedEvent{
+		TagSerialNumber: tag.TagSerialNumber,
+		TagAgencyID:     tag.TagAgencyID,
+		HomeAgencyID:    tag.HomeAgencyID,
+		AccountID:       tag.AccountID,
+		TagStatus:       tag.TagStatus,
+		Note:            note,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return ctx.GetStub().SetEvent("TagCompromised", payload)
+}
+
+// tagByAgencyIndex is the composite key object type indexing tags by issuing agency.
+const tagByAgencyIndex = "tagByAgency"
+
+// putTagAgencyIndex writes the tagByAgency secondary key for a tag.
+// The primary TAG_ record stays the source of truth; the index entry only
+// carries the serial number in its key.
+func putTagAgencyIndex(ctx contractapi.TransactionContextInterface, tag *models.Tag) error {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(tagByAgencyIndex, []string{tag.TagAgencyID, tag.TagSerialNumber})
+	if err != nil {
+		return fmt.Errorf("failed to create index key: %w", err)
+	}
+	return ctx.GetStub().PutState(indexKey, []byte{0x00})
+}
+
+// tagByAccountIndex is the composite key object type indexing tags by account.
+const tagByAccountIndex = "tagByAccount"
+
+// putTagAccountIndex writes the tagByAccount secondary key for a tag. Like
+// tagByAgency, the entry only carries the serial number in its key.
+func putTagAccountIndex(ctx contractapi.TransactionContextInterface, tag *models.Tag) error {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(tagByAccountIndex, []string{tag.AccountID, tag.TagSerialNumber})
+	if err != nil {
+		return fmt.Errorf("failed to create index key: %w", err)
+	}
+	return ctx.GetStub().PutState(indexKey, []byte{0x00})
+}
+
 // GetTagsByAgency returns all tags issued by a specific agency.
-// Uses a CouchDB rich query with index on (docType, tagAgencyID).
+// Reads the tagByAgency composite key index, so the cost depends on the
+// agency's own tag count rather than the total number of tags.
 func (c *TagContract) GetTagsByAgency(ctx contractapi.TransactionContextInterface, tagAgencyID string) ([]*models.Tag, error) {
-	query := fmt.Sprintf(`{"selector":{"docType":"tag","tagAgencyID":"%s"}}`, tagAgencyID)
+	return c.tagsByIndex(ctx, tagByAgencyIndex, tagAgencyID)
+}
+
+// tagsByIndex loads the tags listed under value in a tag composite key index,
+// tagByAgency or tagByAccount.
+func (c *TagContract) tagsByIndex(ctx contractapi.TransactionContextInterface, index string, value string) ([]*models.Tag, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(index, []string{value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag index: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	var tags []*models.Tag
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate: %w", err)
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split index key: %w", err)
+		}
+		if len(attributes) != 2 {
+			continue
+		}
+
+		tag, err := c.GetTag(ctx, attributes[1])
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// TagSortFields lists the tag fields that GetTagsByAgencySorted can sort on.
+// Each has a (docType, tagAgencyID, field) index under
+// META-INF/statedb/couchdb/indexes.
+var TagSortFields = []string{"tagSerialNumber", "accountID", "updatedAt"}
+
+// GetTagsByAgencySorted returns the tags issued by an agency, sorted ascending
+// by sortField and truncated to limit results. Unlike GetTagsByAgency it runs
+// a CouchDB rich query. An empty sortField leaves results unsorted; a limit of
+// 0 returns all matches.
+func (c *TagContract) GetTagsByAgencySorted(ctx contractapi.TransactionContextInterface, tagAgencyID string, sortField string, limit int) ([]*models.Tag, error) {
+	if err := validateSortAndLimit(sortField, TagSortFields, limit); err != nil {
+		return nil, err
+	}
+
+	query, err := newRichQuery("tag", map[string]interface{}{"tagAgencyID": tagAgencyID}).
+		sortAscending(sortField).
+		limit(limit).
+		String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryTags(ctx, query)
+}
+
+// GetTagsByAccount returns all tags on an account, for example to act on
+// every tag when the account is suspended. Uses a CouchDB rich query with
+// index on (docType, accountID). Returns an empty list when none match.
+func (c *TagContract) GetTagsByAccount(ctx contractapi.TransactionContextInterface, accountID string) ([]*models.Tag, error) {
+	if accountID == "" {
+		return nil, fmt.Errorf("accountID is required")
+	}
+
+	query, err := newRichQuery("tag", map[string]interface{}{"accountID": accountID}).String()
+	if err != nil {
+		return nil, err
+	}
+
+	return queryTags(ctx, query)
+}
+
+// queryTags runs a rich query against world state and decodes each result
+// as a tag. Returns an empty list when nothing matches.
+func queryTags(ctx contractapi.TransactionContextInterface, query string) ([]*models.Tag, error) {
 	resultsIterator, err := ctx.GetStub().GetQueryResult(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer resultsIterator.Close()
 
-	var tags []*models.Tag
+	tags := []*models.Tag{}
 	for resultsIterator.HasNext() {
 		queryResponse, err := resultsIterator.Next()
 		if err != nil {
@@ -108,7 +453,7 @@ func (c *TagContract) GetTagsByAgency(ctx contractapi.TransactionContextInterfac
 		}
 
 		var tag models.Tag
-		if err := json.Unmarshal(queryResponse.Value, &tag); err != nil {
+		if err := decodeDocument("tag", queryResponse.Value, &tag); err != nil {
 			return nil, fmt.Errorf("failed to parse tag: %w", err)
 		}
 		tags = append(tags, &tag)
@@ -4,6 +4,7 @@ package niop
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
@@ -24,6 +25,21 @@ func validTag() *models.Tag {
 	}
 }
 
+// newContextWithTags stores a tag on each account in turn, numbering the
+// serials from TEST.000000001.
+func newContextWithTags(t *testing.T, accounts ...string) *enhancedMockContext {
+	t.Helper()
+	ctx := newMockContext()
+	for i, account := range accounts {
+		tag := validTag()
+		tag.TagSerialNumber = fmt.Sprintf("TEST.%09d", i+1)
+		tag.AccountID = account
+		tagJSON, _ := json.Marshal(tag)
+		require.NoError(t, (&TagContract{}).CreateTag(ctx, string(tagJSON)))
+	}
+	return ctx
+}
+
 func TestCreateTag(t *testing.T) {
 	contract := &TagContract{}
 
@@ -93,6 +109,87 @@ func TestCreateTag(t *testing.T) {
 	})
 }
 
+func TestCreateTags(t *testing.T) {
+	contract := &TagContract{}
+
+	batch := func(n int) []*models.Tag {
+		tags := make([]*models.Tag, n)
+		for i := range tags {
+			tags[i] = validTag()
+			tags[i].TagSerialNumber = fmt.Sprintf("TEST.%09d", i+1)
+		}
+		return tags
+	}
+
+	t.Run("creates all valid tags", func(t *testing.T) {
+		ctx := newMockContext()
+		tagsJSON, _ := json.Marshal(batch(3))
+
+		created, err := contract.CreateTags(ctx, string(tagsJSON))
+		require.NoError(t, err)
+		assert.Equal(t, 3, created)
+
+		tags, err := contract.GetTagsByAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		assert.Len(t, tags, 3)
+	})
+
+	t.Run("enforces max batch size", func(t *testing.T) {
+		original := MaxBatchSize
+		MaxBatchSize = 3
+		t.Cleanup(func() { MaxBatchSize = original })
+
+		ctx := newMockContext()
+		tagsJSON, _ := json.Marshal(batch(3))
+		created, err := contract.CreateTags(ctx, string(tagsJSON))
+		require.NoError(t, err)
+		assert.Equal(t, 3, created)
+
+		ctx = newMockContext()
+		tagsJSON, _ = json.Marshal(batch(4))
+		_, err = contract.CreateTags(ctx, string(tagsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "batch exceeds max size 3: got 4 entries")
+	})
+
+	t.Run("rejects whole batch when one tag is invalid", func(t *testing.T) {
+		ctx := newMockContext()
+		tags := batch(3)
+		tags[1].TagStatus = "bogus"
+		tagsJSON, _ := json.Marshal(tags)
+
+		_, err := contract.CreateTags(ctx, string(tagsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tags[1]")
+		assert.Contains(t, err.Error(), "invalid tagStatus")
+
+		_, err = contract.GetTag(ctx, "TEST.000000001")
+		assert.Error(t, err, "no tag should be written when the batch fails")
+	})
+
+	t.Run("rejects duplicate serial within batch", func(t *testing.T) {
+		ctx := newMockContext()
+		tags := batch(3)
+		tags[2].TagSerialNumber = tags[0].TagSerialNumber
+		tagsJSON, _ := json.Marshal(tags)
+
+		_, err := contract.CreateTags(ctx, string(tagsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tags[2]: duplicate tagSerialNumber TEST.000000001 (also at index 0)")
+	})
+
+	t.Run("rejects tag already on the ledger", func(t *testing.T) {
+		ctx := newMockContext()
+		tagJSON, _ := json.Marshal(validTag())
+		require.NoError(t, contract.CreateTag(ctx, string(tagJSON)))
+
+		tagsJSON, _ := json.Marshal(batch(2))
+		_, err := contract.CreateTags(ctx, string(tagsJSON))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tags[0]: tag TEST.000000001 already exists")
+	})
+}
+
 func TestGetTag(t *testing.T) {
 	contract := &TagContract{}
 
@@ -185,6 +282,106 @@ func TestUpdateTagStatus(t *testing.T) {
 	})
 }
 
+func TestGetTagHistory(t *testing.T) {
+	contract := &TagContract{}
+
+	t.Run("returns status transitions in order", func(t *testing.T) {
+		ctx := newMockContext()
+		tagJSON, _ := json.Marshal(validTag())
+		ctx.stub.MockTransactionStart("tx-create")
+		require.NoError(t, contract.CreateTag(ctx, string(tagJSON)))
+		ctx.stub.MockTransactionStart("tx-stolen")
+		require.NoError(t, contract.UpdateTagStatus(ctx, "TEST.000000001", "stolen"))
+		ctx.stub.MockTransactionStart("tx-recovered")
+		require.NoError(t, contract.UpdateTagStatus(ctx, "TEST.000000001", "valid"))
+
+		history, err := contract.GetTagHistory(ctx, "TEST.000000001")
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+
+		wantTxIDs := []string{"tx-create", "tx-stolen", "tx-recovered"}
+		wantStatuses := []string{"valid", "stolen", "valid"}
+		for i, entry := range history {
+			assert.Equal(t, wantTxIDs[i], entry.TxID)
+			assert.NotEmpty(t, entry.Timestamp)
+			assert.False(t, entry.IsDelete)
+			require.NotNil(t, entry.Tag)
+			assert.Equal(t, wantStatuses[i], entry.Tag.TagStatus)
+		}
+	})
+
+	t.Run("includes deletes", func(t *testing.T) {
+		ctx := newMockContext()
+		tagJSON, _ := json.Marshal(validTag())
+		require.NoError(t, contract.CreateTag(ctx, string(tagJSON)))
+		ctx.stub.MockTransactionStart("tx-delete")
+		require.NoError(t, ctx.stub.DelState("TAG_TEST.000000001"))
+
+		history, err := contract.GetTagHistory(ctx, "TEST.000000001")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.True(t, history[1].IsDelete)
+		assert.Nil(t, history[1].Tag)
+		assert.Equal(t, "tx-delete", history[1].TxID)
+	})
+
+	t.Run("unknown tag has empty history", func(t *testing.T) {
+		ctx := newMockContext()
+		history, err := contract.GetTagHistory(ctx, "TEST.404")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+}
+
+func TestReportTagLostOrStolen(t *testing.T) {
+	contract := &TagContract{}
+
+	for _, status := range []string{"lost", "stolen"} {
+		t.Run("reports "+status, func(t *testing.T) {
+			ctx := newContextWithTags(t, "A000000001")
+			require.NoError(t, contract.ReportTagLostOrStolen(ctx, "TEST.000000001", status, "Reported by customer"))
+
+			tag, err := contract.GetTag(ctx, "TEST.000000001")
+			require.NoError(t, err)
+			assert.Equal(t, status, tag.TagStatus)
+			assert.Equal(t, "Reported by customer", tag.StatusNote)
+			assert.NotEmpty(t, tag.UpdatedAt)
+
+			require.Len(t, ctx.stub.events, 1)
+			assert.Equal(t, "TagCompromised", ctx.stub.events[0].name)
+			var event TagCompromisedEvent
+			require.NoError(t, json.Unmarshal(ctx.stub.events[0].payload, &event))
+			assert.Equal(t, "TEST.000000001", event.TagSerialNumber)
+			assert.Equal(t, status, event.TagStatus)
+			assert.Equal(t, tag.AccountID, event.AccountID)
+		})
+	}
+
+	t.Run("rejects other statuses", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001")
+		err := contract.ReportTagLostOrStolen(ctx, "TEST.000000001", "inactive", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+		assert.Empty(t, ctx.stub.events)
+	})
+
+	t.Run("rejects disallowed transition", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001")
+		require.NoError(t, contract.ReportTagLostOrStolen(ctx, "TEST.000000001", "lost", ""))
+
+		err := contract.ReportTagLostOrStolen(ctx, "TEST.000000001", "stolen", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status transition")
+	})
+
+	t.Run("returns error for nonexistent tag", func(t *testing.T) {
+		ctx := newMockContext()
+		err := contract.ReportTagLostOrStolen(ctx, "NONEXISTENT", "lost", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
 func TestGetTagsByAgency(t *testing.T) {
 	contract := &TagContract{}
 
@@ -221,4 +418,176 @@ func TestGetTagsByAgency(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 	})
+
+	t.Run("reads only the agency's own tags", func(t *testing.T) {
+		ctx := newMockContext()
+
+		tag := validTag()
+		tagJSON, _ := json.Marshal(tag)
+		require.NoError(t, contract.CreateTag(ctx, string(tagJSON)))
+
+		for i := 0; i < 50; i++ {
+			other := validTag()
+			other.TagSerialNumber = fmt.Sprintf("OTHER.%09d", i)
+			other.TagAgencyID = "ORG2"
+			otherJSON, _ := json.Marshal(other)
+			require.NoError(t, contract.CreateTag(ctx, string(otherJSON)))
+		}
+
+		ctx.stub.stateReads = 0
+		result, err := contract.GetTagsByAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "TEST.000000001", result[0].TagSerialNumber)
+		assert.Equal(t, 1, ctx.stub.stateReads)
+	})
+
+	t.Run("ignores tags without an index entry", func(t *testing.T) {
+		ctx := newMockContext()
+		tagJSON, _ := json.Marshal(validTag())
+		require.NoError(t, ctx.stub.PutState("TAG_TEST.000000001", tagJSON))
+
+		result, err := contract.GetTagsByAgency(ctx, "ORG1")
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestGetTagsByAgencySorted(t *testing.T) {
+	contract := &TagContract{}
+
+	// Tags TEST.000000001 to TEST.000000003 on these accounts.
+	accounts := []string{"A000000003", "A000000001", "A000000002"}
+
+	t.Run("sorts by account", func(t *testing.T) {
+		ctx := newContextWithTags(t, accounts...)
+		result, err := contract.GetTagsByAgencySorted(ctx, "ORG1", "accountID", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST.000000002", "TEST.000000003", "TEST.000000001"}, tagSerials(result))
+	})
+
+	t.Run("sorts and truncates", func(t *testing.T) {
+		ctx := newContextWithTags(t, accounts...)
+		result, err := contract.GetTagsByAgencySorted(ctx, "ORG1", "accountID", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST.000000002", "TEST.000000003"}, tagSerials(result))
+	})
+
+	t.Run("unsorted and unlimited by default", func(t *testing.T) {
+		ctx := newContextWithTags(t, accounts...)
+		other := validTag()
+		other.TagSerialNumber = "OTHER.000000001"
+		other.TagAgencyID = "ORG2"
+		otherJSON, _ := json.Marshal(other)
+		require.NoError(t, contract.CreateTag(ctx, string(otherJSON)))
+
+		result, err := contract.GetTagsByAgencySorted(ctx, "ORG1", "", 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"TEST.000000001", "TEST.000000002", "TEST.000000003"}, tagSerials(result))
+	})
+
+	t.Run("rejects invalid sort and limit", func(t *testing.T) {
+		ctx := newMockContext()
+		_, err := contract.GetTagsByAgencySorted(ctx, "ORG1", "tagClass", 0)
+		assert.ErrorContains(t, err, "invalid sortField")
+
+		_, err = contract.GetTagsByAgencySorted(ctx, "ORG1", "accountID", -1)
+		assert.ErrorContains(t, err, "limit must be >= 0")
+	})
+}
+
+func TestGetTagsByAccount(t *testing.T) {
+	contract := &TagContract{}
+
+	t.Run("returns every tag on the account", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001", "A000000002", "A000000001", "A000000003")
+		tags, err := contract.GetTagsByAccount(ctx, "A000000001")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"TEST.000000001", "TEST.000000003"}, tagSerials(tags))
+	})
+
+	t.Run("returns empty slice for an account without tags", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001", "A000000002", "A000000001", "A000000003")
+		tags, err := contract.GetTagsByAccount(ctx, "A999999999")
+		require.NoError(t, err)
+		assert.NotNil(t, tags)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("requires accountID", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001", "A000000002", "A000000001", "A000000003")
+		_, err := contract.GetTagsByAccount(ctx, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "accountID is required")
+	})
+}
+
+func TestUpdateTagsStatusByAccount(t *testing.T) {
+	contract := &TagContract{}
+
+	t.Run("updates tags that can transition and reports the rest", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001", "A000000001", "A000000001", "A000000002")
+		// inactive -> lost is not an allowed transition.
+		require.NoError(t, contract.UpdateTagStatus(ctx, "TEST.000000003", "inactive"))
+
+		result, err := contract.UpdateTagsStatusByAccount(ctx, "A000000001", "lost")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"TEST.000000001", "TEST.000000002"}, result.UpdatedTags)
+		require.Len(t, result.SkippedTags, 1)
+		assert.Equal(t, "TEST.000000003", result.SkippedTags[0].TagSerialNumber)
+		assert.Contains(t, result.SkippedTags[0].Reason, "cannot transition")
+
+		for serial, want := range map[string]string{
+			"TEST.000000001": "lost",
+			"TEST.000000002": "lost",
+			"TEST.000000003": "inactive",
+			"TEST.000000004": "valid",
+		} {
+			tag, err := contract.GetTag(ctx, serial)
+			require.NoError(t, err)
+			assert.Equal(t, want, tag.TagStatus, serial)
+		}
+
+		event := ctx.stub.events[len(ctx.stub.events)-1]
+		assert.Equal(t, "AccountTagsStatusUpdated", event.name)
+		var payload AccountTagsStatusUpdate
+		require.NoError(t, json.Unmarshal(event.payload, &payload))
+		assert.Equal(t, "A000000001", payload.AccountID)
+		assert.Len(t, payload.UpdatedTags, 2)
+		assert.Len(t, payload.SkippedTags, 1)
+	})
+
+	t.Run("finds tags through the account index", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001")
+		indexKey, err := ctx.stub.CreateCompositeKey(tagByAccountIndex, []string{"A000000001", "TEST.000000001"})
+		require.NoError(t, err)
+		entry, err := ctx.stub.GetState(indexKey)
+		require.NoError(t, err)
+		assert.NotNil(t, entry)
+
+		// A tag written before the index existed is picked up once
+		// migration backfills its entry.
+		legacy := validTag()
+		legacy.TagSerialNumber = "TEST.000000002"
+		legacyJSON, _ := json.Marshal(legacy)
+		require.NoError(t, ctx.stub.PutState(legacy.Key(), legacyJSON))
+
+		result, err := contract.UpdateTagsStatusByAccount(ctx, "A000000001", "lost")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST.000000001"}, result.UpdatedTags)
+
+		_, err = (&MigrationContract{}).MigrateCollection(ctx, "")
+		require.NoError(t, err)
+		result, err = contract.UpdateTagsStatusByAccount(ctx, "A000000001", "lost")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST.000000002"}, result.UpdatedTags)
+	})
+
+	t.Run("rejects invalid status", func(t *testing.T) {
+		ctx := newContextWithTags(t, "A000000001")
+		_, err := contract.UpdateTagsStatusByAccount(ctx, "A000000001", "suspended")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+	})
 }
@@ -0,0 +1,47 @@
+// Copyright 2016-2026 Milligan Partners LLC. Apache-2.0 license.
+
+package niop
+
+import (
+	"fmt"
+
+	"github.com/milligan-partners/tolling.network-2.0/chaincode/niop/models"
+)
+
+// validatable is a model that can report its first validation failure or
+// all of them.
+type validatable interface {
+	Validate() error
+	ValidateAll() []models.ValidationError
+}
+
+// validateModel validates v. It returns the first failure, or, when
+// ReportAllValidationErrors is set, every failure as a
+// models.ValidationErrors.
+func validateModel(v validatable) error {
+	if !CurrentConfig().ReportAllValidationErrors {
+		return v.Validate()
+	}
+	if errs := v.ValidateAll(); len(errs) > 0 {
+		return models.ValidationErrors(errs)
+	}
+	return nil
+}
+
+// DefaultMaxBatchSize is the value of MaxBatchSize unless overridden.
+const DefaultMaxBatchSize = 500
+
+// MaxBatchSize caps the number of entries a batch transaction such as
+// CreateTags or GetChargesByIDs accepts in one call. It keeps a transaction's
+// read and write sets, and so its proposal response and block, well inside
+// Fabric's size limits. Override it with MaxBatchSizeFromEnv during startup,
+// before any transaction is served.
+var MaxBatchSize = DefaultMaxBatchSize
+
+// checkBatchSize rejects a batch of more than MaxBatchSize entries.
+func checkBatchSize(n int) error {
+	if n > MaxBatchSize {
+		return fmt.Errorf("batch exceeds max size %d: got %d entries", MaxBatchSize, n)
+	}
+	return nil
+}
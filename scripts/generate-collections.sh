@@ -9,6 +9,10 @@
 # Collection names use alphabetical sorting (smaller org first) so both
 # orgs in a pair resolve to the same collection name.
 #
+# niop.GenerateCollectionsConfig produces the same output from Go, and also
+# writes the dual endorsement policy of pairs flagged with
+# NIOP_DUAL_ENDORSEMENT_PAIRS.
+#
 # Usage:
 #   ./generate-collections.sh Org1 Org2 Org3 Org4
 #   ./generate-collections.sh -o output.json Org1 Org2 Org3